@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/display"
+	jsonout "github.com/chadmayfield/tempest-cli/internal/json"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const defaultBarTTL = 2 * time.Minute
+
+var barCmd = &cobra.Command{
+	Use:   "bar",
+	Short: "Print a compact summary for status bars",
+	Long: `Print current conditions as a single line for tmux, waybar, i3bar and similar.
+
+Results are cached on disk so that frequent polling only contacts the API
+once per --ttl. If a refresh fails, the last cached result is shown and
+marked stale.
+
+Formats:
+  text    single line (default)
+  waybar  JSON object with text, tooltip and class (normal, warning, critical, stale)
+  i3bar   JSON block with full_text, short_text, color and urgent`,
+	RunE: runBar,
+}
+
+func init() {
+	barCmd.Flags().String("format", "text", "output format: text, waybar, or i3bar")
+	barCmd.Flags().Duration("ttl", defaultBarTTL, "how long cached results are reused before refreshing")
+	rootCmd.AddCommand(barCmd)
+
+	currentCmd.Flags().Bool("oneline", false, "print a compact single-line summary (same as 'tempest bar')")
+}
+
+// barSnapshot is the cached payload for the bar command.
+type barSnapshot struct {
+	Observation *tempest.StationObservation `json:"observation"`
+	Station     *tempest.Station            `json:"station"`
+	Source      dataSource                  `json:"source"`
+}
+
+type waybarOutput struct {
+	Text    string   `json:"text"`
+	Tooltip string   `json:"tooltip"`
+	Class   []string `json:"class"`
+}
+
+type i3barOutput struct {
+	Name      string `json:"name"`
+	FullText  string `json:"full_text"`
+	ShortText string `json:"short_text"`
+	Color     string `json:"color,omitempty"`
+	Urgent    bool   `json:"urgent"`
+}
+
+func runBar(cmd *cobra.Command, args []string) error {
+	format := "text"
+	ttl := defaultBarTTL
+	if f := cmd.Flags().Lookup("format"); f != nil {
+		format = f.Value.String()
+	}
+	if cmd.Flags().Lookup("ttl") != nil {
+		ttl, _ = cmd.Flags().GetDuration("ttl")
+	}
+	if format != "text" && format != "waybar" && format != "i3bar" {
+		return usageError(fmt.Errorf("invalid --format %q: must be text, waybar, or i3bar", format))
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return wrapConfigError(err)
+	}
+
+	sc, err := cfg.ResolveStation(viper.GetString("station"))
+	if err != nil {
+		return wrapConfigError(err)
+	}
+
+	snap, fetchedAt, stale, err := loadBarSnapshot(cmd, cfg, sc, ttl)
+	if err != nil {
+		if format != "text" {
+			_ = writeBarError(cmd, format, err)
+		}
+		return wrapAPIError(err)
+	}
+
+	// The observation itself may be old however recently it was fetched.
+	obs := snap.Observation
+	if time.Since(obs.Timestamp) > cfg.StaleThreshold(sc) {
+		stale = true
+	}
+
+	u, err := resolveUnits(cfg, sc)
+	if err != nil {
+		return err
+	}
+	loc, err := resolveLocale()
+	if err != nil {
+		return err
+	}
+	noEmoji := viper.GetBool("no-emoji")
+	text := display.RenderBarText(obs, u, loc, noEmoji)
+	level := display.ConditionsAlert(obs)
+
+	switch format {
+	case "waybar":
+		name := sc.Name
+		if snap.Station != nil && snap.Station.Name != "" {
+			name = snap.Station.Name
+		}
+		class := []string{string(level)}
+		if stale {
+			class = append(class, "stale")
+		}
+		tooltip := display.RenderBarTooltip(obs, name, u, loc, fetchedAt)
+		if snap.Source != "" {
+			tooltip += "\n" + loc.T("tooltip_source", snap.Source)
+		}
+		return jsonout.WriteCompact(cmd.OutOrStdout(), waybarOutput{
+			Text:    text,
+			Tooltip: tooltip,
+			Class:   class,
+		})
+	case "i3bar":
+		return jsonout.WriteCompact(cmd.OutOrStdout(), i3barOutput{
+			Name:      "tempest",
+			FullText:  text,
+			ShortText: display.RenderBarShortText(obs, u, loc),
+			Color:     i3barColor(level, stale),
+			Urgent:    level == display.AlertCritical,
+		})
+	default:
+		if stale {
+			text = loc.T("stale_suffix", text)
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), text)
+		return nil
+	}
+}
+
+// loadBarSnapshot returns cached data when it is younger than ttl, otherwise
+// refreshes it. If the refresh fails, any older cached data is returned as stale.
+func loadBarSnapshot(cmd *cobra.Command, cfg *config.Config, sc *config.StationConfig, ttl time.Duration) (*barSnapshot, time.Time, bool, error) {
+	serverURL := resolveServerURL(cfg)
+	key := cloudCacheKey("bar", sc)
+	if serverURL != "" {
+		key = cacheKey("bar", serverURL, sc.StationID)
+	}
+
+	store := openCache()
+
+	var snap barSnapshot
+	if store != nil && !viper.GetBool("refresh") {
+		if fetchedAt, ok := store.Get(key, ttl, &snap); ok && snap.Observation != nil {
+			slog.Debug("bar cache hit", "key", key, "age", time.Since(fetchedAt))
+			return &snap, fetchedAt, false, nil
+		}
+	}
+
+	data, meta, err := fetchCurrent(cmd.Context(), cfg, sc)
+	if err != nil {
+		if store != nil {
+			var old barSnapshot
+			if fetchedAt, ok := store.Get(key, 0, &old); ok && old.Observation != nil {
+				slog.Debug("bar refresh failed, using stale cache", "error", err)
+				return &old, fetchedAt, true, nil
+			}
+		}
+		return nil, time.Time{}, false, err
+	}
+
+	snap = barSnapshot{Observation: data.Observation, Station: data.Station, Source: meta.Source}
+	if store != nil && !meta.Stale {
+		if err := store.Put(key, snap); err != nil {
+			slog.Debug("writing bar cache", "error", err)
+		}
+	}
+	return &snap, meta.FetchedAt, meta.Stale, nil
+}
+
+func writeBarError(cmd *cobra.Command, format string, err error) error {
+	if format == "i3bar" {
+		return jsonout.WriteCompact(cmd.OutOrStdout(), i3barOutput{
+			Name:      "tempest",
+			FullText:  "tempest: error",
+			ShortText: "err",
+			Color:     i3barColor(display.AlertCritical, false),
+		})
+	}
+	return jsonout.WriteCompact(cmd.OutOrStdout(), waybarOutput{
+		Text:    "tempest: error",
+		Tooltip: err.Error(),
+		Class:   []string{"error"},
+	})
+}
+
+func i3barColor(level display.AlertLevel, stale bool) string {
+	switch {
+	case stale:
+		return "#666666"
+	case level == display.AlertCritical:
+		return "#ff4444"
+	case level == display.AlertWarning:
+		return "#ffaa00"
+	default:
+		return ""
+	}
+}
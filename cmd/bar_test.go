@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/display"
+	"github.com/chadmayfield/tempest-cli/internal/fakeserver"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// setupBarTest points bar at a fake tempestd with the on-disk cache
+// enabled. Only bar's own cache is fresh for longer than a moment, and
+// changing tempestd.token afterwards makes every refresh fail.
+func setupBarTest(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	out := setupFakeServer(t, barCmd, fakeserver.Options{Token: "fake"}, "tempestd", "cache")
+	viper.Set("tempestd.token", "fake")
+	viper.Set("json", false)
+	viper.Set("no-cache", false)
+	viper.Set("cache.dir", t.TempDir())
+	viper.Set("cache.current_ttl", time.Nanosecond)
+	viper.Set("cache.station_ttl", time.Nanosecond)
+	viper.Set("units", "metric")
+	return out
+}
+
+func TestRunBarWaybarUsesCache(t *testing.T) {
+	out := setupBarTest(t)
+	_ = barCmd.Flags().Set("format", "waybar")
+	defer func() { _ = barCmd.Flags().Set("format", "text") }()
+
+	for i := 0; i < 3; i++ {
+		if err := runBar(barCmd, nil); err != nil {
+			t.Fatalf("runBar() error: %v", err)
+		}
+
+		var got waybarOutput
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("invalid waybar JSON %q: %v", out.String(), err)
+		}
+		out.Reset()
+		if !strings.Contains(got.Text, "°C") {
+			t.Errorf("text = %q, want temperature", got.Text)
+		}
+		if !strings.Contains(got.Tooltip, "Fake Home") {
+			t.Errorf("tooltip = %q, want station name", got.Tooltip)
+		}
+		// A refresh would fail and fall back to stale data.
+		if len(got.Class) != 1 {
+			t.Errorf("class = %v, want fresh cached data", got.Class)
+		}
+
+		// Only the first call should reach the server.
+		viper.Set("tempestd.token", "wrong")
+	}
+}
+
+func TestRunBarStaleFallback(t *testing.T) {
+	out := setupBarTest(t)
+	_ = barCmd.Flags().Set("format", "text")
+
+	if err := runBar(barCmd, nil); err != nil {
+		t.Fatalf("runBar() error: %v", err)
+	}
+	out.Reset()
+
+	// Force a refresh that the server refuses.
+	viper.Set("tempestd.token", "wrong")
+	viper.Set("refresh", true)
+
+	if err := runBar(barCmd, nil); err != nil {
+		t.Fatalf("runBar() should fall back to cache, got error: %v", err)
+	}
+	if !strings.Contains(out.String(), "(stale)") {
+		t.Errorf("expected stale marker, got %q", out.String())
+	}
+}
+
+func TestRunBarInvalidFormat(t *testing.T) {
+	_ = barCmd.Flags().Set("format", "xml")
+	defer func() { _ = barCmd.Flags().Set("format", "text") }()
+	if err := runBar(barCmd, nil); err == nil {
+		t.Error("expected error for invalid format")
+	}
+}
+
+func TestI3barColor(t *testing.T) {
+	if c := i3barColor(display.AlertNormal, false); c != "" {
+		t.Errorf("normal color = %q, want empty", c)
+	}
+	if c := i3barColor(display.AlertCritical, true); c != "#666666" {
+		t.Errorf("stale color = %q, want muted", c)
+	}
+}
+
+func TestRunBarStaleAfter(t *testing.T) {
+	// The fake sensors stopped reporting two hours ago.
+	out := setupFakeServer(t, barCmd, fakeserver.Options{Scenario: fakeserver.DeadBattery}, "tempestd")
+	_ = barCmd.Flags().Set("format", "text")
+
+	if err := runBar(barCmd, nil); err != nil {
+		t.Fatalf("runBar() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "(stale)") {
+		t.Errorf("output = %q, want stale after the default 30m", out.String())
+	}
+
+	out.Reset()
+	viper.Set("lang", "de")
+	if err := runBar(barCmd, nil); err != nil {
+		t.Fatalf("runBar() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "(veraltet)") {
+		t.Errorf("output = %q, want the stale marker translated", out.String())
+	}
+	viper.Set("lang", "")
+
+	out.Reset()
+	viper.Set("stations.home.stale_after", "3h")
+	if err := runBar(barCmd, nil); err != nil {
+		t.Fatalf("runBar() error: %v", err)
+	}
+	if strings.Contains(out.String(), "(stale)") {
+		t.Errorf("output = %q, want fresh within the station's stale_after", out.String())
+	}
+}
+
+func TestCurrentOnelineRejectsConflictingFlags(t *testing.T) {
+	for _, flag := range []string{"all", "format"} {
+		t.Run(flag, func(t *testing.T) {
+			cmd := &cobra.Command{RunE: runCurrent}
+			cmd.Flags().Bool("oneline", false, "")
+			cmd.Flags().Bool("all", false, "")
+			addFormatFlag(cmd)
+			_ = cmd.Flags().Set("oneline", "true")
+			value := "true"
+			if flag == "format" {
+				value = "markdown"
+			}
+			_ = cmd.Flags().Set(flag, value)
+
+			err := runCurrent(cmd, nil)
+			if errorKind(err) != KindUsage || !strings.Contains(err.Error(), "--"+flag) {
+				t.Errorf("error = %v, want usage error naming --%s", err, flag)
+			}
+		})
+	}
+}
+
+func TestRunBarCacheKeyedByToken(t *testing.T) {
+	setupFakeServer(t, barCmd, fakeserver.Options{Token: "fake"}, "cloud")
+	viper.Set("server", "")
+	viper.Set("no-cache", false)
+	viper.Set("cache.dir", t.TempDir())
+	_ = barCmd.Flags().Set("format", "text")
+
+	if err := runBar(barCmd, nil); err != nil {
+		t.Fatalf("runBar() error: %v", err)
+	}
+
+	// Another token must not be served the first token's cached summary.
+	viper.Set("stations.home.token", "other")
+	if err := runBar(barCmd, nil); errorKind(err) != KindAuth {
+		t.Errorf("error = %v, want auth error for the new token", err)
+	}
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/cache"
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	jsonout "github.com/chadmayfield/tempest-cli/internal/json"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Default response cache TTLs, overridable under the cache: config block.
+const (
+	defaultCurrentTTL  = time.Minute
+	defaultForecastTTL = 30 * time.Minute
+	defaultStationTTL  = 24 * time.Hour
+
+	// historyClosedGrace is how far in the past a history window must end before
+	// it is considered closed and cached permanently. Late uploads from the hub
+	// can still land within this window.
+	historyClosedGrace = time.Hour
+
+	// cacheLockWait bounds how long a process waits for another one that is
+	// already refreshing the same entry.
+	cacheLockWait = 15 * time.Second
+)
+
+// ttlNoCache tells cachedFetch to bypass the cache for a request.
+const ttlNoCache time.Duration = -1
+
+// ttlForever caches a response with no expiry.
+const ttlForever time.Duration = 0
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the response cache",
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show response cache statistics",
+	RunE:  runCacheStats,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached responses",
+	RunE:  runCacheClear,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+// openCache returns the response cache, or nil if it is disabled or unavailable.
+func openCache() *cache.Store {
+	if viper.GetBool("no-cache") {
+		return nil
+	}
+	return cacheStore()
+}
+
+// cacheStore returns the store at cache.dir or the default location. The cache
+// subcommands use it directly so they work even with --no-cache.
+func cacheStore() *cache.Store {
+	if dir := viper.GetString("cache.dir"); dir != "" {
+		return cache.New(dir)
+	}
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		slog.Debug("response cache unavailable", "error", err)
+		return nil
+	}
+	return cache.New(dir)
+}
+
+// cacheTTL returns the configured TTL for a cache.<name>_ttl key, or def.
+func cacheTTL(name string, def time.Duration) time.Duration {
+	key := "cache." + name + "_ttl"
+	if !viper.IsSet(key) {
+		return def
+	}
+	return viper.GetDuration(key)
+}
+
+func currentTTL() time.Duration  { return cacheTTL("current", defaultCurrentTTL) }
+func forecastTTL() time.Duration { return cacheTTL("forecast", defaultForecastTTL) }
+func stationTTL() time.Duration  { return cacheTTL("station", defaultStationTTL) }
+
+// historyTTL caches closed windows forever and skips the cache for windows
+// that are still filling in, since their keys change on every run.
+func historyTTL(end time.Time) time.Duration {
+	if end.Before(time.Now().Add(-historyClosedGrace)) {
+		return ttlForever
+	}
+	return ttlNoCache
+}
+
+// cacheKey builds a response cache key. The endpoint comes first so that
+// 'tempest cache stats' can group entries by it.
+func cacheKey(endpoint, source string, stationID int, params ...string) string {
+	key := fmt.Sprintf("%s|%s|%d", endpoint, source, stationID)
+	for _, p := range params {
+		key += "|" + p
+	}
+	return key
+}
+
+// cloudCacheKey builds a cache key for a cloud API response for sc. The key
+// identifies the token without resolving it, so that a changed token or a
+// second account for the same station never gets the other's cached data.
+func cloudCacheKey(endpoint string, sc *config.StationConfig, params ...string) string {
+	id := sc.TokenSource()
+	if sc.Token != "" {
+		id = sc.Token
+	}
+	sum := sha256.Sum256([]byte(id))
+	return cacheKey(endpoint, "cloud", sc.StationID, append([]string{"token=" + hex.EncodeToString(sum[:6])}, params...)...)
+}
+
+// cachedFetch returns the cached value for key if it is younger than ttl,
+// otherwise calls fetch and stores the result. A ttl of ttlForever never
+// expires and ttlNoCache bypasses the cache. --refresh skips the read but
+// still stores the fresh result; --no-cache disables the cache entirely.
+//
+// Concurrent processes refreshing the same key serialize on a lock file so
+// that only one of them hits the network.
+func cachedFetch[T any](ctx context.Context, key string, ttl time.Duration, fetch func() (*T, error)) (*T, error) {
+	store := openCache()
+	if cacheOnly(ctx) {
+		return cachedOnly[T](ctx, store, key)
+	}
+	if store == nil || ttl < 0 {
+		return recordFetch(ctx, time.Now(), fetch)
+	}
+	refresh := viper.GetBool("refresh")
+
+	var v T
+	if !refresh {
+		if fetchedAt, ok := store.Get(key, ttl, &v); ok {
+			slog.Debug("cache hit", "key", key)
+			recordFetchedAt(ctx, fetchedAt)
+			return &v, nil
+		}
+	}
+
+	lockCtx, cancel := context.WithTimeout(ctx, cacheLockWait)
+	defer cancel()
+	unlock, err := store.Lock(lockCtx, key)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		slog.Debug("cache lock unavailable, fetching without it", "key", key, "error", err)
+	} else {
+		defer unlock()
+		// Another process may have refreshed the entry while we waited.
+		if !refresh {
+			if fetchedAt, ok := store.Get(key, ttl, &v); ok {
+				slog.Debug("cache hit after lock", "key", key)
+				recordFetchedAt(ctx, fetchedAt)
+				return &v, nil
+			}
+		}
+	}
+
+	slog.Debug("cache miss", "key", key)
+	result, err := recordFetch(ctx, time.Now(), fetch)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Put(key, result); err != nil {
+		slog.Debug("writing cache entry", "key", key, "error", err)
+	}
+	return result, nil
+}
+
+// cachedOnly serves key from the cache regardless of its age, without
+// touching the network. It backs the "cache" data source.
+func cachedOnly[T any](ctx context.Context, store *cache.Store, key string) (*T, error) {
+	if store == nil {
+		return nil, errNotCached
+	}
+	var v T
+	fetchedAt, ok := store.Get(key, 0, &v)
+	if !ok {
+		return nil, errNotCached
+	}
+	slog.Debug("serving stale cache entry", "key", key, "age", time.Since(fetchedAt))
+	recordFetchedAt(ctx, fetchedAt)
+	return &v, nil
+}
+
+func recordFetch[T any](ctx context.Context, at time.Time, fetch func() (*T, error)) (*T, error) {
+	v, err := fetch()
+	if err == nil {
+		recordFetchedAt(ctx, at)
+	}
+	return v, err
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	store := cacheStore()
+	if store == nil {
+		return fmt.Errorf("cannot determine cache directory")
+	}
+	st, err := store.Stats()
+	if err != nil {
+		return err
+	}
+
+	if viper.GetBool("json") {
+		return jsonout.Write(cmd.OutOrStdout(), st)
+	}
+
+	w := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(w, "Cache directory: %s\n", st.Dir)
+	_, _ = fmt.Fprintf(w, "Entries:         %d\n", st.Entries)
+	_, _ = fmt.Fprintf(w, "Size:            %s\n", formatBytes(st.Bytes))
+	if st.Entries > 0 {
+		_, _ = fmt.Fprintf(w, "Oldest:          %s\n", st.Oldest.Local().Format(time.DateTime))
+		_, _ = fmt.Fprintf(w, "Newest:          %s\n", st.Newest.Local().Format(time.DateTime))
+		_, _ = fmt.Fprintln(w)
+		for _, name := range st.Endpoints() {
+			_, _ = fmt.Fprintf(w, "  %-10s %d\n", name, st.ByEndpoint[name])
+		}
+	}
+	return nil
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	store := cacheStore()
+	if store == nil {
+		return fmt.Errorf("cannot determine cache directory")
+	}
+	n, err := store.Clear()
+	if err != nil {
+		return err
+	}
+	if viper.GetBool("json") {
+		return jsonout.Write(cmd.OutOrStdout(), map[string]any{"dir": store.Dir(), "removed": n})
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Removed %d cached responses from %s\n", n, store.Dir())
+	return nil
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
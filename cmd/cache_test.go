@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/spf13/viper"
+)
+
+func setupCacheTest(t *testing.T) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("cache.dir", t.TempDir())
+}
+
+type cachedThing struct {
+	N int
+}
+
+func TestCachedFetch(t *testing.T) {
+	setupCacheTest(t)
+	ctx := context.Background()
+
+	calls := 0
+	fetch := func() (*cachedThing, error) {
+		calls++
+		return &cachedThing{N: calls}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		got, err := cachedFetch(ctx, "current|cloud|1", time.Minute, fetch)
+		if err != nil {
+			t.Fatalf("cachedFetch() error: %v", err)
+		}
+		if got.N != 1 {
+			t.Errorf("call %d: N = %d, want 1 (cached)", i, got.N)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+
+	// --refresh bypasses the read but updates the entry.
+	viper.Set("refresh", true)
+	got, _ := cachedFetch(ctx, "current|cloud|1", time.Minute, fetch)
+	if got.N != 2 {
+		t.Errorf("refresh: N = %d, want 2", got.N)
+	}
+	viper.Set("refresh", false)
+	got, _ = cachedFetch(ctx, "current|cloud|1", time.Minute, fetch)
+	if got.N != 2 {
+		t.Errorf("after refresh: N = %d, want 2 (refreshed value cached)", got.N)
+	}
+
+	// --no-cache always fetches.
+	viper.Set("no-cache", true)
+	got, _ = cachedFetch(ctx, "current|cloud|1", time.Minute, fetch)
+	if got.N != 3 {
+		t.Errorf("no-cache: N = %d, want 3", got.N)
+	}
+}
+
+func TestCachedFetchErrorNotCached(t *testing.T) {
+	setupCacheTest(t)
+	ctx := context.Background()
+
+	boom := errors.New("boom")
+	if _, err := cachedFetch(ctx, "k", time.Minute, func() (*cachedThing, error) { return nil, boom }); !errors.Is(err, boom) {
+		t.Fatalf("expected fetch error, got %v", err)
+	}
+	got, err := cachedFetch(ctx, "k", time.Minute, func() (*cachedThing, error) { return &cachedThing{N: 7}, nil })
+	if err != nil || got.N != 7 {
+		t.Errorf("got %+v, %v; want fresh value after failed fetch", got, err)
+	}
+}
+
+func TestCachedFetchNoCacheTTL(t *testing.T) {
+	setupCacheTest(t)
+	calls := 0
+	fetch := func() (*cachedThing, error) { calls++; return &cachedThing{N: calls}, nil }
+	_, _ = cachedFetch(context.Background(), "history|x|1", ttlNoCache, fetch)
+	_, _ = cachedFetch(context.Background(), "history|x|1", ttlNoCache, fetch)
+	if calls != 2 {
+		t.Errorf("fetch called %d times, want 2 for ttlNoCache", calls)
+	}
+}
+
+func TestHistoryTTL(t *testing.T) {
+	if got := historyTTL(time.Now().Add(-48 * time.Hour)); got != ttlForever {
+		t.Errorf("closed window TTL = %v, want ttlForever", got)
+	}
+	if got := historyTTL(time.Now()); got != ttlNoCache {
+		t.Errorf("open window TTL = %v, want ttlNoCache", got)
+	}
+}
+
+func TestCacheTTLConfig(t *testing.T) {
+	setupCacheTest(t)
+	if got := currentTTL(); got != defaultCurrentTTL {
+		t.Errorf("currentTTL() = %v, want default %v", got, defaultCurrentTTL)
+	}
+	viper.Set("cache.current_ttl", "15s")
+	if got := currentTTL(); got != 15*time.Second {
+		t.Errorf("currentTTL() = %v, want 15s", got)
+	}
+}
+
+func TestCloudCacheKey(t *testing.T) {
+	home := &config.StationConfig{Token: "token-one", StationID: 1}
+	if got := cloudCacheKey("current", home); !strings.HasPrefix(got, "current|cloud|1|token=") || strings.Contains(got, "token-one") {
+		t.Errorf("cloudCacheKey() = %q, want the endpoint first and no raw token", got)
+	}
+	other := &config.StationConfig{Token: "token-two", StationID: 1}
+	if cloudCacheKey("current", home) == cloudCacheKey("current", other) {
+		t.Error("two tokens for the same station share a cache key")
+	}
+	cmd := &config.StationConfig{TokenCommand: "pass show tempest", StationID: 1}
+	if cloudCacheKey("current", cmd) == cloudCacheKey("current", home) || cloudCacheKey("current", cmd) != cloudCacheKey("current", cmd) {
+		t.Error("token_command key not stable and distinct")
+	}
+}
+
+func TestCacheStatsAndClear(t *testing.T) {
+	setupCacheTest(t)
+	ctx := context.Background()
+	fetch := func() (*cachedThing, error) { return &cachedThing{N: 1}, nil }
+	_, _ = cachedFetch(ctx, cacheKey("current", "cloud", 1), time.Minute, fetch)
+	_, _ = cachedFetch(ctx, cacheKey("forecast", "cloud", 1), time.Minute, fetch)
+	_, _ = cachedFetch(ctx, cacheKey("forecast", "cloud", 2), time.Minute, fetch)
+
+	var out bytes.Buffer
+	cacheStatsCmd.SetOut(&out)
+	if err := runCacheStats(cacheStatsCmd, nil); err != nil {
+		t.Fatalf("runCacheStats() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Entries:         3") {
+		t.Errorf("stats output missing entry count:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "forecast   2") {
+		t.Errorf("stats output missing per-endpoint counts:\n%s", out.String())
+	}
+
+	out.Reset()
+	cacheClearCmd.SetOut(&out)
+	if err := runCacheClear(cacheClearCmd, nil); err != nil {
+		t.Fatalf("runCacheClear() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Removed 3") {
+		t.Errorf("clear output = %q", out.String())
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{512: "512 B", 2048: "2.0 KiB", 3 << 20: "3.0 MiB"}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/color"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/chart"
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/display"
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
+	"github.com/chadmayfield/tempest-cli/internal/pressure"
+	"github.com/chadmayfield/tempest-cli/internal/units"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var chartKinds = []string{"line", "rain", "wind-rose", "meteogram"}
+
+var chartCmd = &cobra.Command{
+	Use:   "chart <line|rain|wind-rose|meteogram>",
+	Short: "Draw historical data as a PNG or SVG chart",
+	Long: `Draw historical observations as a PNG or SVG image for reports and chat posts.
+
+  line       the --columns over time; columns with the same unit share a panel
+  rain       rain per hour, or per day for ranges over three days
+  wind-rose  how often the wind blew from each direction, by speed
+  meteogram  temperature and dew point, pressure, wind and rain on one time axis
+
+The image type comes from the --output file's extension. Times are shown in
+the station's time zone.`,
+	ValidArgs: chartKinds,
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE:      runChart,
+}
+
+func init() {
+	chartCmd.Flags().StringP("output", "o", "", "image file to write, ending in .png or .svg")
+	chartCmd.Flags().String("date", "", "single day (YYYY-MM-DD)")
+	chartCmd.Flags().String("from", "", "range start (YYYY-MM-DD)")
+	chartCmd.Flags().String("to", "", "range end (YYYY-MM-DD)")
+	chartCmd.Flags().String("resolution", "", "data resolution: 1m, 5m, 30m, 3h (auto if omitted)")
+	chartCmd.Flags().String("pressure", "sea-level", "pressure to show: sea-level, altimeter or station")
+	chartCmd.Flags().StringSlice("columns", nil, "columns of a line chart (default temp); one of "+strings.Join(display.HistoryColumnKeys(), ", "))
+	chartCmd.Flags().Int("width", 0, "image width in pixels (default 1000, or 700 for a wind rose)")
+	chartCmd.Flags().Int("height", 0, "image height in pixels (default 500, 800 for a meteogram, or 600 for a wind rose)")
+	chartCmd.Flags().String("title", "", "chart title (default the station name and dates)")
+	chartCmd.Flags().StringSlice("colors", nil, "series colors in order, or a wind rose's speed band colors, as #rrggbb")
+	chartCmd.Flags().String("background", "#ffffff", "background color; text and grid colors are chosen to contrast with it")
+	chartCmd.Flags().String("tz", "", "time zone for the time axis and dates, e.g. Europe/Berlin (default the station's)")
+	rootCmd.AddCommand(chartCmd)
+}
+
+func runChart(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	kind := args[0]
+
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		return usageError(fmt.Errorf("--output is required, e.g. --output chart.png"))
+	}
+	format, err := chart.FormatFromPath(output)
+	if err != nil {
+		return usageError(err)
+	}
+	columnNames, _ := cmd.Flags().GetStringSlice("columns")
+	if len(columnNames) > 0 && kind != "line" {
+		return usageError(fmt.Errorf("--columns only applies to line charts"))
+	}
+	if len(columnNames) == 0 {
+		columnNames = []string{"temp"}
+	}
+	columns, err := display.ParseHistoryColumns(columnNames)
+	if err != nil {
+		return usageError(err)
+	}
+	opts, err := chartOptions(cmd, kind)
+	if err != nil {
+		return err
+	}
+	pressureFlag, _ := cmd.Flags().GetString("pressure")
+	ref, err := pressure.ParseReference(pressureFlag)
+	if err != nil {
+		return usageError(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return wrapConfigError(err)
+	}
+	sc, err := cfg.ResolveStation(viper.GetString("station"))
+	if err != nil {
+		return wrapConfigError(err)
+	}
+	u, err := resolveUnits(cfg, sc)
+	if err != nil {
+		return err
+	}
+	l, err := resolveLocale()
+	if err != nil {
+		return err
+	}
+	serverURL := resolveServerURL(cfg)
+
+	loc, err := stationLocation(ctx, cmd, serverURL, sc)
+	if err != nil {
+		return err
+	}
+	start, end, err := parseHistoryDatesIn(cmd, loc)
+	if err != nil {
+		return usageError(err)
+	}
+	resFlag, _ := cmd.Flags().GetString("resolution")
+	if resFlag == "" {
+		resFlag = resolutionLabel(resolveResolution("", end.Sub(start)))
+	}
+	// Every observation is kept, so that rain adds up; lines are thinned
+	// to the image's width when drawn.
+	observations, _, err := fetchHistory(ctx, serverURL, sc, start, end, resFlag, 0)
+	if err != nil {
+		return wrapAPIError(err)
+	}
+	if len(observations) == 0 {
+		return newError(KindNotFound, nil, "no observations between %s and %s", start.In(loc).Format(time.DateTime), end.In(loc).Format(time.DateTime))
+	}
+	reducer, err := historyPressure(ctx, serverURL, sc, ref)
+	if err != nil && (kind == "meteogram" || slices.ContainsFunc(columns, func(c display.HistoryColumn) bool { return c.Key == "pressure" })) {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v; showing station pressure. Set elevation (meters) for the station in the config.\n", err)
+	}
+
+	opts.Location = loc
+	opts.TimeLabel = l.Time
+	opts.DateLabel = l.DayLabel
+	opts.Number = l.Number
+	if !cmd.Flags().Changed("title") {
+		opts.Title = chartTitle(sc, start.In(loc), end.In(loc), l)
+	}
+
+	var c chart.Chart
+	switch kind {
+	case "line":
+		c = chart.TimeSeries{Panels: columnPanels(columns, observations, u, reducer, l)}
+	case "rain":
+		c = chart.TimeSeries{Panels: []chart.Panel{rainPanel(observations, u, reducer, l, loc, end.Sub(start))}}
+	case "meteogram":
+		cols, _ := display.ParseHistoryColumns([]string{"temp", "dew", "pressure", "wind", "gust"})
+		panels := columnPanels(cols, observations, u, reducer, l)
+		c = chart.TimeSeries{Panels: append(panels, rainPanel(observations, u, reducer, l, loc, end.Sub(start)))}
+	case "wind-rose":
+		c = windRose(observations, u, l)
+	}
+	if ts, ok := c.(chart.TimeSeries); ok {
+		opts.Footer = l.T("chart_time_zone", zoneName(loc, start))
+		recolor(ts, opts.Palette.Series)
+	} else if rose, ok := c.(chart.WindRose); ok {
+		rose.Colors = opts.Palette.Series
+		c = rose
+	}
+	opts.Palette.Series = nil
+
+	var buf bytes.Buffer
+	if err := chart.Render(&buf, format, c, opts); err != nil {
+		return fmt.Errorf("drawing chart: %w", err)
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0o644); err != nil {
+		return newError(KindGeneral, err, "writing chart: %v", err)
+	}
+	return nil
+}
+
+// chartOptions returns the size and colors from the flags. Series holds the
+// --colors, which replace the defaults in order.
+func chartOptions(cmd *cobra.Command, kind string) (chart.Options, error) {
+	width, _ := cmd.Flags().GetInt("width")
+	height, _ := cmd.Flags().GetInt("height")
+	if width == 0 {
+		width = chart.DefaultWidth
+		if kind == "wind-rose" {
+			width = 700
+		}
+	}
+	if height == 0 {
+		switch kind {
+		case "meteogram":
+			height = 800
+		case "wind-rose":
+			height = 600
+		default:
+			height = chart.DefaultHeight
+		}
+	}
+	if width < 200 || height < 150 || width > 10000 || height > 10000 {
+		return chart.Options{}, usageError(fmt.Errorf("chart size %dx%d is out of range; use 200x150 to 10000x10000", width, height))
+	}
+	title, _ := cmd.Flags().GetString("title")
+
+	bgFlag, _ := cmd.Flags().GetString("background")
+	bg, err := chart.ParseColor(bgFlag)
+	if err != nil {
+		return chart.Options{}, usageError(fmt.Errorf("--background: %w", err))
+	}
+	colorFlags, _ := cmd.Flags().GetStringSlice("colors")
+	var series []color.NRGBA
+	for _, s := range colorFlags {
+		c, err := chart.ParseColor(s)
+		if err != nil {
+			return chart.Options{}, usageError(fmt.Errorf("--colors: %w", err))
+		}
+		series = append(series, c)
+	}
+	return chart.Options{Width: width, Height: height, Title: title, Palette: chart.PaletteFor(bg, series)}, nil
+}
+
+// chartColors keep a column the same color in every chart. Other columns
+// take the palette's colors.
+var chartColors = map[string]color.NRGBA{
+	"temp":     {0xd6, 0x27, 0x28, 0xff},
+	"feels":    {0xff, 0x7f, 0x0e, 0xff},
+	"dew":      {0x2c, 0xa0, 0x2c, 0xff},
+	"hum":      {0x17, 0xbe, 0xcf, 0xff},
+	"wind":     {0x1f, 0x77, 0xb4, 0xff},
+	"gust":     {0x6b, 0xae, 0xd6, 0xff},
+	"pressure": {0x94, 0x67, 0xbd, 0xff},
+	"rain":     {0x31, 0x82, 0xbd, 0xff},
+}
+
+// columnPanels charts each column as a line. Columns with the same unit
+// share a panel; columns without a unit get one each.
+func columnPanels(cols []display.HistoryColumn, obs []tempest.Observation, u units.Set, p pressure.Reducer, l *i18n.Locale) []chart.Panel {
+	var panels []chart.Panel
+	var titles [][]string
+	var unitOf []string
+	byUnit := map[string]int{}
+	for _, c := range cols {
+		if c.Key == "time" {
+			continue
+		}
+		unit := c.Unit(u)
+		i, ok := byUnit[unit]
+		if !ok || unit == "" {
+			i = len(panels)
+			panels = append(panels, chart.Panel{})
+			titles = append(titles, nil)
+			unitOf = append(unitOf, unit)
+			if unit != "" {
+				byUnit[unit] = i
+			}
+		}
+		s := chart.Series{Name: c.Title(l, p), Color: chartColors[c.Key]}
+		for k := range obs {
+			s.Points = append(s.Points, chart.Point{Time: obs[k].Timestamp, Value: c.Value(&obs[k], u, p)})
+		}
+		panels[i].Series = append(panels[i].Series, s)
+		titles[i] = append(titles[i], s.Name)
+	}
+	for i := range panels {
+		panels[i].Label = strings.Join(titles[i], ", ")
+		if unitOf[i] != "" {
+			panels[i].Label += " (" + unitOf[i] + ")"
+		}
+	}
+	return panels
+}
+
+// rainPanel sums rain into hourly bars, or into daily bars in loc when span
+// is over three days.
+func rainPanel(obs []tempest.Observation, u units.Set, p pressure.Reducer, l *i18n.Locale, loc *time.Location, span time.Duration) chart.Panel {
+	col, _ := display.LookupHistoryColumn("rain")
+	step := time.Hour
+	if span > 3*24*time.Hour {
+		step = 24 * time.Hour
+	}
+	sums := map[time.Time]float64{}
+	for i := range obs {
+		t := obs[i].Timestamp.In(loc)
+		hour := t.Hour()
+		if step == 24*time.Hour {
+			hour = 0
+		}
+		bucket := time.Date(t.Year(), t.Month(), t.Day(), hour, 0, 0, 0, loc)
+		sums[bucket] += col.Value(&obs[i], u, p)
+	}
+	s := chart.Series{Name: col.Title(l, p), Step: step, Color: chartColors["rain"]}
+	for t, v := range sums {
+		s.Points = append(s.Points, chart.Point{Time: t, Value: v})
+	}
+	slices.SortFunc(s.Points, func(a, b chart.Point) int { return a.Time.Compare(b.Time) })
+	return chart.Panel{Label: s.Name + " (" + col.Unit(u) + ")", Series: []chart.Series{s}}
+}
+
+// calmSpeed is the wind speed, in m/s, below which wind counts as calm.
+const calmSpeed = 0.5
+
+func windRose(obs []tempest.Observation, u units.Set, l *i18n.Locale) chart.WindRose {
+	rose := chart.WindRose{
+		Unit:      units.Symbol(u.Wind),
+		Calm:      u.Speed(calmSpeed),
+		Compass:   [4]string{l.CompassPoint(0), l.CompassPoint(90), l.CompassPoint(180), l.CompassPoint(270)},
+		CalmLabel: l.T("chart_calm"),
+	}
+	for _, o := range obs {
+		rose.Samples = append(rose.Samples, chart.WindSample{Direction: o.WindDirection, Speed: u.Speed(o.WindAvg)})
+	}
+	return rose
+}
+
+// recolor gives the series the --colors in order, across panels.
+func recolor(ts chart.TimeSeries, colors []color.NRGBA) {
+	i := 0
+	for _, p := range ts.Panels {
+		for k := range p.Series {
+			if i < len(colors) {
+				p.Series[k].Color = colors[i]
+			}
+			i++
+		}
+	}
+}
+
+// chartTitle names the station and the dates charted.
+func chartTitle(sc *config.StationConfig, start, end time.Time, l *i18n.Locale) string {
+	name := sc.Name
+	if name == "" {
+		name = strconv.Itoa(sc.StationID)
+	}
+	last := end.Add(-time.Nanosecond)
+	if start.Format(time.DateOnly) == last.Format(time.DateOnly) {
+		return name + ", " + l.DayLabel(start)
+	}
+	return name + ", " + l.DayLabel(start) + " – " + l.DayLabel(last)
+}
+
+// zoneName names loc for the chart footer: its IANA name, or the
+// abbreviation in effect at t for the local zone.
+func zoneName(loc *time.Location, t time.Time) string {
+	abbr := t.In(loc).Format("MST")
+	if name := loc.String(); name != "Local" && name != abbr {
+		return name + " (" + abbr + ")"
+	}
+	return abbr
+}
+
+// stationLocation returns the time zone to chart sc's data in: --tz, the
+// station's timezone setting, its time zone from the cloud API, or else
+// the local time zone.
+func stationLocation(ctx context.Context, cmd *cobra.Command, serverURL string, sc *config.StationConfig) (*time.Location, error) {
+	name, _ := cmd.Flags().GetString("tz")
+	if name == "" {
+		name = sc.Timezone
+	}
+	if name == "" {
+		tz, _, err := fetchWithFallback(withoutFetchInfo(ctx), serverURL, sourceFetchers[string]{
+			Cloud: func(ctx context.Context) (*string, error) {
+				return cachedFetch(ctx, cloudCacheKey("timezone", sc), stationTTL(), func() (*string, error) {
+					client, err := newStationClient(ctx, sc)
+					if err != nil {
+						return nil, err
+					}
+					tz, err := client.StationTimezone(ctx, sc.StationID)
+					if err != nil {
+						return nil, err
+					}
+					return &tz, nil
+				})
+			},
+		})
+		if err != nil {
+			slog.Debug("station time zone unknown, using local time", "station_id", sc.StationID, "error", err)
+			return time.Local, nil
+		}
+		name = *tz
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, usageError(fmt.Errorf("unknown time zone %q", name))
+	}
+	return loc, nil
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/fakeserver"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// chartNow is the fake server's clock, well after the June 1-2, 2024 days
+// the tests chart.
+var chartNow = time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+
+func setupChart(t *testing.T, flags map[string]string) {
+	t.Helper()
+	t.Setenv("LC_ALL", "C")
+	setupFakeServer(t, chartCmd, fakeserver.Options{Now: func() time.Time { return chartNow }}, "tempestd")
+	viper.Set("stations.home.name", "Cabin")
+	viper.Set("stations.home.timezone", "Asia/Tokyo")
+
+	for name, value := range flags {
+		if err := chartCmd.Flags().Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		chartCmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if !f.Changed {
+				return
+			}
+			if v, ok := f.Value.(pflag.SliceValue); ok {
+				_ = v.Replace(nil)
+			} else {
+				_ = f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		})
+	})
+}
+
+func TestRunChartSVG(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		t.Skip("no time zone data")
+	}
+	out := filepath.Join(t.TempDir(), "chart.svg")
+	setupChart(t, map[string]string{"output": out, "from": "2024-06-01", "to": "2024-06-02"})
+
+	if err := runChart(chartCmd, []string{"meteogram"}); err != nil {
+		t.Fatalf("runChart() error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := xml.Unmarshal(data, new(struct{})); err != nil {
+		t.Fatalf("invalid SVG: %v", err)
+	}
+	svg := string(data)
+	for _, want := range []string{
+		`width="1000" height="800"`,
+		">Cabin, Sat Jun 1 – Sun Jun 2</text>",
+		">Temp, Dew Pt (°C)</text>",
+		">Pressure (hPa)</text>",
+		">Wind, Gust (m/s)</text>",
+		">Rain (mm)</text>",
+		">Time zone: Asia/Tokyo (JST)</text>",
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG missing %q", want)
+		}
+	}
+}
+
+func TestRunChartPNG(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "rose.png")
+	setupChart(t, map[string]string{"output": out, "date": "2024-06-01", "tz": "UTC", "width": "400", "height": "300", "colors": "#ff0000,#00ff00"})
+
+	if err := runChart(chartCmd, []string{"wind-rose"}); err != nil {
+		t.Fatalf("runChart() error: %v", err)
+	}
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 400 || b.Dy() != 300 {
+		t.Errorf("size = %v, want 400x300", b)
+	}
+}
+
+func TestRunChartUsageErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		kind  string
+		flags map[string]string
+		want  string
+	}{
+		{"no output", "line", nil, "--output is required"},
+		{"bad extension", "line", map[string]string{"output": "chart.jpg"}, "use a .png or .svg file"},
+		{"columns on rain", "rain", map[string]string{"output": "chart.png", "columns": "temp"}, "only applies to line charts"},
+		{"unknown column", "line", map[string]string{"output": "chart.png", "columns": "nope"}, "nope"},
+		{"too small", "line", map[string]string{"output": "chart.png", "width": "10"}, "out of range"},
+		{"bad color", "line", map[string]string{"output": "chart.png", "background": "blue"}, "--background"},
+		{"bad time zone", "line", map[string]string{"output": "chart.png", "tz": "Mars/Olympus"}, "unknown time zone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupChart(t, tt.flags)
+			err := runChart(chartCmd, []string{tt.kind})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+			if errorKind(err) != KindUsage {
+				t.Errorf("kind = %v, want usage", errorKind(err))
+			}
+		})
+	}
+}
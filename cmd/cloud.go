@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/spf13/viper"
+)
+
+// cloudTimeout matches tempest-go's default per-request timeout.
+const cloudTimeout = 30 * time.Second
+
+// cloudBaseURL is the WeatherFlow REST API. It is a variable so tests can
+// point requests tempest-go does not cover at a local server.
+var cloudBaseURL = "https://swd.weatherflow.com/swd/rest"
+
+// cloudURL returns the REST API base URL: cloud_url from the config or
+// TEMPEST_CLOUD_URL if set, such as a `tempest dev fake-server`, otherwise
+// cloudBaseURL.
+func cloudURL() string {
+	if u := viper.GetString("cloud_url"); u != "" {
+		return strings.TrimSuffix(u, "/")
+	}
+	return cloudBaseURL
+}
+
+// cloudClient wraps tempest.Client so that its errors can be categorised.
+// tempest-go flattens transport errors to strings and keeps HTTP status codes
+// in an unexported type, so each call records the outcome of its last HTTP
+// attempt through the request context and classifies the error from that.
+type cloudClient struct {
+	*tempest.Client
+
+	token   string
+	baseURL string
+	http    *http.Client
+}
+
+// requestOutcome is filled in by recordingTransport for the request whose
+// context carries it.
+type requestOutcome struct {
+	status int
+	err    error
+}
+
+type outcomeKey struct{}
+
+type recordingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if o, ok := req.Context().Value(outcomeKey{}).(*requestOutcome); ok {
+		o.err = err
+		o.status = 0
+		if resp != nil {
+			o.status = resp.StatusCode
+		}
+	}
+	return resp, err
+}
+
+// newCloudClient creates a WeatherFlow REST client for token. Extra options are
+// applied after the recording HTTP client, so they must not replace it.
+func newCloudClient(token string, opts ...tempest.ClientOption) (*cloudClient, error) {
+	httpClient := &http.Client{
+		Timeout: cloudTimeout,
+		Transport: &recordingTransport{base: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				MinVersion: tls.VersionTLS12,
+			},
+		}},
+	}
+	baseURL := cloudURL()
+	base := []tempest.ClientOption{tempest.WithHTTPClient(httpClient), tempest.WithBaseURL(baseURL)}
+	c, err := tempest.NewClient(token, append(base, opts...)...)
+	if err != nil {
+		return nil, &Error{Kind: KindConfig, Err: err}
+	}
+	return &cloudClient{Client: c, token: token, baseURL: baseURL, http: httpClient}, nil
+}
+
+// newStationClient creates a client with sc's token, reading token_file or
+// running token_command first if needed.
+func newStationClient(ctx context.Context, sc *config.StationConfig, opts ...tempest.ClientOption) (*cloudClient, error) {
+	token, err := sc.APIToken(ctx)
+	if err != nil {
+		return nil, newError(KindConfig, err, "station %d token (%s): %v", sc.StationID, sc.TokenSource(), err)
+	}
+	return newCloudClient(token, opts...)
+}
+
+// lazyStationClient returns a function that creates sc's client on first
+// use. Fetches call it inside their cachedFetch closures, so that a cache
+// hit never reads token_file or runs token_command.
+func lazyStationClient(ctx context.Context, sc *config.StationConfig) func() (*cloudClient, error) {
+	return sync.OnceValues(func() (*cloudClient, error) {
+		return newStationClient(ctx, sc)
+	})
+}
+
+// track returns a context that records the outcome of requests made with it,
+// and a function that converts an error from that call into a categorised one.
+func track(ctx context.Context) (context.Context, func(error) error) {
+	o := &requestOutcome{}
+	ctx = context.WithValue(ctx, outcomeKey{}, o)
+	return ctx, func(err error) error {
+		if err == nil {
+			return nil
+		}
+		if o.status != 0 && o.status != http.StatusOK {
+			return statusError(o.status, err)
+		}
+		if o.err != nil {
+			if wrapped := transportError(o.err); wrapped != o.err {
+				return wrapped
+			}
+		}
+		return wrapAPIError(err)
+	}
+}
+
+func (c *cloudClient) GetStation(ctx context.Context, stationID int) (*tempest.Station, error) {
+	ctx, classify := track(ctx)
+	s, err := c.Client.GetStation(ctx, stationID)
+	return s, classify(err)
+}
+
+func (c *cloudClient) GetStationObservation(ctx context.Context, stationID int) (*tempest.StationObservation, error) {
+	ctx, classify := track(ctx)
+	obs, err := c.Client.GetStationObservation(ctx, stationID)
+	return obs, classify(err)
+}
+
+func (c *cloudClient) GetForecast(ctx context.Context, stationID int) (*tempest.Forecast, error) {
+	ctx, classify := track(ctx)
+	f, err := c.Client.GetForecast(ctx, stationID)
+	return f, classify(err)
+}
+
+func (c *cloudClient) GetDeviceObservations(ctx context.Context, deviceID int, start, end time.Time) ([]tempest.Observation, error) {
+	ctx, classify := track(ctx)
+	obs, err := c.Client.GetDeviceObservations(ctx, deviceID, start, end)
+	return obs, classify(err)
+}
+
+// stationsResponse is the body of GET /stations.
+type stationsResponse struct {
+	Stations []struct {
+		StationID   int     `json:"station_id"`
+		Name        string  `json:"name"`
+		Latitude    float64 `json:"latitude"`
+		Longitude   float64 `json:"longitude"`
+		StationMeta struct {
+			Elevation float64 `json:"elevation"`
+		} `json:"station_meta"`
+		Devices []struct {
+			DeviceID     int    `json:"device_id"`
+			SerialNumber string `json:"serial_number"`
+			DeviceType   string `json:"device_type"`
+		} `json:"devices"`
+	} `json:"stations"`
+}
+
+// ListStations returns every station the token can see, with its devices.
+// tempest-go only fetches stations by ID, so this calls the API directly.
+func (c *cloudClient) ListStations(ctx context.Context) ([]tempest.Station, error) {
+	ctx, classify := track(ctx)
+	stations, err := c.listStations(ctx)
+	return stations, classify(err)
+}
+
+func (c *cloudClient) listStations(ctx context.Context) ([]tempest.Station, error) {
+	var body stationsResponse
+	if err := c.get(ctx, "/stations", "listing stations", &body); err != nil {
+		return nil, err
+	}
+	stations := make([]tempest.Station, len(body.Stations))
+	for i, s := range body.Stations {
+		st := tempest.Station{
+			StationID: s.StationID,
+			Name:      s.Name,
+			Latitude:  s.Latitude,
+			Longitude: s.Longitude,
+			Elevation: s.StationMeta.Elevation,
+		}
+		for _, d := range s.Devices {
+			st.Devices = append(st.Devices, tempest.Device{DeviceID: d.DeviceID, SerialNum: d.SerialNumber, DeviceType: d.DeviceType})
+		}
+		stations[i] = st
+	}
+	return stations, nil
+}
+
+// stationTimezoneResponse is the part of GET /stations/{id} that tempest-go
+// does not parse.
+type stationTimezoneResponse struct {
+	Stations []struct {
+		Timezone string `json:"timezone"`
+	} `json:"stations"`
+}
+
+// StationTimezone returns the station's IANA time zone, e.g. "America/Denver".
+func (c *cloudClient) StationTimezone(ctx context.Context, stationID int) (string, error) {
+	ctx, classify := track(ctx)
+	var body stationTimezoneResponse
+	if err := c.get(ctx, fmt.Sprintf("/stations/%d", stationID), "fetching station", &body); err != nil {
+		return "", classify(err)
+	}
+	if len(body.Stations) == 0 || body.Stations[0].Timezone == "" {
+		return "", fmt.Errorf("station %d has no time zone", stationID)
+	}
+	return body.Stations[0].Timezone, nil
+}
+
+// get fetches path from the REST API and decodes its JSON body into v. what
+// describes the request in errors.
+func (c *cloudClient) get(ctx context.Context, path, what string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?token="+url.QueryEscape(c.token), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// The error text includes the URL, and with it the token.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("%s: %w", what, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: HTTP %d", what, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%s: parsing response: %w", what, err)
+	}
+	return nil
+}
@@ -63,5 +63,10 @@ func completeStationNames(cmd *cobra.Command, args []string, toComplete string)
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	return cfg.StationNames(), cobra.ShellCompDirectiveNoFileComp
+	names := cfg.StationNames()
+	// Only current conditions can show a group of stations.
+	if cmd == currentCmd || cmd == rootCmd {
+		names = append(names, cfg.GroupNames()...)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
 }
@@ -4,17 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/display"
 	tempest "github.com/chadmayfield/tempest-go"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"gopkg.in/yaml.v3"
 )
 
 var configCmd = &cobra.Command{
@@ -43,7 +42,7 @@ func init() {
 func runConfigShow(cmd *cobra.Command, args []string) error {
 	cfg, err := config.Load()
 	if err != nil {
-		return fmt.Errorf("loading config: %w", err)
+		return wrapConfigError(fmt.Errorf("loading config: %w", err))
 	}
 
 	if viper.GetBool("json") {
@@ -60,6 +59,22 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	if server := cfg.EffectiveServerURL(); server != "" {
 		_, _ = fmt.Fprintf(w, "Server:          %s\n", server)
 	}
+	td := cfg.Tempestd
+	switch {
+	case td.Token != "":
+		_, _ = fmt.Fprintf(w, "Server auth:     bearer %s\n", config.RedactToken(td.Token))
+	case td.Username != "":
+		_, _ = fmt.Fprintf(w, "Server auth:     basic %s:%s\n", td.Username, redactSecret(td.Password))
+	}
+	if td.CAFile != "" {
+		_, _ = fmt.Fprintf(w, "Server CA:       %s\n", td.CAFile)
+	}
+	if td.CertFile != "" {
+		_, _ = fmt.Fprintf(w, "Client cert:     %s\n", td.CertFile)
+	}
+	if td.InsecureSkipVerify {
+		_, _ = fmt.Fprintln(w, "TLS verify:      disabled (insecure_skip_verify)")
+	}
 	_, _ = fmt.Fprintln(w)
 
 	for name, sc := range cfg.Stations {
@@ -69,7 +84,7 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 		}
 		_, _ = fmt.Fprintf(w, "Station: %s%s\n", name, def)
 		_, _ = fmt.Fprintf(w, "  Name:       %s\n", sc.Name)
-		_, _ = fmt.Fprintf(w, "  Token:      %s\n", config.RedactToken(sc.Token))
+		_, _ = fmt.Fprintf(w, "  Token:      %s\n", describeToken(&sc))
 		_, _ = fmt.Fprintf(w, "  Station ID: %d\n", sc.StationID)
 		_, _ = fmt.Fprintf(w, "  Device ID:  %d\n", sc.DeviceID)
 		_, _ = fmt.Fprintln(w)
@@ -86,20 +101,68 @@ func displayUnits(u string) string {
 }
 
 type redactedStationConfig struct {
-	Token     string `json:"token"`
-	StationID int    `json:"station_id"`
-	DeviceID  int    `json:"device_id"`
-	Name      string `json:"name"`
+	Token        string `json:"token"`
+	TokenFile    string `json:"token_file,omitempty"`
+	TokenCommand string `json:"token_command,omitempty"`
+	// TokenSource says where the token is resolved from; see
+	// config.StationConfig.TokenSource.
+	TokenSource string `json:"token_source"`
+	StationID   int    `json:"station_id"`
+	DeviceID    int    `json:"device_id"`
+	Name        string `json:"name"`
+}
+
+// describeToken shows a station's token source, with the redacted token
+// when it is set directly. token_file and token_command are not run.
+func describeToken(sc *config.StationConfig) string {
+	if sc.Token == "" {
+		return sc.TokenSource()
+	}
+	return fmt.Sprintf("%s (%s)", config.RedactToken(sc.Token), sc.TokenSource())
+}
+
+type redactedTempestdConfig struct {
+	Server             string `json:"server,omitempty"`
+	Token              string `json:"token,omitempty"`
+	Username           string `json:"username,omitempty"`
+	Password           string `json:"password,omitempty"`
+	CAFile             string `json:"ca_file,omitempty"`
+	CertFile           string `json:"cert_file,omitempty"`
+	KeyFile            string `json:"key_file,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+// redactSecret hides a password entirely; unlike tokens, no prefix is shown.
+func redactSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "****"
+}
+
+// redactOptionalToken redacts token, leaving an unset token empty.
+func redactOptionalToken(token string) string {
+	if token == "" {
+		return ""
+	}
+	return config.RedactToken(token)
 }
 
 func redactedConfig(cfg *config.Config) map[string]any {
 	stations := make(map[string]redactedStationConfig)
 	for name, sc := range cfg.Stations {
+		token := config.RedactToken(sc.Token)
+		if sc.Token == "" && sc.HasToken() {
+			token = ""
+		}
 		stations[name] = redactedStationConfig{
-			Token:     config.RedactToken(sc.Token),
-			StationID: sc.StationID,
-			DeviceID:  sc.DeviceID,
-			Name:      sc.Name,
+			Token:        token,
+			TokenFile:    sc.TokenFile,
+			TokenCommand: sc.TokenCommand,
+			TokenSource:  sc.TokenSource(),
+			StationID:    sc.StationID,
+			DeviceID:     sc.DeviceID,
+			Name:         sc.Name,
 		}
 	}
 	return map[string]any{
@@ -107,38 +170,86 @@ func redactedConfig(cfg *config.Config) map[string]any {
 		"units":           cfg.Units,
 		"stations":        stations,
 		"server":          cfg.EffectiveServerURL(),
-		"config_file":     viper.ConfigFileUsed(),
+		"tempestd": redactedTempestdConfig{
+			Server:             cfg.Tempestd.Server,
+			Token:              redactOptionalToken(cfg.Tempestd.Token),
+			Username:           cfg.Tempestd.Username,
+			Password:           redactSecret(cfg.Tempestd.Password),
+			CAFile:             cfg.Tempestd.CAFile,
+			CertFile:           cfg.Tempestd.CertFile,
+			KeyFile:            cfg.Tempestd.KeyFile,
+			InsecureSkipVerify: cfg.Tempestd.InsecureSkipVerify,
+		},
+		"config_file": viper.ConfigFileUsed(),
 	}
 }
 
 // -- Config init: bubbletea wizard --
-// Note: The spec calls for "fetching available stations" but the tempest-go
-// library (and the WeatherFlow REST API) only supports GetStation(id), not
-// listing all stations for a token. The wizard therefore asks the user to
-// enter their station ID (visible at tempestwx.com) and verifies it via API.
+// After the token is entered the wizard lists the stations it can see, so
+// users pick from a list rather than looking up IDs. A station ID can still
+// be entered by hand if discovery fails or the station is not listed.
+//
+// The wizard edits the config file in use rather than replacing it: other
+// stations, the tempestd block and comments are preserved.
 
 type initStep int
 
 const (
-	stepToken initStep = iota
+	stepMode initStep = iota
+	stepReplacePick
+	stepToken
+	stepDiscover
+	stepPickStation
 	stepStationID
 	stepFetchStation
+	stepPickDevice
 	stepNameStation
+	stepAnother
 	stepUnits
+	stepTempestd
 	stepWriting
 	stepDone
 )
 
+// Choices offered when a config with stations already exists.
+const (
+	modeAdd = iota
+	modeReplace
+	modeKeep
+)
+
+var initModes = []string{"Add a station", "Replace a station", "Keep existing stations"}
+
+var initUnits = []string{"Imperial (°F, mph, inHg)", "Metric (°C, m/s, hPa)"}
+
+// pendingStation is a station collected by the wizard but not yet written.
+type pendingStation struct {
+	name     string
+	replaces string
+	cfg      config.StationConfig
+}
+
 type initModel struct {
 	step     initStep
+	file     *config.File
+	existing []string // station names already in the file
+	replace  string   // station the next one replaces, if any
+	added    []pendingStation
 	token    string
 	input    string
+	stations []tempest.Station // discovered for the current token
 	station  *tempest.Station
-	name     string
+	deviceID int
 	units    string
+	server   string
 	err      error
 	written  string
-	selected int // for units selection (0=imperial, 1=metric)
+	selected int // cursor for menu steps
+}
+
+type stationsDiscoveredMsg struct {
+	stations []tempest.Station
+	err      error
 }
 
 type stationFetchedMsg struct {
@@ -151,10 +262,37 @@ type configWrittenMsg struct {
 	err  error
 }
 
+// newInitModel starts the wizard for f, asking what to do with existing
+// stations if there are any.
+func newInitModel(f *config.File, existing []string) initModel {
+	m := initModel{step: stepToken, file: f, existing: existing}
+	if len(existing) > 0 {
+		m.step = stepMode
+	}
+	return m
+}
+
 func (m initModel) Init() tea.Cmd {
 	return nil
 }
 
+// options returns the number of choices on a menu step.
+func (m initModel) options() int {
+	switch m.step {
+	case stepMode:
+		return len(initModes)
+	case stepReplacePick:
+		return len(m.existing)
+	case stepPickStation:
+		return len(m.stations) + 1
+	case stepPickDevice:
+		return len(sensorDevices(m.station))
+	case stepUnits:
+		return len(initUnits)
+	}
+	return 0
+}
+
 func (m initModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -164,11 +302,11 @@ func (m initModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			return m.handleEnter()
 		case "up":
-			if m.step == stepUnits && m.selected > 0 {
+			if m.selected > 0 {
 				m.selected--
 			}
 		case "down":
-			if m.step == stepUnits && m.selected < 1 {
+			if m.selected < m.options()-1 {
 				m.selected++
 			}
 		case "backspace":
@@ -176,11 +314,30 @@ func (m initModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.input = m.input[:len(m.input)-1]
 			}
 		default:
-			if len(msg.String()) == 1 {
+			if len(msg.String()) == 1 && m.options() == 0 {
 				m.input += msg.String()
 			}
 		}
 
+	case stationsDiscoveredMsg:
+		m.input = ""
+		m.selected = 0
+		switch {
+		case errorKind(msg.err) == KindAuth:
+			m.err = fmt.Errorf("token rejected by the WeatherFlow API")
+			m.step = stepToken
+		case msg.err != nil:
+			m.err = fmt.Errorf("could not list stations: %w; enter the station ID instead", msg.err)
+			m.step = stepStationID
+		case len(msg.stations) == 0:
+			m.err = fmt.Errorf("no stations are visible to this token; enter the station ID instead")
+			m.step = stepStationID
+		default:
+			m.err = nil
+			m.stations = msg.stations
+			m.step = stepPickStation
+		}
+
 	case stationFetchedMsg:
 		if msg.err != nil {
 			m.err = msg.err
@@ -188,10 +345,7 @@ func (m initModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.input = ""
 			return m, nil
 		}
-		m.station = msg.station
-		m.step = stepNameStation
-		// Pre-fill name suggestion
-		m.input = strings.ToLower(strings.ReplaceAll(m.station.Name, " ", "-"))
+		m = m.withStation(msg.station)
 
 	case configWrittenMsg:
 		if msg.err != nil {
@@ -208,14 +362,45 @@ func (m initModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m initModel) handleEnter() (tea.Model, tea.Cmd) {
 	switch m.step {
+	case stepMode:
+		switch m.selected {
+		case modeAdd:
+			m.step = stepToken
+		case modeReplace:
+			m.step = stepReplacePick
+		case modeKeep:
+			m = m.toUnits()
+		}
+		m.selected = 0
+
+	case stepReplacePick:
+		m.replace = m.existing[m.selected]
+		m.selected = 0
+		m.step = stepToken
+
 	case stepToken:
-		m.token = strings.TrimSpace(m.input)
-		if m.token == "" {
+		token := strings.TrimSpace(m.input)
+		if token == "" && m.token == "" {
 			return m, nil
 		}
+		if token != "" {
+			m.token = token
+		}
 		m.input = ""
 		m.err = nil
-		m.step = stepStationID
+		m.step = stepDiscover
+		return m, m.discoverStations
+
+	case stepPickStation:
+		if m.selected == len(m.stations) {
+			m.step = stepStationID
+			return m, nil
+		}
+		m = m.withStation(&m.stations[m.selected])
+
+	case stepPickDevice:
+		m.deviceID = sensorDevices(m.station)[m.selected].DeviceID
+		m = m.toName()
 
 	case stepStationID:
 		idStr := strings.TrimSpace(m.input)
@@ -232,20 +417,52 @@ func (m initModel) handleEnter() (tea.Model, tea.Cmd) {
 		return m, m.fetchStation
 
 	case stepNameStation:
-		m.name = strings.TrimSpace(m.input)
-		if m.name == "" {
+		name := strings.TrimSpace(m.input)
+		if name == "" {
 			return m, nil
 		}
+		if err := m.checkName(name); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.err = nil
+		m.added = append(m.added, pendingStation{
+			name:     name,
+			replaces: m.replace,
+			cfg:      stationConfigFor(m.token, m.station, m.deviceID),
+		})
+		m.replace = ""
 		m.input = ""
-		m.step = stepUnits
-		m.selected = 0
+		m.step = stepAnother
+
+	case stepAnother:
+		answer := strings.ToLower(strings.TrimSpace(m.input))
+		m.input = ""
+		if answer == "y" || answer == "yes" {
+			m.step = stepToken
+			return m, nil
+		}
+		m = m.toUnits()
 
 	case stepUnits:
-		if m.selected == 0 {
-			m.units = "imperial"
-		} else {
+		m.units = "imperial"
+		if m.selected == 1 {
 			m.units = "metric"
 		}
+		m.input = m.currentServer()
+		m.step = stepTempestd
+
+	case stepTempestd:
+		server := strings.TrimSpace(m.input)
+		if server != "" {
+			if err := validateServerURL(server); err != nil {
+				m.err = err
+				return m, nil
+			}
+		}
+		m.err = nil
+		m.server = server
+		m.input = ""
 		m.step = stepWriting
 		return m, m.writeConfig
 	}
@@ -253,8 +470,92 @@ func (m initModel) handleEnter() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// withStation records the chosen station and asks which sensor to use if it
+// has more than one.
+func (m initModel) withStation(station *tempest.Station) initModel {
+	m.station = station
+	m.deviceID = 0
+	m.selected = 0
+	sensors := sensorDevices(station)
+	if len(sensors) > 1 {
+		m.step = stepPickDevice
+		return m
+	}
+	if len(sensors) == 1 {
+		m.deviceID = sensors[0].DeviceID
+	}
+	return m.toName()
+}
+
+// toName moves to the naming step with a suggested name.
+func (m initModel) toName() initModel {
+	m.step = stepNameStation
+	m.selected = 0
+	if m.replace != "" {
+		m.input = m.replace
+	} else {
+		m.input = strings.ToLower(strings.ReplaceAll(m.station.Name, " ", "-"))
+	}
+	return m
+}
+
+// toUnits moves to the units step with the file's current units selected.
+func (m initModel) toUnits() initModel {
+	m.step = stepUnits
+	m.selected = 0
+	if n, ok := m.file.Get("units"); ok && n.Value == "metric" {
+		m.selected = 1
+	}
+	return m
+}
+
+// checkName rejects names that would collide with another station, except
+// the one being replaced.
+func (m initModel) checkName(name string) error {
+	if err := validStationName(name); err != nil {
+		return fmt.Errorf("station names cannot contain dots or spaces")
+	}
+	taken := slices.Contains(m.existing, name) && name != m.replace
+	for _, p := range m.added {
+		if p.name == name || (p.replaces == name && name != m.replace) {
+			taken = true
+		}
+	}
+	if taken {
+		return fmt.Errorf("station %q already exists; choose another name", name)
+	}
+	return nil
+}
+
+func (m initModel) currentServer() string {
+	for _, key := range []string{"tempestd.server", "server"} {
+		if n, ok := m.file.Get(key); ok && n.Value != "" {
+			return n.Value
+		}
+	}
+	return ""
+}
+
+func stationConfigFor(token string, station *tempest.Station, deviceID int) config.StationConfig {
+	return config.StationConfig{
+		Token:     token,
+		StationID: station.StationID,
+		DeviceID:  deviceID,
+		Name:      station.Name,
+	}
+}
+
+func (m initModel) discoverStations() tea.Msg {
+	client, err := newCloudClient(m.token)
+	if err != nil {
+		return stationsDiscoveredMsg{err: err}
+	}
+	stations, err := client.ListStations(context.TODO())
+	return stationsDiscoveredMsg{stations: stations, err: err}
+}
+
 func (m initModel) fetchStation() tea.Msg {
-	client, err := tempest.NewClient(m.token)
+	client, err := newCloudClient(m.token)
 	if err != nil {
 		return stationFetchedMsg{err: err}
 	}
@@ -271,45 +572,70 @@ func (m initModel) fetchStation() tea.Msg {
 }
 
 func (m initModel) writeConfig() tea.Msg {
-	home, err := os.UserHomeDir()
-	if err != nil {
+	if err := m.apply(); err != nil {
 		return configWrittenMsg{err: err}
 	}
-
-	configDir := filepath.Join(home, ".config", "tempest")
-	if err := os.MkdirAll(configDir, 0700); err != nil {
+	if err := m.file.Save(); err != nil {
 		return configWrittenMsg{err: err}
 	}
+	return configWrittenMsg{path: m.file.Path}
+}
 
-	deviceID := 0
-	if len(m.station.Devices) > 0 {
-		deviceID = m.station.Devices[0].DeviceID
+// apply merges the wizard's answers into the config file.
+func (m initModel) apply() error {
+	f := m.file
+	def := ""
+	if n, ok := f.Get("default_station"); ok {
+		def = n.Value
 	}
 
-	cfg := config.Config{
-		DefaultStation: m.name,
-		Units:          m.units,
-		Stations: map[string]config.StationConfig{
-			m.name: {
-				Token:     m.token,
-				StationID: m.station.StationID,
-				DeviceID:  deviceID,
-				Name:      m.station.Name,
-			},
-		},
+	for _, p := range m.added {
+		prefix := "stations." + p.name + "."
+		if p.replaces != "" {
+			// Drop the old station entirely, even when the name is kept, so
+			// that its token_file, elevation or unit overrides do not carry
+			// over to the new station.
+			f.Delete("stations." + p.replaces)
+			if def == p.replaces {
+				def = p.name
+			}
+		}
+		settings := [][2]string{
+			{"token", p.cfg.Token},
+			{"station_id", strconv.Itoa(p.cfg.StationID)},
+			{"device_id", strconv.Itoa(p.cfg.DeviceID)},
+			{"name", p.cfg.Name},
+		}
+		for _, kv := range settings {
+			if err := f.Set(prefix+kv[0], kv[1]); err != nil {
+				return err
+			}
+		}
 	}
-
-	data, err := yaml.Marshal(cfg)
-	if err != nil {
-		return configWrittenMsg{err: err}
+	if def == "" && len(m.added) > 0 {
+		def = m.added[0].name
+	}
+	if def != "" {
+		if err := f.Set("default_station", def); err != nil {
+			return err
+		}
 	}
 
-	path := filepath.Join(configDir, "config.yaml")
-	if err := os.WriteFile(path, data, 0600); err != nil {
-		return configWrittenMsg{err: err}
+	if m.units != "" {
+		if err := f.Set("units", m.units); err != nil {
+			return err
+		}
 	}
 
-	return configWrittenMsg{path: path}
+	if m.server != m.currentServer() {
+		f.Delete("server")
+		if m.server == "" {
+			f.Delete("tempestd.server")
+		} else if err := f.Set("tempestd.server", m.server); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (m initModel) View() string {
@@ -318,10 +644,49 @@ func (m initModel) View() string {
 	b.WriteString("Tempest CLI Configuration\n\n")
 
 	switch m.step {
+	case stepMode:
+		b.WriteString(fmt.Sprintf("Found %s with stations: %s\n\n", m.file.Path, strings.Join(m.existing, ", ")))
+		b.WriteString("What would you like to do?\n\n")
+		writeMenu(&b, initModes, m.selected)
+
+	case stepReplacePick:
+		b.WriteString("Select the station to replace:\n\n")
+		writeMenu(&b, m.existing, m.selected)
+
 	case stepToken:
+		if m.replace != "" {
+			b.WriteString(fmt.Sprintf("Replacing station %q\n\n", m.replace))
+		}
 		b.WriteString("Enter your WeatherFlow API token:\n")
+		if m.token != "" {
+			b.WriteString("(press Enter to reuse the previous token)\n")
+		}
 		b.WriteString("> " + strings.Repeat("*", len(m.input)) + "█\n")
 
+	case stepDiscover:
+		b.WriteString("Looking up stations for this token...\n")
+
+	case stepPickStation:
+		items := make([]string, 0, len(m.stations)+1)
+		for _, s := range m.stations {
+			item := fmt.Sprintf("%s (%d)", s.Name, s.StationID)
+			if slices.ContainsFunc(m.added, func(p pendingStation) bool { return p.cfg.StationID == s.StationID }) {
+				item += " - added"
+			}
+			items = append(items, item)
+		}
+		items = append(items, "Enter a station ID manually")
+		b.WriteString("Select a station:\n\n")
+		writeMenu(&b, items, m.selected)
+
+	case stepPickDevice:
+		var items []string
+		for _, d := range sensorDevices(m.station) {
+			items = append(items, fmt.Sprintf("%s %s (%d)", display.DeviceTypeName(d.DeviceType), d.SerialNum, d.DeviceID))
+		}
+		b.WriteString(fmt.Sprintf("%s has several sensors. Select the one to use for device observations:\n\n", m.station.Name))
+		writeMenu(&b, items, m.selected)
+
 	case stepStationID:
 		b.WriteString(fmt.Sprintf("Token: %s****\n\n", m.token[:min(4, len(m.token))]))
 		b.WriteString("Enter your station ID:\n")
@@ -335,17 +700,22 @@ func (m initModel) View() string {
 		b.WriteString("Config name for this station (e.g., home, office):\n")
 		b.WriteString("> " + m.input + "█\n")
 
+	case stepAnother:
+		names := make([]string, len(m.added))
+		for i, p := range m.added {
+			names[i] = p.name
+		}
+		b.WriteString(fmt.Sprintf("Stations to save: %s\n\n", strings.Join(names, ", ")))
+		b.WriteString("Add another station? (y/N)\n")
+		b.WriteString("> " + m.input + "█\n")
+
 	case stepUnits:
-		units := []string{"Imperial (°F, mph, inHg)", "Metric (°C, m/s, hPa)"}
 		b.WriteString("Select units:\n\n")
-		for i, u := range units {
-			cursor := "  "
-			if i == m.selected {
-				cursor = "> "
-			}
-			b.WriteString(cursor + u + "\n")
-		}
-		b.WriteString("\nUse arrow keys to select, Enter to confirm\n")
+		writeMenu(&b, initUnits, m.selected)
+
+	case stepTempestd:
+		b.WriteString("tempestd server URL for local data (leave empty for none):\n")
+		b.WriteString("> " + m.input + "█\n")
 
 	case stepWriting:
 		b.WriteString("Writing config...\n")
@@ -362,8 +732,28 @@ func (m initModel) View() string {
 	return b.String()
 }
 
+func writeMenu(b *strings.Builder, items []string, selected int) {
+	for i, item := range items {
+		cursor := "  "
+		if i == selected {
+			cursor = "> "
+		}
+		b.WriteString(cursor + item + "\n")
+	}
+	b.WriteString("\nUse arrow keys to select, Enter to confirm\n")
+}
+
 func runConfigInit(cmd *cobra.Command, args []string) error {
-	m := initModel{step: stepToken}
+	f, err := openConfigFile()
+	if err != nil {
+		return newError(KindConfig, err, "%v\nFix the file with 'tempest config edit' before running the wizard", err)
+	}
+	cfg, err := f.Config()
+	if err != nil {
+		return wrapConfigError(err)
+	}
+
+	m := newInitModel(f, cfg.StationNames())
 	p := tea.NewProgram(m)
 
 	finalModel, err := p.Run()
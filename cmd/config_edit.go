@@ -0,0 +1,432 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	jsonout "github.com/chadmayfield/tempest-cli/internal/json"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// The commands in this file edit the config file in place. Unlike
+// 'config init' they keep comments and key order, and every write is
+// validated and atomic.
+
+var configAddStationCmd = &cobra.Command{
+	Use:   "add-station <name>",
+	Short: "Add a station to the config",
+	Example: `  tempest config add-station home --token abc123 --station-id 12345 --device-id 67890
+  tempest config add-station cabin --token abc123 --station-id 54321 --default
+  tempest config add-station home --token-command "pass show tempest/home" --station-id 12345`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigAddStation,
+}
+
+var configRemoveStationCmd = &cobra.Command{
+	Use:   "remove-station <name>",
+	Short: "Remove a station from the config",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigRemoveStation,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a config value",
+	Example: `  tempest config set units metric
+  tempest config set tempestd.server http://localhost:8080
+  tempest config set stations.home.device_id 67890`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigSet,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a config value",
+	Long: `Print the effective value of a config key, including environment variable
+overrides. Tokens and passwords are redacted unless --show-secrets is given.`,
+	Example: `  tempest config get units
+  tempest config get stations.home.station_id
+  tempest config get stations.home.token --show-secrets`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigGet,
+}
+
+var configUseCmd = &cobra.Command{
+	Use:   "use <station>",
+	Short: "Set the default station",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigUse,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $EDITOR",
+	Long:  "Open the config file in $VISUAL or $EDITOR. The file is only replaced if the edited version is valid.",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigEdit,
+}
+
+func init() {
+	configCmd.AddCommand(configAddStationCmd)
+	configCmd.AddCommand(configRemoveStationCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configUseCmd)
+	configCmd.AddCommand(configEditCmd)
+
+	configAddStationCmd.Flags().String("token", "", "WeatherFlow API token")
+	configAddStationCmd.Flags().String("token-file", "", "read the API token from this file")
+	configAddStationCmd.Flags().String("token-command", "", "run this command and use its output as the API token")
+	configAddStationCmd.Flags().Int("station-id", 0, "station ID (required)")
+	configAddStationCmd.Flags().Int("device-id", 0, "Tempest sensor device ID")
+	configAddStationCmd.Flags().String("name", "", "display name for the station")
+	configAddStationCmd.Flags().Bool("default", false, "make this the default station")
+	configAddStationCmd.MarkFlagsOneRequired("token", "token-file", "token-command")
+	configAddStationCmd.MarkFlagsMutuallyExclusive("token", "token-file", "token-command")
+	_ = configAddStationCmd.MarkFlagRequired("station-id")
+
+	configGetCmd.Flags().Bool("show-secrets", false, "print tokens and passwords instead of redacting them")
+}
+
+// configPath returns the config file in use, or the default location if none
+// was found.
+func configPath() (string, error) {
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path, nil
+	}
+	path, err := config.DefaultPath()
+	if err != nil {
+		return "", wrapConfigError(err)
+	}
+	return path, nil
+}
+
+func openConfigFile() (*config.File, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := config.OpenFile(path)
+	if err != nil {
+		return nil, wrapConfigError(err)
+	}
+	return f, nil
+}
+
+func saveConfigFile(f *config.File) error {
+	if err := f.Save(); err != nil {
+		return newError(KindConfig, err, "not saving %s: %v", f.Path, err)
+	}
+	return nil
+}
+
+// validStationName rejects names that cannot be addressed as a dotted key.
+func validStationName(name string) error {
+	if name == "" || strings.ContainsAny(name, ". \t") {
+		return newError(KindUsage, nil, "invalid station name %q: must be non-empty with no dots or spaces", name)
+	}
+	return nil
+}
+
+func runConfigAddStation(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := validStationName(name); err != nil {
+		return err
+	}
+	tokenKey, token := "token", ""
+	for _, flag := range []string{"token", "token-file", "token-command"} {
+		if v, _ := cmd.Flags().GetString(flag); v != "" {
+			tokenKey, token = strings.ReplaceAll(flag, "-", "_"), v
+		}
+	}
+	stationID, _ := cmd.Flags().GetInt("station-id")
+	deviceID, _ := cmd.Flags().GetInt("device-id")
+	label, _ := cmd.Flags().GetString("name")
+	makeDefault, _ := cmd.Flags().GetBool("default")
+
+	if strings.TrimSpace(token) == "" {
+		return newError(KindUsage, nil, "--%s must not be empty", strings.ReplaceAll(tokenKey, "_", "-"))
+	}
+	if stationID <= 0 {
+		return newError(KindUsage, nil, "--station-id must be a positive number")
+	}
+	if deviceID < 0 {
+		return newError(KindUsage, nil, "--device-id must not be negative")
+	}
+
+	f, err := openConfigFile()
+	if err != nil {
+		return err
+	}
+	prefix := "stations." + name + "."
+	if _, exists := f.Get("stations." + name); exists {
+		return newError(KindConfig, nil, "station %q already exists; change it with 'tempest config set %s<key> <value>'", name, prefix)
+	}
+
+	settings := [][2]string{
+		{tokenKey, token},
+		{"station_id", strconv.Itoa(stationID)},
+	}
+	if deviceID > 0 {
+		settings = append(settings, [2]string{"device_id", strconv.Itoa(deviceID)})
+	}
+	if label != "" {
+		settings = append(settings, [2]string{"name", label})
+	}
+	for _, kv := range settings {
+		if err := f.Set(prefix+kv[0], kv[1]); err != nil {
+			return newError(KindConfig, err, "%v", err)
+		}
+	}
+
+	if def, ok := f.Get("default_station"); makeDefault || !ok || def.Value == "" {
+		if err := f.Set("default_station", name); err != nil {
+			return newError(KindConfig, err, "%v", err)
+		}
+		makeDefault = true
+	}
+
+	if err := saveConfigFile(f); err != nil {
+		return err
+	}
+	w := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(w, "Added station %q to %s\n", name, f.Path)
+	if makeDefault {
+		_, _ = fmt.Fprintf(w, "Default station is now %q\n", name)
+	}
+	return nil
+}
+
+func runConfigRemoveStation(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	f, err := openConfigFile()
+	if err != nil {
+		return err
+	}
+	if !f.Delete("stations." + name) {
+		return newError(KindNotFound, nil, "station %q not found in %s", name, f.Path)
+	}
+
+	newDefault := ""
+	changedDefault := false
+	if def, ok := f.Get("default_station"); ok && def.Value == name {
+		changedDefault = true
+		cfg, err := f.Config()
+		if err != nil {
+			return wrapConfigError(err)
+		}
+		if names := cfg.StationNames(); len(names) > 0 {
+			newDefault = names[0]
+			err = f.Set("default_station", newDefault)
+		} else {
+			f.Delete("default_station")
+		}
+		if err != nil {
+			return newError(KindConfig, err, "%v", err)
+		}
+	}
+
+	if err := saveConfigFile(f); err != nil {
+		return err
+	}
+	w := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(w, "Removed station %q from %s\n", name, f.Path)
+	switch {
+	case changedDefault && newDefault != "":
+		_, _ = fmt.Fprintf(w, "Default station is now %q\n", newDefault)
+	case changedDefault:
+		_, _ = fmt.Fprintln(w, "No stations left; add one with 'tempest config add-station'")
+	}
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+	f, err := openConfigFile()
+	if err != nil {
+		return err
+	}
+	if err := f.Set(key, value); err != nil {
+		return newError(KindUsage, err, "%v", err)
+	}
+	if err := saveConfigFile(f); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Set %s = %s\n", key, redactConfigValue(key, value))
+	return nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	if !viper.IsSet(key) {
+		return newError(KindNotFound, nil, "config key %q is not set", key)
+	}
+	value := viper.Get(key)
+	if show, _ := cmd.Flags().GetBool("show-secrets"); !show {
+		value = redactConfigTree(strings.ToLower(key), value)
+	}
+
+	if viper.GetBool("json") {
+		return jsonout.Write(cmd.OutOrStdout(), map[string]any{"key": key, "value": value})
+	}
+	if _, ok := value.(map[string]any); ok {
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, _ = cmd.OutOrStdout().Write(data)
+		return nil
+	}
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), value)
+	return nil
+}
+
+func runConfigUse(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	f, err := openConfigFile()
+	if err != nil {
+		return err
+	}
+	cfg, err := f.Config()
+	if err != nil {
+		return wrapConfigError(err)
+	}
+	if _, ok := cfg.Stations[name]; !ok {
+		return newError(KindNotFound, nil, "station %q not found; available: %s", name, strings.Join(cfg.StationNames(), ", "))
+	}
+	if err := f.Set("default_station", name); err != nil {
+		return newError(KindConfig, err, "%v", err)
+	}
+	if err := saveConfigFile(f); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Default station is now %q\n", name)
+	return nil
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	original, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return wrapConfigError(err)
+	}
+
+	tmp, err := os.CreateTemp("", "tempest-config-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	_, err = tmp.Write(original)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	in := bufio.NewReader(cmd.InOrStdin())
+	for {
+		if err := runEditor(cmd, tmp.Name()); err != nil {
+			return err
+		}
+		edited, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(edited, original) {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No changes made")
+			return nil
+		}
+
+		verr := validateConfigData(path, edited)
+		if verr == nil {
+			if err := config.WriteFileAtomic(path, edited); err != nil {
+				return wrapConfigError(err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Saved %s\n", path)
+			return nil
+		}
+
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Invalid config: %v\nEdit again? [Y/n] ", verr)
+		answer, _ := in.ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a == "n" || a == "no" {
+			return newError(KindConfig, verr, "changes discarded; %s was not modified", path)
+		}
+	}
+}
+
+// validateConfigData checks an edited config file before it replaces the
+// original.
+func validateConfigData(path string, data []byte) error {
+	f, err := config.ParseFile(path, data)
+	if err != nil {
+		return err
+	}
+	cfg, err := f.Config()
+	if err != nil {
+		return err
+	}
+	return cfg.ValidateSettings()
+}
+
+// runEditor opens path in $VISUAL or $EDITOR, which may include arguments.
+func runEditor(cmd *cobra.Command, path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	parts := strings.Fields(editor)
+	c := exec.CommandContext(cmd.Context(), parts[0], append(parts[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return newError(KindGeneral, err, "running editor %q: %v", editor, err)
+	}
+	return nil
+}
+
+// redactConfigValue hides secrets when echoing a value back.
+func redactConfigValue(key, value string) string {
+	switch key[strings.LastIndex(key, ".")+1:] {
+	case "token":
+		return config.RedactToken(value)
+	case "password":
+		return redactSecret(value)
+	}
+	return value
+}
+
+// redactConfigTree hides the secrets in value, the setting at key, which may
+// be a whole section such as tempestd or stations.<name>.
+func redactConfigTree(key string, value any) any {
+	switch v := value.(type) {
+	case string:
+		return redactConfigValue(key, v)
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for k, child := range v {
+			redacted[k] = redactConfigTree(key+"."+k, child)
+		}
+		return redacted
+	}
+	return value
+}
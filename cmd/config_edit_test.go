@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/spf13/viper"
+)
+
+// useConfigFile points viper at a config file in a temp dir containing data.
+func useConfigFile(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if data != "" {
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigFile(path)
+	return path
+}
+
+func loadConfigFile(t *testing.T, path string) *config.Config {
+	t.Helper()
+	f, err := config.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := f.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestConfigAddAndRemoveStation(t *testing.T) {
+	path := useConfigFile(t, "# my stations\nunits: metric\n")
+
+	flags := configAddStationCmd.Flags()
+	_ = flags.Set("token", "tok123456")
+	_ = flags.Set("station-id", "100")
+	_ = flags.Set("device-id", "200")
+	defer func() {
+		for _, name := range []string{"token", "token-file", "token-command", "station-id", "device-id", "name", "default"} {
+			_ = flags.Lookup(name).Value.Set(flags.Lookup(name).DefValue)
+		}
+	}()
+
+	var out bytes.Buffer
+	configAddStationCmd.SetOut(&out)
+	if err := runConfigAddStation(configAddStationCmd, []string{"home"}); err != nil {
+		t.Fatal(err)
+	}
+	_ = flags.Set("station-id", "101")
+	if err := runConfigAddStation(configAddStationCmd, []string{"cabin"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runConfigAddStation(configAddStationCmd, []string{"home"}); errorKind(err) != KindConfig {
+		t.Errorf("duplicate station: err = %v, want config error", err)
+	}
+	_ = flags.Set("token", "")
+	_ = flags.Set("token-command", "pass show tempest")
+	if err := runConfigAddStation(configAddStationCmd, []string{"shed"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := loadConfigFile(t, path)
+	if cfg.DefaultStation != "home" {
+		t.Errorf("default_station = %q, want the first station added", cfg.DefaultStation)
+	}
+	if sc := cfg.Stations["cabin"]; sc.StationID != 101 || sc.DeviceID != 200 || sc.Token != "tok123456" {
+		t.Errorf("cabin = %+v", sc)
+	}
+	if sc := cfg.Stations["shed"]; sc.Token != "" || sc.TokenCommand != "pass show tempest" {
+		t.Errorf("shed = %+v, want token_command only", sc)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "# my stations\n") {
+		t.Errorf("comment lost:\n%s", data)
+	}
+
+	configRemoveStationCmd.SetOut(&out)
+	if err := runConfigRemoveStation(configRemoveStationCmd, []string{"home"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runConfigRemoveStation(configRemoveStationCmd, []string{"home"}); errorKind(err) != KindNotFound {
+		t.Errorf("removing a missing station: err = %v, want not_found", err)
+	}
+	cfg = loadConfigFile(t, path)
+	if _, ok := cfg.Stations["home"]; ok || cfg.DefaultStation != "cabin" {
+		t.Errorf("after remove: default = %q, stations = %v", cfg.DefaultStation, cfg.StationNames())
+	}
+}
+
+func TestConfigSetAndUse(t *testing.T) {
+	path := useConfigFile(t, `default_station: home
+stations:
+  home: {token: a, station_id: 1}
+  cabin: {token: b, station_id: 2}
+`)
+	var out bytes.Buffer
+	configSetCmd.SetOut(&out)
+	configUseCmd.SetOut(&out)
+
+	if err := runConfigSet(configSetCmd, []string{"stations.home.token", "secret-token"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "secret-token") {
+		t.Errorf("token echoed in clear: %q", out.String())
+	}
+	if err := runConfigSet(configSetCmd, []string{"units", "furlongs"}); errorKind(err) != KindConfig {
+		t.Errorf("invalid units: err = %v, want config error", err)
+	}
+	if err := runConfigUse(configUseCmd, []string{"cabin"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runConfigUse(configUseCmd, []string{"office"}); errorKind(err) != KindNotFound {
+		t.Errorf("unknown station: err = %v, want not_found", err)
+	}
+
+	cfg := loadConfigFile(t, path)
+	if cfg.DefaultStation != "cabin" || cfg.Stations["home"].Token != "secret-token" || cfg.Units != "" {
+		t.Errorf("config = %+v", cfg)
+	}
+}
+
+func TestConfigGet(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigType("yaml")
+	_ = viper.ReadConfig(strings.NewReader("units: metric\nstations:\n  home: {station_id: 12345}\n"))
+
+	var out bytes.Buffer
+	configGetCmd.SetOut(&out)
+	if err := runConfigGet(configGetCmd, []string{"stations.home.station_id"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out.String()) != "12345" {
+		t.Errorf("output = %q, want 12345", out.String())
+	}
+	if err := runConfigGet(configGetCmd, []string{"tempestd.server"}); errorKind(err) != KindNotFound {
+		t.Errorf("unset key: err = %v, want not_found", err)
+	}
+}
+
+func TestConfigGetRedactsSecrets(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigType("yaml")
+	_ = viper.ReadConfig(strings.NewReader(`stations:
+  home: {token: hometoken1234, station_id: 1}
+tempestd:
+  token: daemon-secret
+  password: hunter2
+`))
+
+	var out bytes.Buffer
+	configGetCmd.SetOut(&out)
+	for _, key := range []string{"stations.home.token", "stations.home", "stations", "tempestd", "tempestd.password"} {
+		out.Reset()
+		if err := runConfigGet(configGetCmd, []string{key}); err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{"hometoken1234", "daemon-secret", "hunter2"} {
+			if strings.Contains(out.String(), secret) {
+				t.Errorf("config get %s printed %s:\n%s", key, secret, out.String())
+			}
+		}
+	}
+	if !strings.Contains(out.String(), "****") {
+		t.Errorf("password not shown as redacted: %q", out.String())
+	}
+
+	_ = configGetCmd.Flags().Set("show-secrets", "true")
+	defer func() { _ = configGetCmd.Flags().Set("show-secrets", "false") }()
+	out.Reset()
+	if err := runConfigGet(configGetCmd, []string{"tempestd"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "daemon-secret") || !strings.Contains(out.String(), "hunter2") {
+		t.Errorf("--show-secrets output = %q", out.String())
+	}
+}
+
+func TestConfigEdit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("editor script requires a POSIX shell")
+	}
+	original := "default_station: home\nstations:\n  home: {token: a, station_id: 1}\n"
+	path := useConfigFile(t, original)
+
+	dir := t.TempDir()
+	editor := filepath.Join(dir, "editor.sh")
+	script := "#!/bin/sh\ncat \"$(dirname \"$0\")/next\" > \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", editor)
+
+	// An invalid edit is rejected and the file is left alone.
+	_ = os.WriteFile(filepath.Join(dir, "next"), []byte("default_station: nowhere\n"), 0600)
+	configEditCmd.SetContext(context.Background())
+	configEditCmd.SetIn(strings.NewReader("n\n"))
+	configEditCmd.SetOut(&bytes.Buffer{})
+	configEditCmd.SetErr(&bytes.Buffer{})
+	if err := runConfigEdit(configEditCmd, nil); errorKind(err) != KindConfig {
+		t.Errorf("invalid edit: err = %v, want config error", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("invalid edit modified the file:\n%s", data)
+	}
+
+	edited := original + "units: metric\n"
+	_ = os.WriteFile(filepath.Join(dir, "next"), []byte(edited), 0600)
+	if err := runConfigEdit(configEditCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != edited {
+		t.Errorf("file = %q, want %q", data, edited)
+	}
+}
@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/units"
+	tempest "github.com/chadmayfield/tempest-go"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const existingConfig = `# shared config
+default_station: home
+units: imperial
+stations:
+  home:
+    token: hometoken
+    station_id: 1
+    stale_after: 2h
+  office:
+    token: officetoken
+    station_id: 2
+tempestd:
+  server: http://nas.local:8080
+  token: daemon-secret
+`
+
+// wizardInput drives the wizard with keystrokes; "\n" presses Enter and
+// "↓" moves down a menu.
+func wizardInput(t *testing.T, m initModel, keys string) initModel {
+	t.Helper()
+	for _, r := range keys {
+		var msg tea.KeyMsg
+		switch r {
+		case '\n':
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case '↓':
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		}
+		next, _ := m.Update(msg)
+		m = next.(initModel)
+	}
+	return m
+}
+
+func wizardFetched(m initModel, id int, name string) initModel {
+	next, _ := m.Update(stationFetchedMsg{station: &tempest.Station{
+		StationID: id,
+		Name:      name,
+		Devices:   []tempest.Device{{DeviceID: id * 10, DeviceType: "ST"}},
+	}})
+	return next.(initModel)
+}
+
+func wizardDiscovered(m initModel, stations []tempest.Station, err error) initModel {
+	next, _ := m.Update(stationsDiscoveredMsg{stations: stations, err: err})
+	return next.(initModel)
+}
+
+func TestInitWizardAddsStations(t *testing.T) {
+	f, err := config.ParseFile("config.yaml", []byte(existingConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newInitModel(f, []string{"home", "office"})
+	if m.step != stepMode {
+		t.Fatalf("step = %v, want stepMode for an existing config", m.step)
+	}
+
+	// Add a discovered station, then a second one reusing the token and
+	// entering its ID by hand.
+	m = wizardInput(t, m, "\nnewtoken\n")
+	if m.step != stepDiscover {
+		t.Fatalf("step = %v, want stepDiscover after the token", m.step)
+	}
+	m = wizardDiscovered(m, []tempest.Station{{
+		StationID: 3,
+		Name:      "Cabin Lake",
+		Devices:   []tempest.Device{{DeviceID: 29, DeviceType: "HB"}, {DeviceID: 30, DeviceType: "ST"}},
+	}}, nil)
+	m = wizardInput(t, m, "\n")
+	if m.step != stepNameStation || m.deviceID != 30 {
+		t.Fatalf("step = %v, device = %d; want the only sensor chosen, not the hub", m.step, m.deviceID)
+	}
+	m = wizardInput(t, m, "\ny\n\n")
+	if m.token != "newtoken" {
+		t.Errorf("token = %q, want the previous token reused", m.token)
+	}
+	m = wizardDiscovered(m, nil, errors.New("connection refused"))
+	if m.step != stepStationID {
+		t.Fatalf("step = %v, want manual station ID entry after a discovery failure", m.step)
+	}
+	m = wizardInput(t, m, "4\n")
+	m = wizardFetched(m, 4, "Barn")
+
+	// A name that is already taken is rejected.
+	m.input = "office"
+	m = wizardInput(t, m, "\n")
+	if m.err == nil || m.step != stepNameStation {
+		t.Fatalf("duplicate name accepted: step = %v", m.step)
+	}
+	m.input = "barn"
+	// Finish: no more stations, metric units, keep the tempestd server.
+	m = wizardInput(t, m, "\nn\n↓\n")
+	if m.step != stepTempestd || m.input != "http://nas.local:8080" {
+		t.Fatalf("step = %v, input = %q; want the tempestd step pre-filled", m.step, m.input)
+	}
+	m = wizardInput(t, m, "\n")
+	if m.step != stepWriting {
+		t.Fatalf("step = %v, want stepWriting", m.step)
+	}
+
+	if err := m.apply(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := f.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(cfg.StationNames(), ","); got != "barn,cabin-lake,home,office" {
+		t.Errorf("stations = %s", got)
+	}
+	if cfg.DefaultStation != "home" || cfg.Units != "metric" {
+		t.Errorf("default = %q, units = %q", cfg.DefaultStation, cfg.Units)
+	}
+	if cfg.Tempestd.Token != "daemon-secret" || cfg.Stations["home"].StaleAfter == 0 {
+		t.Errorf("existing settings lost: %+v", cfg)
+	}
+	if sc := cfg.Stations["barn"]; sc.Token != "newtoken" || sc.StationID != 4 || sc.DeviceID != 40 {
+		t.Errorf("barn = %+v", sc)
+	}
+	if sc := cfg.Stations["cabin-lake"]; sc.DeviceID != 30 {
+		t.Errorf("cabin-lake device_id = %d, want the sensor", sc.DeviceID)
+	}
+	data, _ := f.Bytes()
+	if !strings.HasPrefix(string(data), "# shared config") {
+		t.Errorf("comment lost:\n%s", data)
+	}
+}
+
+func TestInitWizardReplacesStation(t *testing.T) {
+	f, err := config.ParseFile("config.yaml", []byte(existingConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newInitModel(f, []string{"home", "office"})
+
+	// Replace "home" (the default) and rename it, then drop the server.
+	m = wizardInput(t, m, "↓\n\nnewtoken\n")
+	m = wizardDiscovered(m, nil, errors.New("timeout"))
+	m = wizardInput(t, m, "9\n")
+	m = wizardFetched(m, 9, "New Home")
+	if m.input != "home" {
+		t.Errorf("name suggestion = %q, want the replaced station's name", m.input)
+	}
+	m.input = "house"
+	m = wizardInput(t, m, "\n\n\n")
+	m.input = ""
+	m = wizardInput(t, m, "\n")
+
+	if err := m.apply(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := f.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(cfg.StationNames(), ","); got != "house,office" {
+		t.Errorf("stations = %s", got)
+	}
+	if cfg.DefaultStation != "house" {
+		t.Errorf("default = %q, want it to follow the replaced station", cfg.DefaultStation)
+	}
+	if cfg.EffectiveServerURL() != "" || cfg.Tempestd.Token != "daemon-secret" {
+		t.Errorf("tempestd = %+v, want server removed and auth kept", cfg.Tempestd)
+	}
+}
+
+func TestInitWizardReplacesStationKeepingName(t *testing.T) {
+	f, err := config.ParseFile("config.yaml", []byte(`default_station: home
+stations:
+  home:
+    token_file: /run/secrets/tempest
+    station_id: 1
+    elevation: 1500
+    timezone: America/Denver
+    unit: {temp: c}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newInitModel(f, []string{"home"})
+
+	// Replace "home" with another station under the same name.
+	m = wizardInput(t, m, "↓\n\nnewtoken\n")
+	m = wizardDiscovered(m, nil, errors.New("timeout"))
+	m = wizardInput(t, m, "9\n")
+	m = wizardFetched(m, 9, "Cabin")
+	m = wizardInput(t, m, "\n\n\n\n")
+
+	if err := m.apply(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := f.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("replaced config does not load: %v", err)
+	}
+	sc := cfg.Stations["home"]
+	if sc.Token != "newtoken" || sc.StationID != 9 || sc.TokenFile != "" {
+		t.Errorf("home = %+v, want the new token and station", sc)
+	}
+	if sc.Elevation != nil || sc.Timezone != "" || sc.Unit != (units.Set{}) {
+		t.Errorf("home kept the old station's settings: %+v", sc)
+	}
+}
+
+func TestInitWizardFreshConfig(t *testing.T) {
+	f, err := config.ParseFile("config.yaml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newInitModel(f, nil)
+	if m.step != stepToken {
+		t.Fatalf("step = %v, want stepToken with no existing stations", m.step)
+	}
+	m = wizardInput(t, m, "bad\n")
+	m = wizardDiscovered(m, nil, &Error{Kind: KindAuth, Msg: "unauthorized"})
+	if m.step != stepToken || m.err == nil {
+		t.Fatalf("step = %v, want the token step again after an auth failure", m.step)
+	}
+
+	// A station with two sensors asks which one to use.
+	m = wizardInput(t, m, "tok\n")
+	m = wizardDiscovered(m, []tempest.Station{{
+		StationID: 5,
+		Name:      "Home",
+		Devices: []tempest.Device{
+			{DeviceID: 50, DeviceType: "HB"},
+			{DeviceID: 51, DeviceType: "AR"},
+			{DeviceID: 52, DeviceType: "ST"},
+		},
+	}}, nil)
+	m = wizardInput(t, m, "\n")
+	if m.step != stepPickDevice {
+		t.Fatalf("step = %v, want stepPickDevice", m.step)
+	}
+	if !strings.Contains(m.View(), "> Tempest") {
+		t.Errorf("Tempest sensor not offered first:\n%s", m.View())
+	}
+	m = wizardInput(t, m, "↓\n")
+	if m.deviceID != 51 {
+		t.Errorf("device = %d, want the selected Air sensor", m.deviceID)
+	}
+	m = wizardInput(t, m, "\n\n\n")
+	m.input = "ftp://bad"
+	m = wizardInput(t, m, "\n")
+	if m.err == nil || m.step != stepTempestd {
+		t.Fatalf("invalid server URL accepted")
+	}
+	m.input = ""
+	m = wizardInput(t, m, "\n")
+
+	if err := m.apply(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := f.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DefaultStation != "home" || cfg.Units != "imperial" {
+		t.Errorf("config = %+v", cfg)
+	}
+}
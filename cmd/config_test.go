@@ -42,6 +42,10 @@ func TestRedactedConfig(t *testing.T) {
 				DeviceID:  9876,
 				Name:      "Office",
 			},
+			"cabin": {
+				TokenCommand: "pass show tempest",
+				StationID:    11111,
+			},
 		},
 	}
 
@@ -64,6 +68,12 @@ func TestRedactedConfig(t *testing.T) {
 	if stations["office"].Token != "****" {
 		t.Errorf("office token = %q, want %q (short token redaction)", stations["office"].Token, "****")
 	}
+	if cabin := stations["cabin"]; cabin.Token != "" || cabin.TokenSource != "command pass show tempest" {
+		t.Errorf("cabin token = %q from %q, want no token and the command as source", cabin.Token, cabin.TokenSource)
+	}
+	if stations["home"].TokenSource != "config" {
+		t.Errorf("home token_source = %q, want config", stations["home"].TokenSource)
+	}
 	if stations["home"].StationID != 12345 {
 		t.Errorf("home StationID = %d, want 12345", stations["home"].StationID)
 	}
@@ -100,3 +110,35 @@ stations:
 		t.Error("expected non-empty output")
 	}
 }
+
+func TestRedactedConfigTempestd(t *testing.T) {
+	cfg := &config.Config{
+		Tempestd: config.TempestdConfig{
+			Server:   "https://tempestd.lan",
+			Token:    "secrettoken123",
+			CAFile:   "/etc/tempest/ca.pem",
+			CertFile: "/etc/tempest/client.pem",
+			KeyFile:  "/etc/tempest/client-key.pem",
+		},
+	}
+
+	td, ok := redactedConfig(cfg)["tempestd"].(redactedTempestdConfig)
+	if !ok {
+		t.Fatal("tempestd not found or wrong type")
+	}
+	if td.Token != "secr****" {
+		t.Errorf("token = %q, want %q", td.Token, "secr****")
+	}
+	if td.CAFile != "/etc/tempest/ca.pem" {
+		t.Errorf("ca_file = %q", td.CAFile)
+	}
+
+	cfg.Tempestd = config.TempestdConfig{Username: "admin", Password: "hunter2"}
+	td = redactedConfig(cfg)["tempestd"].(redactedTempestdConfig)
+	if td.Token != "" {
+		t.Errorf("unset token = %q, want empty", td.Token)
+	}
+	if td.Username != "admin" || td.Password != "****" {
+		t.Errorf("basic auth = %q/%q, want admin/****", td.Username, td.Password)
+	}
+}
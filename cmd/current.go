@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"slices"
 	"time"
 
 	"github.com/chadmayfield/tempest-cli/internal/config"
 	"github.com/chadmayfield/tempest-cli/internal/display"
 	jsonout "github.com/chadmayfield/tempest-cli/internal/json"
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	tempest "github.com/chadmayfield/tempest-go"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -24,6 +26,8 @@ var currentCmd = &cobra.Command{
 }
 
 func init() {
+	currentCmd.Flags().Bool("all", false, "show every configured station side by side; --station also takes a comma-separated list or a group")
+	addFormatFlag(currentCmd)
 	rootCmd.AddCommand(currentCmd)
 
 	// Make current the default command when no subcommand is given
@@ -33,7 +37,21 @@ func init() {
 }
 
 func runCurrent(cmd *cobra.Command, args []string) error {
+	if oneline, _ := cmd.Flags().GetBool("oneline"); oneline {
+		// The one-line summary covers a single station in plain text only.
+		for _, name := range []string{"all", "format"} {
+			if cmd.Flags().Changed(name) {
+				return usageError(fmt.Errorf("--oneline cannot be used with --%s", name))
+			}
+		}
+		return runBar(cmd, args)
+	}
+
 	ctx := cmd.Context()
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
@@ -41,67 +59,245 @@ func runCurrent(cmd *cobra.Command, args []string) error {
 	}
 
 	stationName := viper.GetString("station")
+	if all, _ := cmd.Flags().GetBool("all"); all || cfg.IsStationList(stationName) {
+		names, err := currentStationNames(cfg, stationName, all)
+		if err != nil {
+			return err
+		}
+		return runCurrentGrid(cmd, cfg, names, format)
+	}
 	sc, err := cfg.ResolveStation(stationName)
 	if err != nil {
 		return wrapConfigError(err)
 	}
 
-	serverURL := resolveServerURL(cfg)
-
-	var obs *tempest.StationObservation
-	var station *tempest.Station
-
-	units := "metric"
-	if cfg.IsImperial() {
-		units = "imperial"
+	u, err := resolveUnits(cfg, sc)
+	if err != nil {
+		return err
 	}
 
-	slog.Debug("fetching current conditions", "station_id", sc.StationID, "server", serverURL)
-	if serverURL != "" {
-		obs, station, err = fetchCurrentFromServer(ctx, serverURL, sc.StationID, units)
-	} else {
-		obs, station, err = fetchCurrentFromAPI(ctx, sc)
-	}
+	data, meta, err := fetchCurrent(ctx, cfg, sc)
 	if err != nil {
 		return wrapAPIError(err)
 	}
-
-	imperial := cfg.IsImperial()
+	obs, station := data.Observation, data.Station
 
 	if viper.GetBool("json") {
-		return jsonout.Write(cmd.OutOrStdout(), currentJSON(obs, station, sc, units, imperial))
+		out := currentJSON(obs, station, sc, u)
+		out.sourceJSON = meta.json()
+		return staleResult(meta, jsonout.Write(cmd.OutOrStdout(), out))
+	}
+	theme, err := newTheme()
+	if err != nil {
+		return err
 	}
-	noColor := viper.GetBool("no-color")
-	theme := display.NewTheme(noColor, display.WithNoEmoji(viper.GetBool("no-emoji")))
 
 	displayName := station.Name
 	if displayName == "" {
 		displayName = sc.Name
 	}
+	if format != display.FormatText {
+		doc := display.CurrentDocument(theme, obs, displayName, u)
+		return staleResult(meta, writeDocument(cmd.OutOrStdout(), theme, format, doc, &meta))
+	}
 
 	termWidth := 80
 	if w, _, err := term.GetSize(0); err == nil && w > 0 {
 		termWidth = w
 	}
 
-	output := display.RenderCurrent(theme, obs, displayName, imperial, termWidth)
+	output := display.RenderCurrent(theme, obs, displayName, u, termWidth)
 	_, _ = fmt.Fprintln(cmd.OutOrStdout(), output)
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), display.RenderSource(theme, string(meta.Source), meta.FetchedAt, meta.Stale))
 
-	return nil
+	return staleResult(meta, nil)
 }
 
-func fetchCurrentFromAPI(ctx context.Context, sc *config.StationConfig) (*tempest.StationObservation, *tempest.Station, error) {
-	client, err := tempest.NewClient(sc.Token)
+// currentStationNames returns the stations to show in the grid: all of them
+// with --all, or else those named by the --station list or group.
+func currentStationNames(cfg *config.Config, spec string, all bool) ([]string, error) {
+	if !all {
+		names, err := cfg.ResolveStationList(spec)
+		if err != nil {
+			return nil, wrapConfigError(err)
+		}
+		return names, nil
+	}
+	if spec != "" {
+		return nil, usageError(fmt.Errorf("--all cannot be used with --station"))
+	}
+	if len(cfg.Stations) == 0 {
+		return nil, newError(KindConfig, nil, "no stations configured; run 'tempest config init' to set up")
+	}
+	return cfg.StationNames(), nil
+}
+
+// runCurrentGrid shows current conditions for several stations as cards.
+// Stations are fetched concurrently; one that fails gets an error card
+// rather than failing the command, unless every station fails.
+func runCurrentGrid(cmd *cobra.Command, cfg *config.Config, names []string, format display.Format) error {
+	ctx := cmd.Context()
+	serverURL := resolveServerURL(cfg)
+
+	cards := make([]display.StationCard, len(names))
+	out := make([]any, len(names))
+	errs := make([]error, len(names))
+	forEachStation(ctx, names, defaultStationTimeout, func(ctx context.Context, i int, name string) {
+		sc := cfg.Stations[name]
+		cards[i], out[i], errs[i] = currentCard(ctx, cfg, serverURL, name, &sc)
+	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var failed error
+	if !slices.ContainsFunc(errs, func(err error) bool { return err == nil }) {
+		// Every station's error is already in the output, so only the exit
+		// code reports the failure.
+		failed = &Error{
+			Kind:  errorKind(errs[0]),
+			Msg:   fmt.Sprintf("all %d stations failed: %v", len(names), errs[0]),
+			Err:   errs[0],
+			Quiet: true,
+		}
+	}
+
+	if viper.GetBool("json") {
+		if err := jsonout.Write(cmd.OutOrStdout(), out); err != nil {
+			return err
+		}
+		return failed
+	}
+	theme, err := newTheme()
+	if err != nil {
+		return err
+	}
+	if format != display.FormatText {
+		if err := writeDocument(cmd.OutOrStdout(), theme, format, display.CurrentGridDocument(theme, cards), nil); err != nil {
+			return err
+		}
+		return failed
+	}
+
+	termWidth := 80
+	if w, _, err := term.GetSize(0); err == nil && w > 0 {
+		termWidth = w
+	}
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), display.RenderCurrentGrid(theme, cards, termWidth))
+	return failed
+}
+
+// currentCard fetches one station's current conditions for the grid. It
+// returns the station's card and JSON, and the error if the fetch failed,
+// in which case both describe the failure.
+func currentCard(ctx context.Context, cfg *config.Config, serverURL, name string, sc *config.StationConfig) (display.StationCard, any, error) {
+	card := display.StationCard{Name: sc.Name}
+	if card.Name == "" {
+		card.Name = name
+	}
+	fail := func(err error) (display.StationCard, any, error) {
+		slog.Debug("station fetch failed", "station_id", sc.StationID, "error", err)
+		card.Reason = stationReason(err)
+		return card, currentErrorJSON{
+			Station: stationMeta{Name: card.Name, StationID: sc.StationID, DeviceID: sc.DeviceID},
+			Reason:  card.Reason,
+			Error:   err.Error(),
+		}, err
+	}
+
+	u, err := resolveUnits(cfg, sc)
+	if err != nil {
+		return fail(err)
+	}
+	data, meta, err := fetchWithFallback(ctx, serverURL, currentFetchers(sc))
 	if err != nil {
-		return nil, nil, fmt.Errorf("creating API client: %w", err)
+		return fail(wrapAPIError(err))
+	}
+	if data.Station != nil && data.Station.Name != "" {
+		card.Name = data.Station.Name
+	}
+	obs := data.Observation
+	if obs == nil || obs.Timestamp.IsZero() {
+		return fail(newError(KindNotFound, nil, "station %d has no observations", sc.StationID))
+	}
+	card.Observation = obs
+	card.Units = u
+	card.Online = time.Since(obs.Timestamp) < cfg.StaleThreshold(sc)
+	card.Source, card.FetchedAt, card.Stale = string(meta.Source), meta.FetchedAt, meta.Stale
+
+	j := currentJSON(obs, data.Station, sc, u)
+	j.sourceJSON = meta.json()
+	return card, currentGridJSON{currentJSONOutput: j, Online: card.Online}, nil
+}
+
+// currentGridJSON is a station in the grid's JSON array.
+type currentGridJSON struct {
+	currentJSONOutput
+	Online bool `json:"online"`
+}
+
+// currentErrorJSON stands in the grid's JSON array for a station that could
+// not be fetched.
+type currentErrorJSON struct {
+	Station stationMeta `json:"station"`
+	Reason  string      `json:"reason"`
+	Error   string      `json:"error"`
+}
+
+// currentData is an observation together with its station's metadata.
+type currentData struct {
+	Observation *tempest.StationObservation `json:"observation"`
+	Station     *tempest.Station            `json:"station"`
+}
+
+// fetchCurrent fetches current conditions from the first data source that
+// succeeds. Observations are always requested in metric units so that data
+// from every source can be converted the same way for display.
+func fetchCurrent(ctx context.Context, cfg *config.Config, sc *config.StationConfig) (*currentData, fetchMeta, error) {
+	serverURL := resolveServerURL(cfg)
+	slog.Debug("fetching current conditions", "station_id", sc.StationID, "server", serverURL)
+	fetchers := currentFetchers(sc)
+	fetchers.UDP = func(ctx context.Context) (*currentData, error) {
+		return fetchCurrentFromUDP(ctx, serverURL, sc)
+	}
+	return fetchWithFallback(ctx, serverURL, fetchers)
+}
+
+// currentFetchers returns the tempestd and cloud fetchers for sc's current
+// conditions. UDP is left to the caller, since a broadcast does not say which
+// station it came from.
+func currentFetchers(sc *config.StationConfig) sourceFetchers[currentData] {
+	return sourceFetchers[currentData]{
+		Tempestd: func(ctx context.Context, serverURL string) (*currentData, error) {
+			obs, station, err := fetchCurrentFromServer(ctx, serverURL, sc.StationID, "metric")
+			if err != nil {
+				return nil, err
+			}
+			return &currentData{Observation: obs, Station: station}, nil
+		},
+		Cloud: func(ctx context.Context) (*currentData, error) {
+			obs, station, err := fetchCurrentFromAPI(ctx, sc)
+			if err != nil {
+				return nil, err
+			}
+			return &currentData{Observation: obs, Station: station}, nil
+		},
 	}
+}
 
-	obs, err := client.GetStationObservation(ctx, sc.StationID)
+func fetchCurrentFromAPI(ctx context.Context, sc *config.StationConfig) (*tempest.StationObservation, *tempest.Station, error) {
+	client := lazyStationClient(ctx, sc)
+	obs, err := cachedFetch(ctx, cloudCacheKey("current", sc), currentTTL(), func() (*tempest.StationObservation, error) {
+		c, err := client()
+		if err != nil {
+			return nil, fmt.Errorf("creating API client: %w", err)
+		}
+		return c.GetStationObservation(ctx, sc.StationID)
+	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("fetching observation: %w", err)
 	}
 
-	station, err := client.GetStation(ctx, sc.StationID)
+	station, err := fetchStationFromAPI(ctx, sc, client)
 	if err != nil {
 		return nil, nil, fmt.Errorf("fetching station: %w", err)
 	}
@@ -110,8 +306,10 @@ func fetchCurrentFromAPI(ctx context.Context, sc *config.StationConfig) (*tempes
 }
 
 type currentJSONOutput struct {
+	sourceJSON
 	Station               stationMeta `json:"station"`
 	Units                 string      `json:"units"`
+	Unit                  units.Set   `json:"unit"`
 	Timestamp             time.Time   `json:"timestamp"`
 	Temperature           float64     `json:"temperature"`
 	FeelsLike             float64     `json:"feels_like"`
@@ -138,29 +336,11 @@ type stationMeta struct {
 	DeviceID  int    `json:"device_id"`
 }
 
-func currentJSON(obs *tempest.StationObservation, station *tempest.Station, sc *config.StationConfig, units string, imperial bool) currentJSONOutput {
-	temp := obs.AirTemperature
-	feels := obs.FeelsLike
-	dew := obs.DewPoint
-	wind := obs.WindAvg
-	gust := obs.WindGust
-	lull := obs.WindLull
-	pressure := obs.SeaLevelPressure
-	rain := obs.PrecipAccumDay
-	lightningDist := obs.LightningStrikeLastDistance
-	if imperial {
-		temp = tempest.CelsiusToFahrenheit(temp)
-		feels = tempest.CelsiusToFahrenheit(feels)
-		dew = tempest.CelsiusToFahrenheit(dew)
-		wind = tempest.MpsToMph(wind)
-		gust = tempest.MpsToMph(gust)
-		lull = tempest.MpsToMph(lull)
-		pressure = tempest.HpaToInhg(pressure)
-		rain = tempest.MmToInches(rain)
-		lightningDist = tempest.KmToMiles(lightningDist)
-	}
-
+func currentJSON(obs *tempest.StationObservation, station *tempest.Station, sc *config.StationConfig, u units.Set) currentJSONOutput {
 	deviceID := sc.DeviceID
+	if d, err := pickSensor(station, sc.DeviceID, ""); err == nil {
+		deviceID = d.DeviceID
+	}
 	stationName := station.Name
 	if stationName == "" {
 		stationName = sc.Name
@@ -172,39 +352,62 @@ func currentJSON(obs *tempest.StationObservation, station *tempest.Station, sc *
 			StationID: sc.StationID,
 			DeviceID:  deviceID,
 		},
-		Units:                 units,
+		Units:                 u.System(),
+		Unit:                  u,
 		Timestamp:             obs.Timestamp,
-		Temperature:           temp,
-		FeelsLike:             feels,
-		DewPoint:              dew,
+		Temperature:           u.Temp(obs.AirTemperature),
+		FeelsLike:             u.Temp(obs.FeelsLike),
+		DewPoint:              u.Temp(obs.DewPoint),
 		Humidity:              obs.RelativeHumidity,
-		WindSpeed:             wind,
-		WindGust:              gust,
-		WindLull:              lull,
+		WindSpeed:             u.Speed(obs.WindAvg),
+		WindGust:              u.Speed(obs.WindGust),
+		WindLull:              u.Speed(obs.WindLull),
 		WindDirection:         obs.WindDirection,
 		WindDirectionCardinal: tempest.WindDirectionToCompass(obs.WindDirection),
-		Pressure:              pressure,
+		Pressure:              u.Press(obs.SeaLevelPressure),
 		PressureTrend:         obs.PressureTrend,
 		UVIndex:               obs.UV,
 		SolarRadiation:        obs.SolarRadiation,
-		RainToday:             rain,
+		RainToday:             u.Precip(obs.PrecipAccumDay),
 		LightningCount:        obs.LightningCount3hr,
-		LightningDistance:     lightningDist,
+		LightningDistance:     u.Dist(obs.LightningStrikeLastDistance),
 	}
 }
 
 func fetchCurrentFromServer(ctx context.Context, serverURL string, stationID int, units string) (*tempest.StationObservation, *tempest.Station, error) {
 	params := url.Values{}
 	params.Set("units", units)
-	obs, err := fetchFromTempestd[tempest.StationObservation](ctx, serverURL, fmt.Sprintf("/api/v1/stations/%d/current?%s", stationID, params.Encode()))
+	obs, err := cachedFetch(ctx, cacheKey("current", serverURL, stationID, params.Encode()), currentTTL(), func() (*tempest.StationObservation, error) {
+		return fetchFromTempestd[tempest.StationObservation](ctx, serverURL, fmt.Sprintf("/api/v1/stations/%d/current?%s", stationID, params.Encode()))
+	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("fetching observation from tempestd: %w", err)
 	}
 
-	station, err := fetchFromTempestd[tempest.Station](ctx, serverURL, fmt.Sprintf("/api/v1/stations/%d", stationID))
+	station, err := fetchStationFromServer(ctx, serverURL, stationID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("fetching station from tempestd: %w", err)
 	}
 
 	return obs, station, nil
 }
+
+// fetchStationFromAPI returns station metadata from the cloud API, cached for stationTTL.
+func fetchStationFromAPI(ctx context.Context, sc *config.StationConfig, client func() (*cloudClient, error)) (*tempest.Station, error) {
+	ctx = withoutFetchInfo(ctx)
+	return cachedFetch(ctx, cloudCacheKey("station", sc), stationTTL(), func() (*tempest.Station, error) {
+		c, err := client()
+		if err != nil {
+			return nil, err
+		}
+		return c.GetStation(ctx, sc.StationID)
+	})
+}
+
+// fetchStationFromServer returns station metadata from tempestd, cached for stationTTL.
+func fetchStationFromServer(ctx context.Context, serverURL string, stationID int) (*tempest.Station, error) {
+	ctx = withoutFetchInfo(ctx)
+	return cachedFetch(ctx, cacheKey("station", serverURL, stationID), stationTTL(), func() (*tempest.Station, error) {
+		return fetchFromTempestd[tempest.Station](ctx, serverURL, fmt.Sprintf("/api/v1/stations/%d", stationID))
+	})
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/fakeserver"
+	"github.com/spf13/viper"
+)
+
+// setupCurrentGrid configures alpha as fresh, echo as two hours old and
+// bravo as refused by the server.
+func setupCurrentGrid(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	t.Setenv("LC_ALL", "C")
+	// Every fake station last reported two hours ago; alpha tolerates that.
+	now := time.Now().Add(-2 * time.Hour)
+	out := setupFakeServer(t, currentCmd, fakeserver.Options{
+		Now:    func() time.Time { return now },
+		Faults: []fakeserver.Fault{{Status: http.StatusUnauthorized, Path: "/stations/1003"}},
+	}, "tempestd")
+	viper.Set("json", false)
+	_ = viper.ReadConfig(strings.NewReader(`
+stations:
+  echo:  {token: fake, station_id: 1002}
+  bravo: {token: fake, station_id: 1003, name: Bravo}
+  alpha: {token: fake, station_id: 1001, stale_after: 3h}
+groups:
+  pair: [echo, alpha]
+`))
+	t.Cleanup(func() {
+		_ = currentCmd.Flags().Set("all", "false")
+		currentCmd.Flags().Lookup("all").Changed = false
+	})
+	return out
+}
+
+func TestRunCurrentAllJSON(t *testing.T) {
+	out := setupCurrentGrid(t)
+	viper.Set("json", true)
+	_ = currentCmd.Flags().Set("all", "true")
+
+	if err := runCurrent(currentCmd, nil); err != nil {
+		t.Fatalf("runCurrent() error: %v", err)
+	}
+	var got []struct {
+		Station struct {
+			Name      string `json:"name"`
+			StationID int    `json:"station_id"`
+		} `json:"station"`
+		Online *bool  `json:"online"`
+		Reason string `json:"reason"`
+		Error  string `json:"error"`
+		Source string `json:"source"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d stations, want 3", len(got))
+	}
+	// Stations are listed in config name order: alpha, bravo, echo.
+	if g := got[0]; g.Station.Name != "Fake Home" || g.Online == nil || !*g.Online || g.Source != "tempestd" {
+		t.Errorf("alpha = %+v", g)
+	}
+	if g := got[1]; g.Station.StationID != 1003 || g.Reason != "auth failed" || g.Error == "" || g.Online != nil {
+		t.Errorf("bravo = %+v", g)
+	}
+	if g := got[2]; g.Station.Name != "Fake Coast" || g.Online == nil || *g.Online {
+		t.Errorf("echo = %+v", g)
+	}
+}
+
+func TestRunCurrentGroup(t *testing.T) {
+	out := setupCurrentGrid(t)
+	viper.Set("station", "pair")
+
+	if err := runCurrent(currentCmd, nil); err != nil {
+		t.Fatalf("runCurrent() error: %v", err)
+	}
+	text := out.String()
+	echo, alpha := strings.Index(text, "Fake Coast"), strings.Index(text, "Fake Home")
+	if echo < 0 || alpha < 0 || echo > alpha {
+		t.Errorf("group should show echo then alpha:\n%s", text)
+	}
+	if !strings.Contains(text, "Offline") || strings.Contains(text, "Bravo") {
+		t.Errorf("unexpected grid:\n%s", text)
+	}
+}
+
+func TestRunCurrentGridAllFail(t *testing.T) {
+	out := setupCurrentGrid(t)
+	viper.Set("station", "bravo,bravo")
+
+	err := runCurrent(currentCmd, nil)
+	if errorKind(err) != KindAuth {
+		t.Errorf("err = %v, want an auth error", err)
+	}
+	if text := out.String(); strings.Count(text, "Unavailable: auth failed") != 1 {
+		t.Errorf("want one error card:\n%s", text)
+	}
+}
+
+func TestRunCurrentGridAllFailJSON(t *testing.T) {
+	out := setupCurrentGrid(t)
+	viper.Set("json", true)
+	_ = viper.ReadConfig(strings.NewReader(`
+stations:
+  bravo: {token: fake, station_id: 1003}
+  charlie: {token: fake, station_id: 1004}
+`))
+	_ = currentCmd.Flags().Set("all", "true")
+
+	err := runCurrent(currentCmd, nil)
+	var e *Error
+	if !errors.As(err, &e) || !e.Quiet || e.Kind != KindAuth {
+		t.Fatalf("err = %#v, want a quiet auth error so that only the JSON is printed", err)
+	}
+	var got []currentErrorJSON
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(got) != 2 || got[0].Reason != "auth failed" || got[1].Reason == "" {
+		t.Errorf("got %+v, want an error entry per station", got)
+	}
+}
+
+func TestRunCurrentAllWithStation(t *testing.T) {
+	setupCurrentGrid(t)
+	viper.Set("station", "alpha")
+	_ = currentCmd.Flags().Set("all", "true")
+
+	if err := runCurrent(currentCmd, nil); errorKind(err) != KindUsage {
+		t.Errorf("err = %v, want a usage error", err)
+	}
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/fakeserver"
+	"github.com/spf13/cobra"
+)
+
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Tools for developing and testing tempest-cli",
+}
+
+var fakeServerCmd = &cobra.Command{
+	Use:   "fake-server",
+	Short: "Serve synthetic WeatherFlow and tempestd data for offline use",
+	Long: `Serve deterministic synthetic weather on the WeatherFlow REST endpoints
+and tempestd API endpoints that tempest uses, so that every command can be
+tried without a station, a token or network access.
+
+Scenarios:
+  normal        mild weather with a daily temperature cycle
+  heat-wave     hot, dry and sunny
+  storm         thunderstorms peaking at 09:00 and 21:00 UTC, with wind,
+                rain, lightning and falling pressure
+  dead-battery  nearly flat batteries; sensors stopped reporting 2h ago
+
+--error makes requests fail. Give an HTTP status or "timeout", optionally
+followed by xCOUNT to fail only that many requests and @PATH to fail only
+requests whose path contains PATH. Repeat it for several faults.`,
+	Example: `  tempest dev fake-server --scenario storm
+  tempest dev fake-server --error 429x2@/api/v1 --error timeout@/better_forecast
+  tempest dev fake-server --now 2024-07-15T21:00:00Z`,
+	Args: cobra.NoArgs,
+	RunE: runFakeServer,
+}
+
+func init() {
+	rootCmd.AddCommand(devCmd)
+	devCmd.AddCommand(fakeServerCmd)
+	fakeServerCmd.Flags().String("addr", "127.0.0.1:8765", "address to listen on")
+	fakeServerCmd.Flags().String("scenario", string(fakeserver.Normal), "weather scenario: normal, heat-wave, storm or dead-battery")
+	fakeServerCmd.Flags().StringArray("error", nil, "inject failures: STATUS or timeout, with optional xCOUNT and @PATH (repeatable)")
+	fakeServerCmd.Flags().String("now", "", "serve data as of this RFC 3339 time instead of the clock")
+	fakeServerCmd.Flags().String("token", "", "only accept this REST API token and tempestd bearer token")
+	_ = fakeServerCmd.RegisterFlagCompletionFunc("scenario", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		names := make([]string, len(fakeserver.Scenarios))
+		for i, sc := range fakeserver.Scenarios {
+			names[i] = string(sc)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// fakeServerOptions reads the fake-server flags.
+func fakeServerOptions(cmd *cobra.Command) (fakeserver.Options, error) {
+	var opts fakeserver.Options
+	name, _ := cmd.Flags().GetString("scenario")
+	sc, err := fakeserver.ParseScenario(name)
+	if err != nil {
+		return opts, usageError(err)
+	}
+	opts.Scenario = sc
+
+	specs, _ := cmd.Flags().GetStringArray("error")
+	for _, spec := range specs {
+		f, err := fakeserver.ParseFault(spec)
+		if err != nil {
+			return opts, usageError(err)
+		}
+		opts.Faults = append(opts.Faults, f)
+	}
+
+	if s, _ := cmd.Flags().GetString("now"); s != "" {
+		now, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return opts, usageError(fmt.Errorf("invalid --now (use RFC 3339, e.g. 2024-07-15T21:00:00Z): %w", err))
+		}
+		opts.Now = func() time.Time { return now }
+	}
+	opts.Token, _ = cmd.Flags().GetString("token")
+	return opts, nil
+}
+
+func runFakeServer(cmd *cobra.Command, args []string) error {
+	opts, err := fakeServerOptions(cmd)
+	if err != nil {
+		return err
+	}
+	addr, _ := cmd.Flags().GetString("addr")
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return newError(KindConfig, err, "cannot listen on %s: %v", addr, err)
+	}
+	srv := &http.Server{Handler: fakeserver.New(opts), ReadHeaderTimeout: 10 * time.Second}
+
+	printFakeServerUsage(cmd.OutOrStdout(), "http://"+ln.Addr().String(), opts)
+	return serveUntilDone(cmd.Context(), srv, ln)
+}
+
+// serveUntilDone serves on ln until ctx is done, then shuts srv down.
+func serveUntilDone(ctx context.Context, srv *http.Server, ln net.Listener) error {
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	// Requests held open by timeout faults end when their connections close.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	_ = srv.Close()
+	return nil
+}
+
+func printFakeServerUsage(w io.Writer, url string, opts fakeserver.Options) {
+	token := opts.Token
+	if token == "" {
+		token = "fake"
+	}
+	_, _ = fmt.Fprintf(w, "Fake WeatherFlow and tempestd server listening on %s (scenario: %s)\n", url, opts.Scenario)
+	if len(opts.Faults) > 0 {
+		faults := make([]string, len(opts.Faults))
+		for i, f := range opts.Faults {
+			faults[i] = f.String()
+		}
+		_, _ = fmt.Fprintf(w, "Injecting errors: %s\n", strings.Join(faults, ", "))
+	}
+
+	_, _ = fmt.Fprintf(w, "\nStations:\n")
+	for _, st := range fakeserver.Stations() {
+		devices := make([]string, len(st.Devices))
+		for i, d := range st.Devices {
+			devices[i] = fmt.Sprintf("%d (%s)", d.DeviceID, d.DeviceType)
+		}
+		_, _ = fmt.Fprintf(w, "  %d  %-10s  devices %s\n", st.StationID, st.Name, strings.Join(devices, ", "))
+	}
+
+	_, _ = fmt.Fprintf(w, "\nPoint tempest at it from another terminal:\n")
+	_, _ = fmt.Fprintf(w, "  export TEMPEST_SERVER=%s\n", url)
+	_, _ = fmt.Fprintf(w, "  export TEMPEST_CLOUD_URL=%s%s\n", url, fakeserver.CloudPath)
+	if opts.Token != "" {
+		_, _ = fmt.Fprintf(w, "  export TEMPEST_TEMPESTD_TOKEN=%s\n", opts.Token)
+	}
+	_, _ = fmt.Fprintf(w, "\nwith stations like these in the config file:\n")
+	_, _ = fmt.Fprintf(w, "  stations:\n")
+	for _, st := range fakeserver.Stations() {
+		name := strings.ToLower(strings.TrimPrefix(st.Name, "Fake "))
+		_, _ = fmt.Fprintf(w, "    %s: {token: %s, station_id: %d}\n", name, token, st.StationID)
+	}
+	_, _ = fmt.Fprintf(w, "\nPress Ctrl-C to stop.\n")
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/fakeserver"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// setupFakeServer configures the fake stations against a fake server with
+// opts, reading from sources, and sends cmd's output to the returned buffer.
+func setupFakeServer(t *testing.T, cmd *cobra.Command, opts fakeserver.Options, sources ...string) *bytes.Buffer {
+	t.Helper()
+	if opts.Now == nil {
+		now := time.Now().Truncate(time.Minute)
+		opts.Now = func() time.Time { return now }
+	}
+	srv := httptest.NewServer(fakeserver.New(opts))
+	t.Cleanup(srv.Close)
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigType("yaml")
+	_ = viper.ReadConfig(strings.NewReader(`
+default_station: home
+stations:
+  home:  {token: fake, station_id: 1001}
+  coast: {token: fake, station_id: 1002}
+`))
+	viper.Set("server", srv.URL)
+	viper.Set("cloud_url", srv.URL+fakeserver.CloudPath)
+	viper.Set("sources", sources)
+	viper.Set("json", true)
+	viper.Set("no-cache", true)
+	viper.Set("tempestd.retries", 0)
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetContext(context.Background())
+	return &out
+}
+
+func decodeJSON[T any](t *testing.T, out *bytes.Buffer) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(out.Bytes(), &v); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	out.Reset()
+	return v
+}
+
+func TestFakeServerFlows(t *testing.T) {
+	for _, source := range []string{"tempestd", "cloud"} {
+		t.Run(source, func(t *testing.T) {
+			out := setupFakeServer(t, currentCmd, fakeserver.Options{Scenario: fakeserver.Storm}, source)
+			if err := runCurrent(currentCmd, nil); err != nil {
+				t.Fatalf("current: %v", err)
+			}
+			current := decodeJSON[currentJSONOutput](t, out)
+			if current.Source != dataSource(source) || current.Station.StationID != 1001 || time.Since(current.Timestamp) > 2*time.Minute {
+				t.Errorf("current = %+v", current)
+			}
+
+			forecastCmd.SetOut(out)
+			forecastCmd.SetContext(context.Background())
+			if err := runForecast(forecastCmd, nil); err != nil {
+				t.Fatalf("forecast: %v", err)
+			}
+			if forecast := decodeJSON[forecastJSONOutput](t, out); len(forecast.Days) == 0 || forecast.Source != dataSource(source) {
+				t.Errorf("forecast = %+v", forecast)
+			}
+
+			historyCmd.SetOut(out)
+			historyCmd.SetContext(context.Background())
+			if err := runHistory(historyCmd, nil); err != nil {
+				t.Fatalf("history: %v", err)
+			}
+			history := decodeJSON[historyJSONOutput](t, out)
+			// The last 24 hours, a minute apart.
+			if n := len(history.Observations); n < 24*60 || n > 24*60+1 {
+				t.Errorf("history has %d observations", n)
+			}
+		})
+	}
+}
+
+func TestFakeServerDeadBatteryHealth(t *testing.T) {
+	out := setupFakeServer(t, stationsCmd, fakeserver.Options{Scenario: fakeserver.DeadBattery}, "tempestd")
+	_ = stationsCmd.Flags().Set("health", "true")
+	t.Cleanup(func() { _ = stationsCmd.Flags().Set("health", "false") })
+
+	if err := runStations(stationsCmd, nil); ExitCode(err) != ExitDegraded {
+		t.Fatalf("runStations() error = %v, want exit code %d", err, ExitDegraded)
+	}
+	got := decodeJSON[healthJSONOutput](t, out)
+	if got.Healthy || got.Degraded != 2 {
+		t.Fatalf("healthy = %v, degraded = %d; want false, 2", got.Healthy, got.Degraded)
+	}
+	for _, s := range got.Stations {
+		if s.BatteryVolts == nil || *s.BatteryVolts > 2.35 || len(s.Problems) < 2 {
+			t.Errorf("%s = %+v, want a flat battery and stale data", s.ConfigName, s)
+		}
+	}
+}
+
+func TestFakeServerInjectedErrors(t *testing.T) {
+	t.Run("auth", func(t *testing.T) {
+		out := setupFakeServer(t, currentCmd, fakeserver.Options{Faults: []fakeserver.Fault{{Status: 401, Path: "/stations/1002"}}}, "tempestd")
+		_ = currentCmd.Flags().Set("all", "true")
+		t.Cleanup(func() {
+			_ = currentCmd.Flags().Set("all", "false")
+			currentCmd.Flags().Lookup("all").Changed = false
+		})
+
+		if err := runCurrent(currentCmd, nil); err != nil {
+			t.Fatalf("runCurrent() error: %v", err)
+		}
+		got := decodeJSON[[]currentErrorJSON](t, out)
+		if len(got) != 2 || got[0].Reason != "auth failed" || got[1].Reason != "" {
+			t.Errorf("got %+v, want coast to fail auth and home to succeed", got)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		setupFakeServer(t, currentCmd, fakeserver.Options{Faults: []fakeserver.Fault{{Timeout: true}}}, "tempestd")
+		viper.Set("tempestd.timeout", "100ms")
+		if err := runCurrent(currentCmd, nil); errorKind(err) != KindTimeout {
+			t.Errorf("err = %v, want a timeout", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		setupFakeServer(t, currentCmd, fakeserver.Options{}, "cloud")
+		viper.Set("station", "ghost")
+		_ = viper.ReadConfig(strings.NewReader(`
+stations:
+  ghost: {token: fake, station_id: 9}
+`))
+		if err := runCurrent(currentCmd, nil); errorKind(err) != KindNotFound {
+			t.Errorf("err = %v, want not found", err)
+		}
+	})
+}
+
+func TestFakeServerOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags map[string]string
+		want  string
+	}{
+		{"scenario", map[string]string{"scenario": "blizzard"}, "unknown scenario"},
+		{"error", map[string]string{"error": "418x"}, "invalid fault"},
+		{"now", map[string]string{"now": "yesterday"}, "invalid --now"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := fakeServerCmd.Flags()
+			for name, value := range tt.flags {
+				_ = fs.Set(name, value)
+			}
+			t.Cleanup(func() {
+				_ = fs.Set("scenario", string(fakeserver.Normal))
+				_ = fs.Set("now", "")
+				_ = fs.Lookup("error").Value.(pflag.SliceValue).Replace(nil)
+			})
+			_, err := fakeServerOptions(fakeServerCmd)
+			if err == nil || !strings.Contains(err.Error(), tt.want) || errorKind(err) != KindUsage {
+				t.Errorf("err = %v, want a usage error about %q", err, tt.want)
+			}
+		})
+	}
+
+	_ = fakeServerCmd.Flags().Set("now", "2024-07-15T21:00:00Z")
+	t.Cleanup(func() { _ = fakeServerCmd.Flags().Set("now", "") })
+	opts, err := fakeServerOptions(fakeServerCmd)
+	if err != nil || opts.Scenario != fakeserver.Normal || !opts.Now().Equal(time.Date(2024, 7, 15, 21, 0, 0, 0, time.UTC)) {
+		t.Errorf("fakeServerOptions() = %+v, %v", opts, err)
+	}
+}
+
+func TestFakeServerHistoryDevice(t *testing.T) {
+	out := setupFakeServer(t, historyCmd, fakeserver.Options{Token: "fake"}, "tempestd", "cloud")
+	viper.Set("device", "ST")
+
+	if err := runHistory(historyCmd, nil); err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if got := decodeJSON[historyJSONOutput](t, out); got.Source != sourceCloud {
+		t.Errorf("source = %s, want cloud, since tempestd cannot select a device", got.Source)
+	}
+
+	// A cloud failure is reported as such, not as tempestd lacking --device.
+	viper.Set("stations.home.token", "wrong")
+	if err := runHistory(historyCmd, nil); errorKind(err) != KindAuth {
+		t.Errorf("err = %v, want the cloud auth error", err)
+	}
+
+	viper.Set("sources", []string{"tempestd"})
+	if err := runHistory(historyCmd, nil); errorKind(err) != KindUsage {
+		t.Errorf("err = %v, want a usage error when only tempestd is configured", err)
+	}
+}
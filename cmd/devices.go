@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	tempest "github.com/chadmayfield/tempest-go"
+)
+
+// WeatherFlow device type codes. Observations come from sensors; the hub
+// only relays them.
+const (
+	deviceTempest = "ST"
+	deviceHub     = "HB"
+)
+
+// sensorDevices returns the station's sensors, leaving out the hub. Tempest
+// devices come first since they report every observation field.
+func sensorDevices(station *tempest.Station) []tempest.Device {
+	var sensors []tempest.Device
+	for _, d := range station.Devices {
+		if d.DeviceType != deviceHub {
+			sensors = append(sensors, d)
+		}
+	}
+	slices.SortStableFunc(sensors, func(a, b tempest.Device) int {
+		return sensorRank(a.DeviceType) - sensorRank(b.DeviceType)
+	})
+	return sensors
+}
+
+func sensorRank(t string) int {
+	if t == deviceTempest {
+		return 0
+	}
+	return 1
+}
+
+// resolveDeviceID returns the device whose observations to fetch for sc. A
+// selector from --device wins, then the configured device_id; otherwise the
+// sensor is picked from the station's devices, which are cached with the
+// rest of the station metadata.
+func resolveDeviceID(ctx context.Context, sc *config.StationConfig, selector string) (int, error) {
+	if selector == "" && sc.DeviceID > 0 {
+		return sc.DeviceID, nil
+	}
+	station, err := fetchStationFromAPI(ctx, sc, lazyStationClient(ctx, sc))
+	if err != nil {
+		return 0, fmt.Errorf("looking up devices for station %d: %w", sc.StationID, err)
+	}
+	d, err := pickSensor(station, sc.DeviceID, selector)
+	if err != nil {
+		return 0, err
+	}
+	slog.Debug("resolved device", "station_id", sc.StationID, "device_id", d.DeviceID, "type", d.DeviceType)
+	return d.DeviceID, nil
+}
+
+// pickSensor chooses a station's sensor. selector may be a device ID, a
+// serial number or a device type such as ST; without one the configured
+// deviceID is used, or else the only sensor, or else the only Tempest.
+func pickSensor(station *tempest.Station, deviceID int, selector string) (tempest.Device, error) {
+	if selector != "" {
+		return selectDevice(station, selector)
+	}
+	if deviceID > 0 {
+		return tempest.Device{DeviceID: deviceID}, nil
+	}
+
+	sensors := sensorDevices(station)
+	if len(sensors) == 1 {
+		return sensors[0], nil
+	}
+	var tempests []tempest.Device
+	for _, d := range sensors {
+		if d.DeviceType == deviceTempest {
+			tempests = append(tempests, d)
+		}
+	}
+	if len(tempests) == 1 {
+		return tempests[0], nil
+	}
+	if len(sensors) == 0 {
+		return tempest.Device{}, newError(KindConfig, nil, "station %d has no sensors", station.StationID)
+	}
+	return tempest.Device{}, newError(KindConfig, nil, "station %d has several sensors (%s); choose one with --device or set device_id",
+		station.StationID, describeDevices(sensors))
+}
+
+// selectDevice finds the device matching a --device selector.
+func selectDevice(station *tempest.Station, selector string) (tempest.Device, error) {
+	var matches []tempest.Device
+	id, idErr := strconv.Atoi(selector)
+	for _, d := range station.Devices {
+		if (idErr == nil && d.DeviceID == id) || strings.EqualFold(d.SerialNum, selector) || strings.EqualFold(d.DeviceType, selector) {
+			matches = append(matches, d)
+		}
+	}
+
+	switch {
+	case len(matches) == 0:
+		return tempest.Device{}, newError(KindUsage, nil, "no device %q on station %d; devices: %s",
+			selector, station.StationID, describeDevices(station.Devices))
+	case len(matches) > 1:
+		return tempest.Device{}, newError(KindUsage, nil, "--device %q matches several devices (%s); use a device ID or serial number",
+			selector, describeDevices(matches))
+	case matches[0].DeviceType == deviceHub:
+		return tempest.Device{}, newError(KindUsage, nil, "device %d is the hub, which does not report observations; devices: %s",
+			matches[0].DeviceID, describeDevices(sensorDevices(station)))
+	}
+	return matches[0], nil
+}
+
+func describeDevices(devices []tempest.Device) string {
+	parts := make([]string, len(devices))
+	for i, d := range devices {
+		parts[i] = fmt.Sprintf("%d %s %s", d.DeviceID, d.DeviceType, d.SerialNum)
+	}
+	return strings.Join(parts, ", ")
+}
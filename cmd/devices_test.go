@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/spf13/viper"
+)
+
+func TestSensorDevices(t *testing.T) {
+	station := &tempest.Station{Devices: []tempest.Device{
+		{DeviceID: 1, DeviceType: "HB"},
+		{DeviceID: 2, DeviceType: "AR"},
+		{DeviceID: 3, DeviceType: "ST"},
+		{DeviceID: 4, DeviceType: "SK"},
+	}}
+	var ids []int
+	for _, d := range sensorDevices(station) {
+		ids = append(ids, d.DeviceID)
+	}
+	if len(ids) != 3 || ids[0] != 3 || ids[1] != 2 || ids[2] != 4 {
+		t.Errorf("sensors = %v, want [3 2 4] (Tempest first, hub excluded)", ids)
+	}
+}
+
+func TestPickSensor(t *testing.T) {
+	tempestStation := &tempest.Station{StationID: 1, Devices: []tempest.Device{
+		{DeviceID: 10, SerialNum: "HB-00000010", DeviceType: "HB"},
+		{DeviceID: 11, SerialNum: "ST-00000011", DeviceType: "ST"},
+	}}
+	legacy := &tempest.Station{StationID: 2, Devices: []tempest.Device{
+		{DeviceID: 20, SerialNum: "HB-00000020", DeviceType: "HB"},
+		{DeviceID: 21, SerialNum: "AR-00000021", DeviceType: "AR"},
+		{DeviceID: 22, SerialNum: "SK-00000022", DeviceType: "SK"},
+	}}
+	twoTempests := &tempest.Station{StationID: 3, Devices: []tempest.Device{
+		{DeviceID: 31, SerialNum: "ST-00000031", DeviceType: "ST"},
+		{DeviceID: 32, SerialNum: "ST-00000032", DeviceType: "ST"},
+	}}
+
+	tests := []struct {
+		name     string
+		station  *tempest.Station
+		deviceID int
+		selector string
+		want     int
+		wantKind ErrorKind
+	}{
+		{"only sensor", tempestStation, 0, "", 11, ""},
+		{"configured", tempestStation, 99, "", 99, ""},
+		{"selector beats config", tempestStation, 99, "st", 11, ""},
+		{"legacy needs selector", legacy, 0, "", 0, KindConfig},
+		{"legacy by type", legacy, 0, "SK", 22, ""},
+		{"legacy by serial", legacy, 0, "ar-00000021", 21, ""},
+		{"by ID", twoTempests, 0, "32", 32, ""},
+		{"ambiguous type", twoTempests, 0, "ST", 0, KindUsage},
+		{"several Tempests", twoTempests, 0, "", 0, KindConfig},
+		{"hub rejected", legacy, 0, "HB", 0, KindUsage},
+		{"no match", legacy, 0, "99", 0, KindUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := pickSensor(tt.station, tt.deviceID, tt.selector)
+			if tt.wantKind != "" {
+				if errorKind(err) != tt.wantKind {
+					t.Errorf("err = %v (kind %q), want kind %q", err, errorKind(err), tt.wantKind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.DeviceID != tt.want {
+				t.Errorf("device = %d, want %d", d.DeviceID, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveDeviceIDCachesStation(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{"stations": [{"station_id": 7, "name": "Home", "devices": [
+			{"device_id": 70, "serial_number": "HB-00000070", "device_type": "HB"},
+			{"device_id": 71, "serial_number": "ST-00000071", "device_type": "ST"}]}]}`))
+	}))
+	defer srv.Close()
+	orig := cloudBaseURL
+	cloudBaseURL = srv.URL
+	defer func() { cloudBaseURL = orig }()
+
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("cache.dir", t.TempDir())
+
+	sc := &config.StationConfig{Token: "t", StationID: 7}
+	for range 2 {
+		id, err := resolveDeviceID(context.Background(), sc, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != 71 {
+			t.Errorf("device = %d, want the Tempest sensor 71", id)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("station fetched %d times, want 1 (cached)", n)
+	}
+
+	sc.DeviceID = 5
+	if id, err := resolveDeviceID(context.Background(), sc, ""); err != nil || id != 5 {
+		t.Errorf("configured device: got %d, %v; want 5", id, err)
+	}
+}
+
+func TestCachedStationSkipsTokenCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("token_command runs through sh")
+	}
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("cache.dir", t.TempDir())
+
+	runs := filepath.Join(t.TempDir(), "runs")
+	sc := &config.StationConfig{TokenCommand: "echo run >> " + runs + "; echo t", StationID: 7}
+	_, err := cachedFetch(context.Background(), cloudCacheKey("station", sc), stationTTL(), func() (*tempest.Station, error) {
+		return &tempest.Station{StationID: 7, Devices: []tempest.Device{{DeviceID: 71, DeviceType: "ST"}}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id, err := resolveDeviceID(context.Background(), sc, ""); err != nil || id != 71 {
+		t.Fatalf("got %d, %v; want the cached sensor 71", id, err)
+	}
+	if _, err := os.Stat(runs); !os.IsNotExist(err) {
+		t.Errorf("token_command ran for a cached station (stat: %v)", err)
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/display"
+	jsonout "github.com/chadmayfield/tempest-cli/internal/json"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var configDiscoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "List the stations and devices a token can see",
+	Long: `List every station visible to a WeatherFlow token, with each device's ID,
+type and serial number. Uses --token, or the token of the selected station
+in the config.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigDiscover,
+}
+
+func init() {
+	configCmd.AddCommand(configDiscoverCmd)
+	configDiscoverCmd.Flags().String("token", "", "WeatherFlow API token (default: the configured station's token)")
+}
+
+type discoveredDeviceJSON struct {
+	DeviceID     int    `json:"device_id"`
+	SerialNumber string `json:"serial_number"`
+	DeviceType   string `json:"device_type"`
+	TypeName     string `json:"type_name"`
+}
+
+type discoveredStationJSON struct {
+	StationID  int     `json:"station_id"`
+	Name       string  `json:"name"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	ConfigName string  `json:"config_name,omitempty"`
+	// SensorDeviceID is the device to use as device_id.
+	SensorDeviceID int                    `json:"sensor_device_id,omitempty"`
+	Devices        []discoveredDeviceJSON `json:"devices"`
+}
+
+func runConfigDiscover(cmd *cobra.Command, args []string) error {
+	token, _ := cmd.Flags().GetString("token")
+	cfg, cfgErr := config.Load()
+	if token == "" {
+		if cfgErr == nil {
+			if sc, err := cfg.ResolveStation(viper.GetString("station")); err == nil && sc.HasToken() {
+				if token, err = sc.APIToken(cmd.Context()); err != nil {
+					return newError(KindConfig, err, "reading token (%s): %v", sc.TokenSource(), err)
+				}
+			}
+		}
+		if token == "" {
+			return newError(KindConfig, nil, "no token to discover stations with: pass --token or configure a station")
+		}
+	}
+
+	client, err := newCloudClient(token)
+	if err != nil {
+		return err
+	}
+	stations, err := client.ListStations(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	configured := make(map[int]string)
+	if cfgErr == nil {
+		for _, name := range cfg.StationNames() {
+			configured[cfg.Stations[name].StationID] = name
+		}
+	}
+
+	if viper.GetBool("json") {
+		return jsonout.Write(cmd.OutOrStdout(), map[string]any{"stations": discoveredJSON(stations, configured)})
+	}
+
+	theme, err := newTheme()
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), display.RenderDiscovery(theme, stations, configured))
+	return nil
+}
+
+func discoveredJSON(stations []tempest.Station, configured map[int]string) []discoveredStationJSON {
+	out := make([]discoveredStationJSON, len(stations))
+	for i, s := range stations {
+		ds := discoveredStationJSON{
+			StationID:  s.StationID,
+			Name:       s.Name,
+			Latitude:   s.Latitude,
+			Longitude:  s.Longitude,
+			ConfigName: configured[s.StationID],
+			Devices:    []discoveredDeviceJSON{},
+		}
+		if sensors := sensorDevices(&s); len(sensors) > 0 {
+			ds.SensorDeviceID = sensors[0].DeviceID
+		}
+		for _, d := range s.Devices {
+			ds.Devices = append(ds.Devices, discoveredDeviceJSON{
+				DeviceID:     d.DeviceID,
+				SerialNumber: d.SerialNum,
+				DeviceType:   d.DeviceType,
+				TypeName:     display.DeviceTypeName(d.DeviceType),
+			})
+		}
+		out[i] = ds
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// fakeStationsAPI serves GET /stations for the token "good".
+func fakeStationsAPI(t *testing.T) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stations" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("token") != "good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"stations": [{
+			"station_id": 100, "name": "Home", "latitude": 40.1, "longitude": -111.2,
+			"station_meta": {"elevation": 1400},
+			"devices": [
+				{"device_id": 1, "serial_number": "HB-00000001", "device_type": "HB"},
+				{"device_id": 2, "serial_number": "ST-00000002", "device_type": "ST"}
+			]}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	orig := cloudBaseURL
+	cloudBaseURL = srv.URL
+	t.Cleanup(func() { cloudBaseURL = orig })
+}
+
+func TestListStations(t *testing.T) {
+	fakeStationsAPI(t)
+
+	client, err := newCloudClient("good")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stations, err := client.ListStations(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stations) != 1 || stations[0].Elevation != 1400 || len(stations[0].Devices) != 2 {
+		t.Fatalf("stations = %+v", stations)
+	}
+	if d := stations[0].Devices[1]; d.DeviceID != 2 || d.SerialNum != "ST-00000002" || d.DeviceType != "ST" {
+		t.Errorf("device = %+v", d)
+	}
+
+	client, _ = newCloudClient("bad-secret")
+	_, err = client.ListStations(context.Background())
+	if errorKind(err) != KindAuth {
+		t.Errorf("bad token: kind = %q, want auth (err: %v)", errorKind(err), err)
+	}
+	if strings.Contains(err.Error(), "bad-secret") {
+		t.Errorf("error leaks the token: %v", err)
+	}
+}
+
+func TestRunConfigDiscover(t *testing.T) {
+	fakeStationsAPI(t)
+
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigType("yaml")
+	_ = viper.ReadConfig(strings.NewReader("stations:\n  home: {token: good, station_id: 100}\n"))
+	viper.Set("json", true)
+
+	var out bytes.Buffer
+	configDiscoverCmd.SetOut(&out)
+	configDiscoverCmd.SetContext(context.Background())
+	if err := runConfigDiscover(configDiscoverCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Stations []discoveredStationJSON `json:"stations"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(got.Stations) != 1 {
+		t.Fatalf("stations = %+v", got.Stations)
+	}
+	s := got.Stations[0]
+	if s.ConfigName != "home" || s.SensorDeviceID != 2 || s.Devices[0].TypeName != "Hub" {
+		t.Errorf("station = %+v", s)
+	}
+}
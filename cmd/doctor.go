@@ -0,0 +1,408 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/display"
+	jsonout "github.com/chadmayfield/tempest-cli/internal/json"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	doctorCheckTimeout = 10 * time.Second
+
+	// Clock skew up to clockSkewWarn is normal given the Date header's
+	// one-second resolution and network latency.
+	clockSkewWarn = 30 * time.Second
+	clockSkewFail = 2 * time.Minute
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose configuration and connectivity problems",
+	Long: `Run a series of checks and print pass/warn/fail lines with suggested fixes:
+
+  - the config file is found, parses, and is not readable by others
+  - each station's token is accepted and its station ID resolves
+  - device_id is set and refers to a sensor rather than the hub
+  - the tempestd server, if configured, is reachable and its API version
+  - the local clock agrees with the WeatherFlow servers
+
+Exits non-zero if any check fails.`,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// doctorOptions lets tests point the network checks at local servers.
+type doctorOptions struct {
+	cloudOpts []tempest.ClientOption
+	clockURL  string
+}
+
+type doctorJSONOutput struct {
+	Checks   []display.DoctorCheck `json:"checks"`
+	Passed   int                   `json:"passed"`
+	Warnings int                   `json:"warnings"`
+	Failed   int                   `json:"failed"`
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	checks := runDoctorChecks(cmd.Context(), doctorOptions{clockURL: cloudURL()})
+	if err := cmd.Context().Err(); err != nil {
+		return err
+	}
+
+	out := doctorJSONOutput{Checks: checks}
+	for _, c := range checks {
+		switch c.Status {
+		case display.CheckPass:
+			out.Passed++
+		case display.CheckWarn:
+			out.Warnings++
+		case display.CheckFail:
+			out.Failed++
+		}
+	}
+
+	if viper.GetBool("json") {
+		if err := jsonout.Write(cmd.OutOrStdout(), out); err != nil {
+			return err
+		}
+	} else {
+		theme, err := newTheme()
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), display.RenderDoctor(theme, checks))
+	}
+
+	if out.Failed > 0 {
+		return &Error{Kind: KindDegraded, Msg: fmt.Sprintf("%d doctor checks failed", out.Failed), Quiet: true}
+	}
+	return nil
+}
+
+// runDoctorChecks runs every check in order. Checks that depend on a usable
+// config are skipped when it cannot be loaded.
+func runDoctorChecks(ctx context.Context, opts doctorOptions) []display.DoctorCheck {
+	checks := checkConfigFile()
+
+	cfg, err := config.Load()
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		checks = append(checks, display.DoctorCheck{
+			Name:    "config",
+			Status:  display.CheckFail,
+			Message: err.Error(),
+			Fix:     "run 'tempest config init' or edit the config file",
+		})
+	} else {
+		checks = append(checks, display.DoctorCheck{
+			Name:    "config",
+			Status:  display.CheckPass,
+			Message: fmt.Sprintf("%d station(s) configured", len(cfg.Stations)),
+		})
+	}
+
+	if cfg != nil && len(cfg.Stations) > 0 {
+		names := cfg.StationNames()
+		results := make([][]display.DoctorCheck, len(names))
+		forEachStation(ctx, names, doctorCheckTimeout, func(ctx context.Context, i int, name string) {
+			sc := cfg.Stations[name]
+			results[i] = checkStationSetup(ctx, name, &sc, opts)
+		})
+		for _, r := range results {
+			checks = append(checks, r...)
+		}
+	}
+
+	if c, ok := checkTempestd(ctx, cfg); ok {
+		checks = append(checks, c)
+	}
+	return append(checks, checkClock(ctx, opts.clockURL))
+}
+
+// checkConfigFile reports whether a config file was found and parsed, and
+// whether its permissions keep the tokens private.
+func checkConfigFile() []display.DoctorCheck {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		return []display.DoctorCheck{{
+			Name:    "config file",
+			Status:  display.CheckWarn,
+			Message: "no config file found; using environment variables only",
+			Fix:     "run 'tempest config init' to create ~/.config/tempest/config.yaml",
+		}}
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return []display.DoctorCheck{{
+			Name:    "config file",
+			Status:  display.CheckFail,
+			Message: fmt.Sprintf("cannot read %s: %v", path, err),
+			Fix:     "check the --config flag and TEMPEST_CONFIG, or run 'tempest config init'",
+		}}
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return []display.DoctorCheck{{
+			Name:    "config file",
+			Status:  display.CheckFail,
+			Message: fmt.Sprintf("cannot parse %s: %v", path, err),
+			Fix:     "fix the YAML syntax error, or move the file aside and run 'tempest config init'",
+		}}
+	}
+
+	checks := []display.DoctorCheck{{
+		Name:    "config file",
+		Status:  display.CheckPass,
+		Message: path,
+	}}
+	if mode, open := configReadableByOthers(path); open {
+		checks = append(checks, display.DoctorCheck{
+			Name:    "config permissions",
+			Status:  display.CheckWarn,
+			Message: fmt.Sprintf("readable by other users (mode %04o); it contains API tokens", mode),
+			Fix:     "chmod 600 " + path,
+		})
+	} else {
+		checks = append(checks, display.DoctorCheck{
+			Name:    "config permissions",
+			Status:  display.CheckPass,
+			Message: fmt.Sprintf("mode %04o", mode),
+		})
+	}
+	return checks
+}
+
+// checkStationSetup verifies a station's token, station ID and device ID
+// against the cloud API.
+func checkStationSetup(ctx context.Context, name string, sc *config.StationConfig, opts doctorOptions) []display.DoctorCheck {
+	prefix := "station " + name + ": "
+	fail := func(what, msg, fix string) []display.DoctorCheck {
+		return []display.DoctorCheck{{Name: prefix + what, Status: display.CheckFail, Message: msg, Fix: fix}}
+	}
+
+	if !sc.HasToken() {
+		return fail("token", "no token configured", "add a token from https://tempestwx.com/settings/tokens")
+	}
+	source := sc.TokenSource()
+	token, err := sc.APIToken(ctx)
+	if err != nil {
+		return fail("token", fmt.Sprintf("%v (from %s)", err, source), tokenSourceFix(sc))
+	}
+	if sc.StationID <= 0 {
+		return fail("station_id", "no station_id configured", "set station_id; it is shown in the URL of your station at tempestwx.com")
+	}
+
+	accepted := "accepted (from " + source + ")"
+	client, err := newCloudClient(token, opts.cloudOpts...)
+	if err != nil {
+		return fail("token", fmt.Sprintf("%v (from %s)", err, source), "check the token in your config")
+	}
+	station, err := client.GetStation(ctx, sc.StationID)
+	switch errorKind(err) {
+	case KindAuth:
+		return fail("token", "token from "+source+" rejected by the WeatherFlow API", "create a new token at https://tempestwx.com/settings/tokens")
+	case KindNotFound:
+		return []display.DoctorCheck{
+			{Name: prefix + "token", Status: display.CheckPass, Message: accepted},
+			{Name: prefix + "station_id", Status: display.CheckFail,
+				Message: fmt.Sprintf("station %d not found for this token", sc.StationID),
+				Fix:     "check the station ID at tempestwx.com; the token must belong to the station's owner"},
+		}
+	}
+	if err != nil {
+		return []display.DoctorCheck{{
+			Name:    prefix + "token",
+			Status:  display.CheckWarn,
+			Message: fmt.Sprintf("cannot verify: %v", err),
+			Fix:     "check your internet connection and try again",
+		}}
+	}
+
+	checks := []display.DoctorCheck{
+		{Name: prefix + "token", Status: display.CheckPass, Message: accepted},
+		{Name: prefix + "station_id", Status: display.CheckPass, Message: fmt.Sprintf("%d is %q", sc.StationID, station.Name)},
+	}
+	return append(checks, checkDeviceID(prefix, sc.DeviceID, station))
+}
+
+// tokenSourceFix suggests how to repair a token source that could not be read.
+func tokenSourceFix(sc *config.StationConfig) string {
+	switch {
+	case sc.TokenFile != "":
+		return "check that token_file exists, is readable and contains the token"
+	case sc.TokenCommand != "":
+		return "run token_command by hand; it must exit 0 and print the token on stdout"
+	default:
+		return "check the token in your config"
+	}
+}
+
+// checkDeviceID checks that deviceID is one of the station's sensors.
+func checkDeviceID(prefix string, deviceID int, station *tempest.Station) display.DoctorCheck {
+	name := prefix + "device_id"
+
+	var sensors []string
+	for _, d := range sensorDevices(station) {
+		sensors = append(sensors, fmt.Sprintf("%d (%s %s)", d.DeviceID, d.DeviceType, d.SerialNum))
+	}
+	fix := "see the station's devices with 'tempest config discover'"
+	if s := sensorDevices(station); len(s) > 0 {
+		fix = fmt.Sprintf("set device_id: %d", s[0].DeviceID)
+	}
+
+	if deviceID <= 0 {
+		if d, err := pickSensor(station, 0, ""); err == nil {
+			return display.DoctorCheck{Name: name, Status: display.CheckPass,
+				Message: fmt.Sprintf("not set; using sensor %d (%s %s) automatically", d.DeviceID, d.DeviceType, d.SerialNum)}
+		}
+		return display.DoctorCheck{Name: name, Status: display.CheckWarn,
+			Message: "not set and the station has several sensors (" + strings.Join(sensors, ", ") + "); history needs --device", Fix: fix}
+	}
+	for _, d := range station.Devices {
+		if d.DeviceID != deviceID {
+			continue
+		}
+		if d.DeviceType == deviceHub {
+			return display.DoctorCheck{Name: name, Status: display.CheckFail,
+				Message: fmt.Sprintf("%d is the hub (%s), not a sensor", deviceID, d.SerialNum), Fix: fix}
+		}
+		return display.DoctorCheck{Name: name, Status: display.CheckPass,
+			Message: fmt.Sprintf("%d is sensor %s (%s)", deviceID, d.SerialNum, d.DeviceType)}
+	}
+	msg := fmt.Sprintf("%d is not attached to station %d", deviceID, station.StationID)
+	if len(sensors) > 0 {
+		msg += "; sensors: " + strings.Join(sensors, ", ")
+	}
+	return display.DoctorCheck{Name: name, Status: display.CheckFail, Message: msg, Fix: fix}
+}
+
+// tempestdHealth is the optional version information tempestd reports from
+// its health endpoint.
+type tempestdHealth struct {
+	Status     string `json:"status"`
+	Version    string `json:"version"`
+	APIVersion string `json:"api_version"`
+}
+
+// checkTempestd checks that the configured tempestd server answers and
+// reports which API version it speaks. ok is false if no server is set.
+func checkTempestd(ctx context.Context, cfg *config.Config) (c display.DoctorCheck, ok bool) {
+	serverURL := viper.GetString("server")
+	if serverURL == "" && cfg != nil {
+		serverURL = cfg.EffectiveServerURL()
+	}
+	if serverURL == "" {
+		return c, false
+	}
+
+	c.Name = "tempestd"
+	if err := validateServerURL(serverURL); err != nil {
+		c.Status, c.Message = display.CheckFail, err.Error()
+		c.Fix = "use an http://, https:// or unix:// URL for tempestd.server or --server"
+		return c, true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	defer cancel()
+
+	health, err := fetchFromTempestd[tempestdHealth](ctx, serverURL, "/api/v1/health")
+	var se *tempestdStatusError
+	if errors.As(err, &se) && se.StatusCode == http.StatusNotFound {
+		// Older servers have no health endpoint; any v1 endpoint will do.
+		_, err = fetchFromTempestd[[]tempest.Station](ctx, serverURL, "/api/v1/stations")
+		health = &tempestdHealth{}
+	}
+	if err != nil {
+		c.Status = display.CheckFail
+		c.Message = fmt.Sprintf("%s is unreachable: %v", serverURL, wrapAPIError(err))
+		switch errorKind(wrapAPIError(err)) {
+		case KindAuth:
+			c.Fix = "check tempestd.token or tempestd.username/password"
+		case KindConfig:
+			c.Fix = "check the tempestd TLS settings (ca_file, cert_file, key_file)"
+		default:
+			c.Fix = "check that tempestd is running and listening at " + serverURL
+		}
+		return c, true
+	}
+
+	api := health.APIVersion
+	if api == "" {
+		api = "v1"
+	}
+	c.Status = display.CheckPass
+	c.Message = fmt.Sprintf("%s is reachable, API %s", serverURL, api)
+	if health.Version != "" {
+		c.Message += ", tempestd " + health.Version
+	}
+	return c, true
+}
+
+// checkClock compares the local clock with the Date header from url.
+func checkClock(ctx context.Context, url string) display.DoctorCheck {
+	c := display.DoctorCheck{Name: "clock"}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorCheckTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		c.Status, c.Message = display.CheckWarn, fmt.Sprintf("cannot check: %v", err)
+		return c
+	}
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.Status, c.Message = display.CheckWarn, fmt.Sprintf("cannot check: %v", transportError(err))
+		return c
+	}
+	_ = resp.Body.Close()
+	rtt := time.Since(start)
+
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		c.Status, c.Message = display.CheckWarn, "cannot check: server sent no Date header"
+		return c
+	}
+
+	skew := start.Add(rtt / 2).Sub(serverTime).Round(time.Second)
+	abs := skew
+	if abs < 0 {
+		abs = -abs
+	}
+	direction := "ahead"
+	if skew < 0 {
+		direction = "behind"
+	}
+
+	switch {
+	case abs <= clockSkewWarn:
+		c.Status, c.Message = display.CheckPass, fmt.Sprintf("in sync (within %s)", clockSkewWarn)
+	case abs <= clockSkewFail:
+		c.Status, c.Message = display.CheckWarn, fmt.Sprintf("local clock is %s %s", abs, direction)
+		c.Fix = "enable NTP time sync (e.g. 'timedatectl set-ntp true')"
+	default:
+		c.Status, c.Message = display.CheckFail, fmt.Sprintf("local clock is %s %s; ages and history ranges will be wrong", abs, direction)
+		c.Fix = "enable NTP time sync (e.g. 'timedatectl set-ntp true')"
+	}
+	return c
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/display"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/spf13/viper"
+)
+
+func TestCheckDeviceID(t *testing.T) {
+	station := &tempest.Station{
+		StationID: 100,
+		Devices: []tempest.Device{
+			{DeviceID: 1, SerialNum: "HB-00000001", DeviceType: "HB"},
+			{DeviceID: 2, SerialNum: "ST-00000002", DeviceType: "ST"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		deviceID int
+		want     display.CheckStatus
+		contains string
+	}{
+		{"unset", 0, display.CheckPass, "using sensor 2"},
+		{"sensor", 2, display.CheckPass, "ST-00000002"},
+		{"hub", 1, display.CheckFail, "is the hub"},
+		{"unknown", 9, display.CheckFail, "sensors: 2 (ST ST-00000002)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := checkDeviceID("station home: ", tt.deviceID, station)
+			if c.Status != tt.want {
+				t.Errorf("status = %q, want %q (%s)", c.Status, tt.want, c.Message)
+			}
+			if !strings.Contains(c.Message, tt.contains) {
+				t.Errorf("message %q does not contain %q", c.Message, tt.contains)
+			}
+			if tt.want != display.CheckPass && c.Fix != "set device_id: 2" {
+				t.Errorf("fix = %q, want the sensor's device ID", c.Fix)
+			}
+		})
+	}
+}
+
+func TestCheckDeviceIDAmbiguous(t *testing.T) {
+	station := &tempest.Station{
+		StationID: 100,
+		Devices: []tempest.Device{
+			{DeviceID: 2, SerialNum: "AR-00000002", DeviceType: "AR"},
+			{DeviceID: 3, SerialNum: "SK-00000003", DeviceType: "SK"},
+		},
+	}
+	c := checkDeviceID("station home: ", 0, station)
+	if c.Status != display.CheckWarn || !strings.Contains(c.Message, "several sensors") {
+		t.Errorf("got %q %q, want a warning about several sensors", c.Status, c.Message)
+	}
+}
+
+func TestCheckStationSetup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token") == "bad" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if !strings.HasSuffix(r.URL.Path, "/stations/100") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"stations": []map[string]any{{
+			"station_id": 100,
+			"name":       "Home",
+			"devices": []map[string]any{
+				{"device_id": 1, "serial_number": "HB-00000001", "device_type": "HB"},
+				{"device_id": 2, "serial_number": "ST-00000002", "device_type": "ST"},
+			},
+		}}})
+	}))
+	defer srv.Close()
+	opts := doctorOptions{cloudOpts: []tempest.ClientOption{tempest.WithBaseURL(srv.URL)}}
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("good\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		sc   config.StationConfig
+		want map[string]display.CheckStatus
+	}{
+		{"ok", config.StationConfig{Token: "good", StationID: 100, DeviceID: 2}, map[string]display.CheckStatus{
+			"token": display.CheckPass, "station_id": display.CheckPass, "device_id": display.CheckPass,
+		}},
+		{"bad token", config.StationConfig{Token: "bad", StationID: 100}, map[string]display.CheckStatus{
+			"token": display.CheckFail,
+		}},
+		{"unknown station", config.StationConfig{Token: "good", StationID: 7}, map[string]display.CheckStatus{
+			"token": display.CheckPass, "station_id": display.CheckFail,
+		}},
+		{"hub device", config.StationConfig{Token: "good", StationID: 100, DeviceID: 1}, map[string]display.CheckStatus{
+			"token": display.CheckPass, "station_id": display.CheckPass, "device_id": display.CheckFail,
+		}},
+		{"no token", config.StationConfig{StationID: 100}, map[string]display.CheckStatus{
+			"token": display.CheckFail,
+		}},
+		{"token file", config.StationConfig{TokenFile: tokenFile, StationID: 100, DeviceID: 2}, map[string]display.CheckStatus{
+			"token": display.CheckPass, "station_id": display.CheckPass, "device_id": display.CheckPass,
+		}},
+		{"missing token file", config.StationConfig{TokenFile: tokenFile + ".missing", StationID: 100}, map[string]display.CheckStatus{
+			"token": display.CheckFail,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := checkStationSetup(context.Background(), "home", &tt.sc, opts)
+			got := make(map[string]display.CheckStatus)
+			for _, c := range checks {
+				got[strings.TrimPrefix(c.Name, "station home: ")] = c.Status
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("checks = %+v, want %v", checks, tt.want)
+			}
+			for name, status := range tt.want {
+				if got[name] != status {
+					t.Errorf("%s = %q, want %q", name, got[name], status)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckTempestd(t *testing.T) {
+	withHealth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok", "version": "1.4.0", "api_version": "v1"})
+	}))
+	defer withHealth.Close()
+
+	legacy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/stations" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer legacy.Close()
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	downURL := down.URL
+	down.Close()
+
+	tests := []struct {
+		name     string
+		server   string
+		want     display.CheckStatus
+		contains string
+	}{
+		{"health endpoint", withHealth.URL, display.CheckPass, "API v1, tempestd 1.4.0"},
+		{"no health endpoint", legacy.URL, display.CheckPass, "reachable, API v1"},
+		{"unreachable", downURL, display.CheckFail, "unreachable"},
+		{"invalid URL", "ftp://example.com", display.CheckFail, "ftp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			viper.Set("server", tt.server)
+
+			c, ok := checkTempestd(context.Background(), nil)
+			if !ok {
+				t.Fatal("check skipped with a server configured")
+			}
+			if c.Status != tt.want || !strings.Contains(c.Message, tt.contains) {
+				t.Errorf("got %q %q, want %q containing %q", c.Status, c.Message, tt.want, tt.contains)
+			}
+		})
+	}
+
+	viper.Reset()
+	if _, ok := checkTempestd(context.Background(), &config.Config{}); ok {
+		t.Error("check ran with no server configured")
+	}
+}
+
+func TestCheckClock(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset time.Duration
+		want   display.CheckStatus
+	}{
+		{"in sync", 0, display.CheckPass},
+		{"slightly off", time.Minute, display.CheckWarn},
+		{"far off", -10 * time.Minute, display.CheckFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Date", time.Now().Add(tt.offset).UTC().Format(http.TimeFormat))
+			}))
+			defer srv.Close()
+
+			c := checkClock(context.Background(), srv.URL)
+			if c.Status != tt.want {
+				t.Errorf("status = %q, want %q (%s)", c.Status, tt.want, c.Message)
+			}
+		})
+	}
+}
+
+func TestRunDoctorChecksNoConfig(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	checks := runDoctorChecks(context.Background(), doctorOptions{clockURL: srv.URL})
+	got := make(map[string]display.CheckStatus)
+	for _, c := range checks {
+		got[c.Name] = c.Status
+	}
+	if got["config file"] != display.CheckWarn {
+		t.Errorf("config file = %q, want warn", got["config file"])
+	}
+	if got["config"] != display.CheckFail {
+		t.Errorf("config = %q, want fail with no stations", got["config"])
+	}
+	if got["clock"] != display.CheckPass {
+		t.Errorf("clock = %q, want pass", got["clock"])
+	}
+}
@@ -1,71 +1,230 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
+	"os"
 	"strings"
+
+	jsonout "github.com/chadmayfield/tempest-cli/internal/json"
+	"github.com/spf13/viper"
 )
 
-// wrapAPIError enhances API errors with user-friendly messages.
-func wrapAPIError(err error) error {
+// ErrorKind categorises a failure so that scripts can tell failure modes apart.
+type ErrorKind string
+
+const (
+	KindGeneral   ErrorKind = "error"
+	KindUsage     ErrorKind = "usage"
+	KindConfig    ErrorKind = "config"
+	KindAuth      ErrorKind = "auth"
+	KindNotFound  ErrorKind = "not_found"
+	KindRateLimit ErrorKind = "rate_limit"
+	KindNetwork   ErrorKind = "network"
+	KindTimeout   ErrorKind = "timeout"
+	KindStale     ErrorKind = "stale_data"
+	KindDegraded  ErrorKind = "degraded"
+)
+
+// Process exit codes. These are part of the CLI's interface; see README.md.
+const (
+	ExitOK          = 0
+	ExitGeneral     = 1
+	ExitUsage       = 2
+	ExitConfig      = 3
+	ExitAuth        = 4
+	ExitNotFound    = 5
+	ExitRateLimit   = 6
+	ExitNetwork     = 7
+	ExitTimeout     = 8
+	ExitStale       = 9
+	ExitDegraded    = 10
+	ExitInterrupted = 130
+)
+
+var exitCodes = map[ErrorKind]int{
+	KindGeneral:   ExitGeneral,
+	KindUsage:     ExitUsage,
+	KindConfig:    ExitConfig,
+	KindAuth:      ExitAuth,
+	KindNotFound:  ExitNotFound,
+	KindRateLimit: ExitRateLimit,
+	KindNetwork:   ExitNetwork,
+	KindTimeout:   ExitTimeout,
+	KindStale:     ExitStale,
+	KindDegraded:  ExitDegraded,
+}
+
+// Error is a categorised error. Msg is the user-facing message; Err is the
+// underlying cause, if any, and is available through errors.Unwrap. Quiet
+// errors have already been reported in the command's output, so PrintError
+// skips them and only the exit code signals the failure.
+type Error struct {
+	Kind  ErrorKind
+	Msg   string
+	Err   error
+	Quiet bool
+}
+
+func (e *Error) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return string(e.Kind)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(kind ErrorKind, cause error, format string, args ...any) *Error {
+	return &Error{Kind: kind, Msg: fmt.Sprintf(format, args...), Err: cause}
+}
+
+// errorKind returns the category of err, or KindGeneral if it is uncategorised.
+func errorKind(err error) ErrorKind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindGeneral
+}
+
+// ExitCode returns the documented process exit code for err.
+func ExitCode(err error) int {
 	if err == nil {
-		return nil
+		return ExitOK
+	}
+	if errors.Is(err, context.Canceled) {
+		return ExitInterrupted
 	}
+	return exitCodes[errorKind(err)]
+}
 
-	msg := err.Error()
+type errorJSONOutput struct {
+	Error errorJSONBody `json:"error"`
+}
 
-	// HTTP 401 — invalid token
-	if strings.Contains(msg, "401") || strings.Contains(msg, "Unauthorized") {
-		return fmt.Errorf("authentication failed: your API token may be invalid or expired.\nCheck your token at https://tempestwx.com/settings/tokens")
+type errorJSONBody struct {
+	Kind     ErrorKind `json:"kind"`
+	Message  string    `json:"message"`
+	ExitCode int       `json:"exit_code"`
+}
+
+// PrintError reports err to the user. With --json the error is written to
+// stdout as {"error": {...}} so scripts always receive a JSON document;
+// otherwise it is printed to stderr.
+func PrintError(err error) {
+	var e *Error
+	if errors.As(err, &e) && e.Quiet {
+		return
+	}
+	if viper.GetBool("json") {
+		_ = jsonout.Write(rootCmd.OutOrStdout(), errorJSONOutput{Error: errorJSONBody{
+			Kind:     errorKind(err),
+			Message:  err.Error(),
+			ExitCode: ExitCode(err),
+		}})
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+}
+
+// wrapAPIError converts errors from the cloud API or tempestd into categorised
+// errors with user-friendly messages. Errors that are already categorised, and
+// errors that cannot be classified, are returned unchanged.
+func wrapAPIError(err error) error {
+	if err == nil {
+		return nil
 	}
 
-	// HTTP 404 — unknown station
-	if strings.Contains(msg, "404") || strings.Contains(msg, "Not Found") {
-		return fmt.Errorf("station not found: check that your station ID is correct. %w", err)
+	var e *Error
+	if errors.As(err, &e) || errors.Is(err, context.Canceled) {
+		return err
 	}
 
-	// HTTP 429 — rate limited
-	if strings.Contains(msg, "429") || strings.Contains(msg, "Too Many Requests") || strings.Contains(msg, "rate limit") {
-		return fmt.Errorf("API rate limit exceeded. Try again in a moment")
+	var se *tempestdStatusError
+	if errors.As(err, &se) {
+		return statusError(se.StatusCode, err)
 	}
 
-	// Circuit breaker open
-	if strings.Contains(msg, "circuit breaker") || strings.Contains(msg, "breaker is open") {
-		return fmt.Errorf("API temporarily unavailable — the circuit breaker is open. Wait a moment and try again")
+	// tempest-go reports an open circuit breaker with this fixed message and
+	// does not wrap it in a typed error.
+	if strings.Contains(err.Error(), "circuit breaker open") {
+		return newError(KindNetwork, err, "API temporarily unavailable — the circuit breaker is open. Wait a moment and try again")
 	}
 
-	// Network errors
-	var netErr *net.OpError
-	if errors.As(err, &netErr) {
-		return fmt.Errorf("network error: cannot reach the WeatherFlow API. Check your internet connection: %w", err)
+	return transportError(err)
+}
+
+// statusError categorises an HTTP error status.
+func statusError(status int, cause error) error {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return newError(KindAuth, cause, "authentication failed: your API token may be invalid or expired.\nCheck your token at https://tempestwx.com/settings/tokens")
+	case status == http.StatusNotFound:
+		return newError(KindNotFound, cause, "station not found: check that your station ID is correct. %v", cause)
+	case status == http.StatusTooManyRequests:
+		return newError(KindRateLimit, cause, "API rate limit exceeded. Try again in a moment")
+	case status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout:
+		return newError(KindNetwork, cause, "service unavailable (HTTP %d). Try again later", status)
+	default:
+		return cause
 	}
+}
 
-	var urlErr *url.Error
-	if errors.As(err, &urlErr) {
-		if urlErr.Timeout() {
-			return fmt.Errorf("request timed out: the WeatherFlow API did not respond in time. Try again later")
-		}
-		return fmt.Errorf("network error: %w", err)
+// transportError categorises network-level failures.
+func transportError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return newError(KindTimeout, err, "request timed out: the server did not respond in time. Try again later")
 	}
 
 	var dnsErr *net.DNSError
 	if errors.As(err, &dnsErr) {
-		return fmt.Errorf("DNS lookup failed: cannot resolve the WeatherFlow API hostname. Check your internet connection")
+		return newError(KindNetwork, err, "DNS lookup failed: cannot resolve %s. Check your internet connection", dnsErr.Name)
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return newError(KindNetwork, err, "network error: cannot reach the server. Check your internet connection: %v", err)
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return newError(KindNetwork, err, "network error: %v", err)
 	}
 
 	return err
 }
 
-// wrapConfigError wraps a config loading error with a helpful suggestion.
+// wrapConfigError categorises a config loading error and adds a helpful suggestion.
 func wrapConfigError(err error) error {
 	if err == nil {
 		return nil
 	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
 	msg := err.Error()
 	if strings.Contains(msg, "no stations configured") || strings.Contains(msg, "not set in config") {
-		return fmt.Errorf("%w\nRun 'tempest config init' to set up your configuration", err)
+		return newError(KindConfig, err, "%v\nRun 'tempest config init' to set up your configuration", err)
 	}
-	return err
+	return &Error{Kind: KindConfig, Err: err}
+}
+
+// usageError categorises an invalid flag or argument.
+func usageError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: KindUsage, Err: err}
 }
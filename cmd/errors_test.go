@@ -1,10 +1,19 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/spf13/viper"
 )
 
 func TestWrapAPIError_Nil(t *testing.T) {
@@ -14,10 +23,13 @@ func TestWrapAPIError_Nil(t *testing.T) {
 }
 
 func TestWrapAPIError_401(t *testing.T) {
-	err := wrapAPIError(fmt.Errorf("HTTP 401 Unauthorized"))
+	err := wrapAPIError(&tempestdStatusError{StatusCode: 401, Path: "/api/v1/stations/1"})
 	if err == nil {
 		t.Fatal("expected error")
 	}
+	if errorKind(err) != KindAuth {
+		t.Errorf("kind = %q, want %q", errorKind(err), KindAuth)
+	}
 	if !strings.Contains(err.Error(), "authentication failed") {
 		t.Errorf("expected auth error message, got: %s", err.Error())
 	}
@@ -27,33 +39,49 @@ func TestWrapAPIError_401(t *testing.T) {
 }
 
 func TestWrapAPIError_404(t *testing.T) {
-	err := wrapAPIError(fmt.Errorf("HTTP 404 Not Found"))
+	err := wrapAPIError(fmt.Errorf("fetching station: %w", &tempestdStatusError{StatusCode: 404, Path: "/x"}))
 	if err == nil {
 		t.Fatal("expected error")
 	}
+	if errorKind(err) != KindNotFound {
+		t.Errorf("kind = %q, want %q", errorKind(err), KindNotFound)
+	}
 	if !strings.Contains(err.Error(), "station not found") {
 		t.Errorf("expected station not found message, got: %s", err.Error())
 	}
 }
 
 func TestWrapAPIError_429(t *testing.T) {
-	err := wrapAPIError(fmt.Errorf("HTTP 429 Too Many Requests"))
+	err := wrapAPIError(&tempestdStatusError{StatusCode: 429})
 	if err == nil {
 		t.Fatal("expected error")
 	}
+	if errorKind(err) != KindRateLimit {
+		t.Errorf("kind = %q, want %q", errorKind(err), KindRateLimit)
+	}
 	if !strings.Contains(err.Error(), "rate limit") {
 		t.Errorf("expected rate limit message, got: %s", err.Error())
 	}
 }
 
+func TestWrapAPIError_NumbersInMessageNotMisclassified(t *testing.T) {
+	orig := fmt.Errorf("station 404123 has no device 401")
+	if err := wrapAPIError(orig); err != orig {
+		t.Errorf("expected uncategorised passthrough, got %v (kind %q)", err, errorKind(err))
+	}
+}
+
 func TestWrapAPIError_CircuitBreaker(t *testing.T) {
-	err := wrapAPIError(fmt.Errorf("circuit breaker is open"))
+	err := wrapAPIError(fmt.Errorf("fetching observation: circuit breaker open"))
 	if err == nil {
 		t.Fatal("expected error")
 	}
 	if !strings.Contains(err.Error(), "circuit breaker") {
 		t.Errorf("expected circuit breaker message, got: %s", err.Error())
 	}
+	if errorKind(err) != KindNetwork {
+		t.Errorf("kind = %q, want %q", errorKind(err), KindNetwork)
+	}
 }
 
 func TestWrapAPIError_NetworkError(t *testing.T) {
@@ -69,6 +97,23 @@ func TestWrapAPIError_NetworkError(t *testing.T) {
 	if !strings.Contains(err.Error(), "network error") {
 		t.Errorf("expected network error message, got: %s", err.Error())
 	}
+	if errorKind(err) != KindNetwork {
+		t.Errorf("kind = %q, want %q", errorKind(err), KindNetwork)
+	}
+}
+
+func TestWrapAPIError_Timeout(t *testing.T) {
+	err := wrapAPIError(fmt.Errorf("contacting tempestd: %w", context.DeadlineExceeded))
+	if errorKind(err) != KindTimeout {
+		t.Errorf("kind = %q, want %q", errorKind(err), KindTimeout)
+	}
+}
+
+func TestWrapAPIError_AlreadyCategorised(t *testing.T) {
+	orig := newError(KindStale, nil, "data is stale")
+	if err := wrapAPIError(fmt.Errorf("wrapped: %w", orig)); errorKind(err) != KindStale {
+		t.Errorf("kind = %q, want %q", errorKind(err), KindStale)
+	}
 }
 
 func TestWrapAPIError_Generic(t *testing.T) {
@@ -87,6 +132,9 @@ func TestWrapConfigError_NoStations(t *testing.T) {
 	if !strings.Contains(err.Error(), "config init") {
 		t.Errorf("expected config init suggestion, got: %s", err.Error())
 	}
+	if errorKind(err) != KindConfig {
+		t.Errorf("kind = %q, want %q", errorKind(err), KindConfig)
+	}
 }
 
 func TestWrapConfigError_Nil(t *testing.T) {
@@ -95,10 +143,123 @@ func TestWrapConfigError_Nil(t *testing.T) {
 	}
 }
 
-func TestWrapConfigError_Passthrough(t *testing.T) {
+func TestWrapConfigError_Categorises(t *testing.T) {
 	orig := fmt.Errorf("some other error")
 	err := wrapConfigError(orig)
-	if err != orig {
-		t.Errorf("expected original error to pass through, got: %v", err)
+	if !errors.Is(err, orig) {
+		t.Errorf("expected original error in chain, got: %v", err)
+	}
+	if err.Error() != orig.Error() {
+		t.Errorf("message = %q, want unchanged %q", err.Error(), orig.Error())
+	}
+	if errorKind(err) != KindConfig {
+		t.Errorf("kind = %q, want %q", errorKind(err), KindConfig)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitOK},
+		{fmt.Errorf("plain"), ExitGeneral},
+		{usageError(fmt.Errorf("bad flag")), ExitUsage},
+		{wrapConfigError(fmt.Errorf("bad yaml")), ExitConfig},
+		{statusError(401, nil), ExitAuth},
+		{statusError(404, fmt.Errorf("x")), ExitNotFound},
+		{statusError(429, nil), ExitRateLimit},
+		{statusError(503, nil), ExitNetwork},
+		{wrapAPIError(context.DeadlineExceeded), ExitTimeout},
+		{newError(KindStale, nil, "stale"), ExitStale},
+		{&Error{Kind: KindDegraded, Quiet: true}, ExitDegraded},
+		{fmt.Errorf("fetching: %w", context.Canceled), ExitInterrupted},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestPrintErrorJSON(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("json", true)
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	defer rootCmd.SetOut(nil)
+
+	PrintError(statusError(401, nil))
+
+	var got errorJSONOutput
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	if got.Error.Kind != KindAuth || got.Error.ExitCode != ExitAuth {
+		t.Errorf("got %+v, want auth/%d", got.Error, ExitAuth)
+	}
+	if !strings.Contains(got.Error.Message, "authentication failed") {
+		t.Errorf("message = %q", got.Error.Message)
+	}
+}
+
+func TestPrintErrorQuiet(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("json", true)
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	defer rootCmd.SetOut(nil)
+
+	PrintError(&Error{Kind: KindDegraded, Msg: "1 of 2 stations degraded", Quiet: true})
+
+	if out.Len() != 0 {
+		t.Errorf("quiet error printed %q", out.String())
+	}
+}
+
+func TestCloudClientClassifiesStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   ErrorKind
+	}{
+		{http.StatusUnauthorized, KindAuth},
+		{http.StatusNotFound, KindNotFound},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			_, _ = w.Write([]byte(`{"status":{"status_message":"nope"}}`))
+		}))
+
+		client, err := newCloudClient("token", tempest.WithBaseURL(srv.URL))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = client.GetStation(context.Background(), 12345)
+		if errorKind(err) != tt.want {
+			t.Errorf("status %d: kind = %q, want %q (err: %v)", tt.status, errorKind(err), tt.want, err)
+		}
+		srv.Close()
+	}
+}
+
+func TestCloudClientClassifiesNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	client, err := newCloudClient("token", tempest.WithBaseURL(url))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.GetStation(context.Background(), 12345)
+	if errorKind(err) != KindNetwork {
+		t.Errorf("kind = %q, want %q (err: %v)", errorKind(err), KindNetwork, err)
 	}
 }
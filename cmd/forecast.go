@@ -3,12 +3,12 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"log/slog"
 	"time"
 
 	"github.com/chadmayfield/tempest-cli/internal/config"
 	"github.com/chadmayfield/tempest-cli/internal/display"
 	jsonout "github.com/chadmayfield/tempest-cli/internal/json"
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	tempest "github.com/chadmayfield/tempest-go"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -24,11 +24,16 @@ var forecastCmd = &cobra.Command{
 
 func init() {
 	forecastCmd.Flags().IntP("days", "d", 5, "number of forecast days (max 10)")
+	addFormatFlag(forecastCmd)
 	rootCmd.AddCommand(forecastCmd)
 }
 
 func runForecast(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
@@ -51,48 +56,56 @@ func runForecast(cmd *cobra.Command, args []string) error {
 
 	serverURL := resolveServerURL(cfg)
 
-	var forecast *tempest.Forecast
-	if serverURL != "" {
-		// tempestd may not cache forecasts — fall back to cloud API on failure
-		forecast, err = fetchForecastFromServer(ctx, serverURL, sc.StationID)
-		if err != nil {
-			slog.Debug("tempestd forecast failed, falling back to cloud API", "error", err)
-			forecast, err = fetchForecastFromAPI(ctx, sc)
-		}
-	} else {
-		forecast, err = fetchForecastFromAPI(ctx, sc)
-	}
+	// tempestd may not cache forecasts, so the cloud API usually follows it
+	// in the source order.
+	forecast, meta, err := fetchWithFallback(ctx, serverURL, sourceFetchers[tempest.Forecast]{
+		Tempestd: func(ctx context.Context, serverURL string) (*tempest.Forecast, error) {
+			return fetchForecastFromServer(ctx, serverURL, sc.StationID)
+		},
+		Cloud: func(ctx context.Context) (*tempest.Forecast, error) {
+			return fetchForecastFromAPI(ctx, sc)
+		},
+	})
 	if err != nil {
 		return wrapAPIError(err)
 	}
 
+	u, err := resolveUnits(cfg, sc)
+	if err != nil {
+		return err
+	}
+
 	if viper.GetBool("json") {
-		imperial := cfg.IsImperial()
-		units := "metric"
-		if imperial {
-			units = "imperial"
-		}
-		return jsonout.Write(cmd.OutOrStdout(), forecastJSON(forecast, sc, units, imperial, days))
+		out := forecastJSON(forecast, sc, u, days)
+		out.sourceJSON = meta.json()
+		return staleResult(meta, jsonout.Write(cmd.OutOrStdout(), out))
 	}
 
-	imperial := cfg.IsImperial()
-	noColor := viper.GetBool("no-color")
-	theme := display.NewTheme(noColor, display.WithNoEmoji(viper.GetBool("no-emoji")))
+	theme, err := newTheme()
+	if err != nil {
+		return err
+	}
+	if format != display.FormatText {
+		return staleResult(meta, writeDocument(cmd.OutOrStdout(), theme, format, display.ForecastDocument(theme, forecast, days, u), &meta))
+	}
 
 	termWidth := 80
 	if w, _, err := term.GetSize(0); err == nil && w > 0 {
 		termWidth = w
 	}
 
-	output := display.RenderForecast(theme, forecast, days, imperial, termWidth)
+	output := display.RenderForecast(theme, forecast, days, u, termWidth)
 	_, _ = fmt.Fprintln(cmd.OutOrStdout(), output)
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), display.RenderSource(theme, string(meta.Source), meta.FetchedAt, meta.Stale))
 
-	return nil
+	return staleResult(meta, nil)
 }
 
 type forecastJSONOutput struct {
+	sourceJSON
 	Station stationMeta       `json:"station"`
 	Units   string            `json:"units"`
+	Unit    units.Set         `json:"unit"`
 	Days    []forecastDayJSON `json:"daily"`
 }
 
@@ -108,7 +121,7 @@ type forecastDayJSON struct {
 	Sunset       string  `json:"sunset,omitempty"`
 }
 
-func forecastJSON(f *tempest.Forecast, sc *config.StationConfig, units string, imperial bool, days int) forecastJSONOutput {
+func forecastJSON(f *tempest.Forecast, sc *config.StationConfig, u units.Set, days int) forecastJSONOutput {
 	n := len(f.Daily)
 	if days < n {
 		n = days
@@ -116,16 +129,10 @@ func forecastJSON(f *tempest.Forecast, sc *config.StationConfig, units string, i
 	fdays := make([]forecastDayJSON, n)
 	for i := 0; i < n; i++ {
 		d := f.Daily[i]
-		high := d.HighTemp
-		low := d.LowTemp
-		if imperial {
-			high = tempest.CelsiusToFahrenheit(high)
-			low = tempest.CelsiusToFahrenheit(low)
-		}
 		fdays[i] = forecastDayJSON{
 			Date:         d.Date.Format(time.DateOnly),
-			HighTemp:     high,
-			LowTemp:      low,
+			HighTemp:     u.Temp(d.HighTemp),
+			LowTemp:      u.Temp(d.LowTemp),
 			Conditions:   d.Conditions,
 			Icon:         d.Icon,
 			PrecipChance: d.PrecipChance,
@@ -144,18 +151,20 @@ func forecastJSON(f *tempest.Forecast, sc *config.StationConfig, units string, i
 			StationID: sc.StationID,
 			DeviceID:  sc.DeviceID,
 		},
-		Units: units,
+		Units: u.System(),
+		Unit:  u,
 		Days:  fdays,
 	}
 }
 
 func fetchForecastFromAPI(ctx context.Context, sc *config.StationConfig) (*tempest.Forecast, error) {
-	client, err := tempest.NewClient(sc.Token)
-	if err != nil {
-		return nil, fmt.Errorf("creating API client: %w", err)
-	}
-
-	forecast, err := client.GetForecast(ctx, sc.StationID)
+	forecast, err := cachedFetch(ctx, cloudCacheKey("forecast", sc), forecastTTL(), func() (*tempest.Forecast, error) {
+		client, err := newStationClient(ctx, sc)
+		if err != nil {
+			return nil, fmt.Errorf("creating API client: %w", err)
+		}
+		return client.GetForecast(ctx, sc.StationID)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("fetching forecast: %w", err)
 	}
@@ -164,5 +173,7 @@ func fetchForecastFromAPI(ctx context.Context, sc *config.StationConfig) (*tempe
 }
 
 func fetchForecastFromServer(ctx context.Context, serverURL string, stationID int) (*tempest.Forecast, error) {
-	return fetchFromTempestd[tempest.Forecast](ctx, serverURL, fmt.Sprintf("/api/v1/stations/%d/forecast", stationID))
+	return cachedFetch(ctx, cacheKey("forecast", serverURL, stationID), forecastTTL(), func() (*tempest.Forecast, error) {
+		return fetchFromTempestd[tempest.Forecast](ctx, serverURL, fmt.Sprintf("/api/v1/stations/%d/forecast", stationID))
+	})
 }
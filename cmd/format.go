@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/chadmayfield/tempest-cli/internal/display"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// addFormatFlag adds --format to a command that can write markdown and HTML
+// as well as its terminal view.
+func addFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().String("format", "text", "output format: text, markdown or html")
+}
+
+// outputFormat returns the command's --format. Formats other than text
+// cannot be combined with --json.
+func outputFormat(cmd *cobra.Command) (display.Format, error) {
+	s, _ := cmd.Flags().GetString("format")
+	f, err := display.ParseFormat(s)
+	if err != nil {
+		return "", usageError(err)
+	}
+	if f != display.FormatText && viper.GetBool("json") {
+		return "", usageError(fmt.Errorf("--format %s cannot be used with --json", f))
+	}
+	return f, nil
+}
+
+// writeDocument writes doc as markdown or HTML, with meta, if any, as its
+// footer.
+func writeDocument(w io.Writer, theme *display.Theme, f display.Format, doc display.Document, meta *fetchMeta) error {
+	if meta != nil {
+		doc.Footer, doc.FooterWarning = display.SourceFooter(theme, string(meta.Source), meta.FetchedAt, meta.Stale)
+	}
+	out := doc.Markdown()
+	if f == display.FormatHTML {
+		out = doc.HTML(theme)
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chadmayfield/tempest-cli/internal/display"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func TestOutputFormat(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	tests := []struct {
+		format string
+		json   bool
+		want   display.Format
+		err    string
+	}{
+		{"", false, display.FormatText, ""},
+		{"md", false, display.FormatMarkdown, ""},
+		{"HTML", false, display.FormatHTML, ""},
+		{"text", true, display.FormatText, ""},
+		{"markdown", true, "", "cannot be used with --json"},
+		{"pdf", false, "", "unknown format"},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{}
+		addFormatFlag(cmd)
+		if tt.format != "" {
+			_ = cmd.Flags().Set("format", tt.format)
+		}
+		viper.Set("json", tt.json)
+		got, err := outputFormat(cmd)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) || errorKind(err) != KindUsage {
+				t.Errorf("--format %s: err = %v, want usage error %q", tt.format, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("--format %s = %q, %v; want %q", tt.format, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/display"
+	jsonout "github.com/chadmayfield/tempest-cli/internal/json"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// healthWindow is how far back device observations are scanned for the
+	// battery voltage and the last rain.
+	healthWindow = 24 * time.Hour
+	// healthMaxPowerMode is the highest power-save mode that is not reported
+	// as a problem. Modes 2 and 3 noticeably reduce the sampling rate.
+	healthMaxPowerMode = 1
+)
+
+type stationHealthJSONOutput struct {
+	stationJSONOutput
+	ConfigName    string   `json:"config_name"`
+	Health        string   `json:"health"`
+	StaleAfter    string   `json:"stale_after"`
+	BatteryVolts  *float64 `json:"battery_volts"`
+	Battery       string   `json:"battery,omitempty"`
+	PowerMode     *int     `json:"power_mode"`
+	SensorRSSI    *int     `json:"sensor_rssi"`
+	HubRSSI       *int     `json:"hub_rssi"`
+	LastRain      *string  `json:"last_rain"`
+	LastLightning *string  `json:"last_lightning"`
+	Problems      []string `json:"problems"`
+}
+
+type healthJSONOutput struct {
+	Healthy  bool                      `json:"healthy"`
+	Degraded int                       `json:"degraded"`
+	Stations []stationHealthJSONOutput `json:"stations"`
+}
+
+// runStationHealth implements 'tempest stations --health'. It returns a
+// KindDegraded error if any station has a problem, so cron jobs can alert on
+// the exit code.
+func runStationHealth(cmd *cobra.Command, cfg *config.Config, serverURL string, serverStations map[int]*tempest.Station, timeout time.Duration) error {
+	ctx := cmd.Context()
+
+	names := cfg.StationNames()
+	rows := make([]display.StationHealth, len(names))
+	forEachStation(ctx, names, timeout, func(ctx context.Context, i int, name string) {
+		sc := cfg.Stations[name]
+		rows[i] = checkStationHealth(ctx, serverURL, serverStations, &sc, cfg.StaleThreshold(&sc))
+		rows[i].ConfigName = name
+		rows[i].IsDefault = name == cfg.DefaultStation
+	})
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	collectSignals(ctx, serverURL, cfg, names, rows)
+
+	degraded := 0
+	for _, r := range rows {
+		if r.Degraded() {
+			degraded++
+		}
+	}
+
+	if viper.GetBool("json") {
+		if err := jsonout.Write(cmd.OutOrStdout(), stationHealthJSON(rows)); err != nil {
+			return err
+		}
+	} else {
+		theme, err := newTheme()
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), display.RenderStationHealth(theme, rows))
+	}
+
+	if degraded > 0 {
+		return &Error{
+			Kind:  KindDegraded,
+			Msg:   fmt.Sprintf("%d of %d stations degraded", degraded, len(rows)),
+			Quiet: true,
+		}
+	}
+	return nil
+}
+
+// checkStationHealth extends checkStation with battery, power mode and
+// activity details, and collects everything that makes the station degraded.
+func checkStationHealth(ctx context.Context, serverURL string, serverStations map[int]*tempest.Station, sc *config.StationConfig, staleAfter time.Duration) display.StationHealth {
+	row, obs := checkStation(ctx, serverURL, serverStations, sc, staleAfter)
+	h := display.StationHealth{StationRow: row, StaleAfter: staleAfter}
+
+	if obs != nil {
+		h.LastLightning = obs.LightningStrikeLastEpoch
+	}
+
+	switch {
+	case row.Outdated:
+		h.Problems = append(h.Problems, fmt.Sprintf("stale: last seen %s ago", time.Since(row.LastObserved).Round(time.Minute)))
+	case row.Reason != "":
+		h.Problems = append(h.Problems, row.Reason)
+	}
+
+	// Device observations are only needed for details the station
+	// observation lacks; without a device ID they are simply unknown.
+	if row.Reason == "" || row.Outdated {
+		recent, err := fetchRecentDeviceObs(ctx, serverURL, sc)
+		if err != nil {
+			slog.Debug("fetching device observations for health", "station_id", sc.StationID, "error", err)
+			if errorKind(err) != KindConfig {
+				h.Problems = append(h.Problems, "device data: "+stationReason(err))
+			}
+		}
+		h.Battery, h.LastRain = summarizeDeviceObs(recent)
+	}
+
+	if h.Battery > 0 {
+		if display.BatteryLow(h.Battery) {
+			h.Problems = append(h.Problems, fmt.Sprintf("battery low (%.2fV)", h.Battery))
+		}
+		if mode := display.PowerMode(h.Battery); mode > healthMaxPowerMode {
+			h.Problems = append(h.Problems, fmt.Sprintf("power save mode %d", mode))
+		}
+	}
+
+	return h
+}
+
+// collectSignals fills in the sensors' signal strength from their UDP
+// device_status broadcasts. Neither the REST API nor tempestd reports it, so
+// it is only known when udp is one of the sources.
+func collectSignals(ctx context.Context, serverURL string, cfg *config.Config, names []string, rows []display.StationHealth) {
+	order, err := sourceOrder()
+	if err != nil || !slices.Contains(order, sourceUDP) {
+		return
+	}
+	stationSerials := make([][]string, len(names))
+	var all []string
+	for i, name := range names {
+		sc := cfg.Stations[name]
+		serials, err := udpSerials(ctx, serverURL, &sc)
+		if err != nil {
+			slog.Debug("no signal strength for station", "station", name, "error", err)
+			continue
+		}
+		stationSerials[i] = serials
+		all = append(all, serials...)
+	}
+	if len(all) == 0 {
+		return
+	}
+	signals, err := listenForSignals(ctx, udpListenAddr(), all, udpTimeout())
+	if err != nil {
+		slog.Debug("listening for device status", "error", err)
+	}
+	for i, serials := range stationSerials {
+		for _, serial := range serials {
+			if sig, ok := signals[strings.ToUpper(serial)]; ok {
+				rows[i].SensorRSSI, rows[i].HubRSSI = sig.SensorRSSI, sig.HubRSSI
+				break
+			}
+		}
+	}
+}
+
+// fetchRecentDeviceObs returns the device observations from the last healthWindow.
+func fetchRecentDeviceObs(ctx context.Context, serverURL string, sc *config.StationConfig) ([]tempest.Observation, error) {
+	end := time.Now()
+	start := end.Add(-healthWindow)
+	if serverURL != "" {
+		return fetchHistoryFromServer(ctx, serverURL, sc.StationID, start, end, "metric", resolutionLabel(5*time.Minute))
+	}
+	deviceID, err := resolveDeviceID(ctx, sc, "")
+	if err != nil {
+		return nil, err
+	}
+	resolved := *sc
+	resolved.DeviceID = deviceID
+	return fetchHistoryFromAPI(ctx, &resolved, start, end)
+}
+
+// summarizeDeviceObs returns the most recent battery voltage and the time of
+// the most recent observation with rain.
+func summarizeDeviceObs(obs []tempest.Observation) (battery float64, lastRain time.Time) {
+	var batteryAt time.Time
+	for _, o := range obs {
+		if o.Battery > 0 && !o.Timestamp.Before(batteryAt) {
+			battery, batteryAt = o.Battery, o.Timestamp
+		}
+		if o.RainAccumulation > 0 && o.Timestamp.After(lastRain) {
+			lastRain = o.Timestamp
+		}
+	}
+	return battery, lastRain
+}
+
+func stationHealthJSON(rows []display.StationHealth) healthJSONOutput {
+	base := make([]display.StationRow, len(rows))
+	for i, r := range rows {
+		base[i] = r.StationRow
+	}
+	stations := stationsJSON(base)
+
+	out := healthJSONOutput{Stations: make([]stationHealthJSONOutput, len(rows))}
+	for i, r := range rows {
+		s := stationHealthJSONOutput{
+			stationJSONOutput: stations[i],
+			ConfigName:        r.ConfigName,
+			Health:            "ok",
+			StaleAfter:        r.StaleAfter.String(),
+			LastRain:          formatOptionalTime(r.LastRain),
+			LastLightning:     formatOptionalTime(r.LastLightning),
+			Problems:          r.Problems,
+		}
+		if r.Degraded() {
+			s.Health = "degraded"
+			out.Degraded++
+		}
+		if s.Problems == nil {
+			s.Problems = []string{}
+		}
+		if r.SensorRSSI != 0 || r.HubRSSI != 0 {
+			sensor, hub := r.SensorRSSI, r.HubRSSI
+			s.SensorRSSI, s.HubRSSI = &sensor, &hub
+		}
+		if r.Battery > 0 {
+			v := r.Battery
+			mode := display.PowerMode(r.Battery)
+			s.BatteryVolts = &v
+			s.Battery = display.BatteryLabel(r.Battery)
+			s.PowerMode = &mode
+		}
+		out.Stations[i] = s
+	}
+	out.Healthy = out.Degraded == 0
+	return out
+}
+
+func formatOptionalTime(t time.Time) *string {
+	if t.IsZero() {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
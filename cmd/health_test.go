@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/spf13/viper"
+)
+
+func TestSummarizeDeviceObs(t *testing.T) {
+	now := time.Now()
+	obs := []tempest.Observation{
+		{Timestamp: now.Add(-3 * time.Hour), Battery: 2.6, RainAccumulation: 0.2},
+		{Timestamp: now.Add(-2 * time.Hour), Battery: 2.5},
+		{Timestamp: now.Add(-time.Hour), Battery: 0},
+	}
+
+	battery, lastRain := summarizeDeviceObs(obs)
+	if battery != 2.5 {
+		t.Errorf("battery = %v, want 2.5 (latest non-zero reading)", battery)
+	}
+	if !lastRain.Equal(now.Add(-3 * time.Hour)) {
+		t.Errorf("lastRain = %v, want 3h ago", lastRain)
+	}
+
+	if battery, lastRain := summarizeDeviceObs(nil); battery != 0 || !lastRain.IsZero() {
+		t.Errorf("empty input = %v, %v; want zero values", battery, lastRain)
+	}
+}
+
+func TestRunStationHealth(t *testing.T) {
+	deviceObs := func(battery float64) map[string]any {
+		return map[string]any{"observations": []map[string]any{
+			{"timestamp": time.Now().Add(-5 * time.Minute), "battery": battery},
+			{"timestamp": time.Now().Add(-2 * time.Hour), "battery": battery, "rain_accumulation": 0.4},
+		}}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/stations/1", "/api/v1/stations/2", "/api/v1/stations/3":
+			_ = json.NewEncoder(w).Encode(map[string]any{"StationID": 1, "Name": "Station"})
+		case "/api/v1/stations/1/current", "/api/v1/stations/2/current":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Timestamp": time.Now().Add(-5 * time.Minute)})
+		case "/api/v1/stations/3/current":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Timestamp": time.Now().Add(-3 * time.Hour)})
+		case "/api/v1/stations/1/observations", "/api/v1/stations/3/observations":
+			_ = json.NewEncoder(w).Encode(deviceObs(2.6))
+		case "/api/v1/stations/2/observations":
+			_ = json.NewEncoder(w).Encode(deviceObs(2.05))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigType("yaml")
+	_ = viper.ReadConfig(strings.NewReader(`
+stations:
+  healthy: {token: t, station_id: 1}
+  lowbatt: {token: t, station_id: 2}
+  cabin:   {token: t, station_id: 3, stale_after: 6h}
+`))
+	viper.Set("server", srv.URL)
+	// Keep tests off the real cloud API.
+	viper.Set("sources", []string{"tempestd"})
+	viper.Set("json", true)
+	viper.Set("no-cache", true)
+
+	_ = stationsCmd.Flags().Set("health", "true")
+	defer func() { _ = stationsCmd.Flags().Set("health", "false") }()
+	var out bytes.Buffer
+	stationsCmd.SetOut(&out)
+	stationsCmd.SetContext(context.Background())
+
+	err := runStations(stationsCmd, nil)
+	if ExitCode(err) != ExitDegraded {
+		t.Fatalf("runStations() error = %v, want exit code %d", err, ExitDegraded)
+	}
+
+	var got healthJSONOutput
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	if got.Healthy || got.Degraded != 1 {
+		t.Errorf("healthy = %v, degraded = %d; want false, 1", got.Healthy, got.Degraded)
+	}
+
+	byName := map[string]stationHealthJSONOutput{}
+	for _, s := range got.Stations {
+		byName[s.ConfigName] = s
+	}
+	if s := byName["healthy"]; s.Health != "ok" || s.PowerMode == nil || *s.PowerMode != 0 || s.LastRain == nil {
+		t.Errorf("healthy = %+v, want ok, power mode 0 and a last rain time", s)
+	}
+	if s := byName["lowbatt"]; s.Health != "degraded" || len(s.Problems) != 2 {
+		t.Errorf("lowbatt problems = %v, want battery low and power save", s.Problems)
+	}
+	if s := byName["cabin"]; s.Health != "ok" || s.StaleAfter != "6h0m0s" {
+		t.Errorf("cabin = %+v, want ok with 6h stale_after", s)
+	}
+}
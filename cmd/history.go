@@ -1,15 +1,26 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/chadmayfield/tempest-cli/internal/config"
 	"github.com/chadmayfield/tempest-cli/internal/display"
 	jsonout "github.com/chadmayfield/tempest-cli/internal/json"
+	"github.com/chadmayfield/tempest-cli/internal/pressure"
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	tempest "github.com/chadmayfield/tempest-go"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/term"
@@ -27,6 +38,12 @@ func init() {
 	historyCmd.Flags().String("from", "", "range start (YYYY-MM-DD)")
 	historyCmd.Flags().String("to", "", "range end (YYYY-MM-DD)")
 	historyCmd.Flags().String("resolution", "", "data resolution: 1m, 5m, 30m, 3h (auto if omitted)")
+	historyCmd.Flags().String("pressure", "sea-level", "pressure to show: sea-level, altimeter or station")
+	historyCmd.Flags().StringSlice("columns", nil, "columns to show, in order (default "+strings.Join(display.DefaultHistoryColumns, ",")+"); one of "+strings.Join(display.HistoryColumnKeys(), ", "))
+	historyCmd.Flags().String("sort", "", "sort by a column, e.g. gust or gust:desc (default time)")
+	historyCmd.Flags().Bool("csv", false, "output as CSV")
+	historyCmd.Flags().BoolP("interactive", "i", false, "browse the history in an interactive table")
+	addFormatFlag(historyCmd)
 	rootCmd.AddCommand(historyCmd)
 }
 
@@ -46,37 +63,70 @@ func runHistory(cmd *cobra.Command, args []string) error {
 
 	start, end, err := parseHistoryDates(cmd)
 	if err != nil {
-		return err
+		return usageError(err)
 	}
 
 	serverURL := resolveServerURL(cfg)
-	imperial := cfg.IsImperial()
+	u, err := resolveUnits(cfg, sc)
+	if err != nil {
+		return err
+	}
 	resFlag, _ := cmd.Flags().GetString("resolution")
 	resolution := resolveResolution(resFlag, end.Sub(start))
-
-	units := "metric"
-	if imperial {
-		units = "imperial"
+	pressureFlag, _ := cmd.Flags().GetString("pressure")
+	ref, err := pressure.ParseReference(pressureFlag)
+	if err != nil {
+		return usageError(err)
 	}
-
-	var observations []tempest.Observation
-	if serverURL != "" {
-		resLabel := resFlag
-		if resLabel == "" {
-			resLabel = resolutionLabel(resolution)
+	columnNames, _ := cmd.Flags().GetStringSlice("columns")
+	columns, err := display.ParseHistoryColumns(columnNames)
+	if err != nil {
+		return usageError(err)
+	}
+	// JSON and CSV keep every field unless columns are chosen explicitly.
+	var fields []string
+	if len(columnNames) > 0 {
+		fields = historyFields(columns)
+	}
+	sortFlag, _ := cmd.Flags().GetString("sort")
+	var sortCol *display.HistoryColumn
+	var sortDesc bool
+	if sortFlag != "" {
+		col, desc, err := display.ParseHistorySort(sortFlag)
+		if err != nil {
+			return usageError(err)
 		}
-		// Always request metric from tempestd; display layer handles conversion + labeling.
-		observations, err = fetchHistoryFromServer(ctx, serverURL, sc.StationID, start, end, "metric", resLabel)
-	} else {
-		observations, err = fetchHistoryFromAPI(ctx, sc, start, end)
+		sortCol, sortDesc = &col, desc
+	}
+	csvOut, _ := cmd.Flags().GetBool("csv")
+	if csvOut && viper.GetBool("json") {
+		return usageError(fmt.Errorf("--csv and --json cannot be used together"))
 	}
+	interactive, _ := cmd.Flags().GetBool("interactive")
+	if interactive && (csvOut || viper.GetBool("json")) {
+		return usageError(fmt.Errorf("--interactive cannot be used with --json or --csv"))
+	}
+	format, err := outputFormat(cmd)
 	if err != nil {
-		return wrapAPIError(err)
+		return err
+	}
+	if format != display.FormatText && (csvOut || interactive) {
+		return usageError(fmt.Errorf("--format %s cannot be used with --csv or --interactive", format))
+	}
+	if interactive && !term.IsTerminal(int(os.Stdout.Fd())) {
+		return usageError(fmt.Errorf("--interactive needs a terminal"))
 	}
 
-	// Apply client-side downsampling
-	if resolution > 0 {
-		observations = downsample(observations, resolution)
+	observations, meta, err := fetchHistory(ctx, serverURL, sc, start, end, resFlag, resolution)
+	if err != nil {
+		return wrapAPIError(err)
+	}
+	reducer, err := historyPressure(ctx, serverURL, sc, ref)
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v; showing station pressure. Set elevation (meters) for the station in the config.\n", err)
+	}
+	if sortCol != nil {
+		display.SortHistory(observations, *sortCol, sortDesc, reducer)
 	}
 
 	if viper.GetBool("json") {
@@ -84,68 +134,314 @@ func runHistory(cmd *cobra.Command, args []string) error {
 		if jsonResLabel == "" {
 			jsonResLabel = resolutionLabel(resolution)
 		}
-		return jsonout.Write(cmd.OutOrStdout(), historyJSON(observations, sc, units, start, end, jsonResLabel))
+		out := historyJSON(observations, sc, u, reducer, start, end, jsonResLabel)
+		out.sourceJSON = meta.json()
+		out.selectFields(fields)
+		return staleResult(meta, jsonout.Write(cmd.OutOrStdout(), out))
+	}
+	if csvOut {
+		return staleResult(meta, writeHistoryCSV(cmd.OutOrStdout(), historyJSON(observations, sc, u, reducer, start, end, "").Observations, fields))
+	}
+
+	theme, err := newTheme()
+	if err != nil {
+		return err
+	}
+
+	if format != display.FormatText {
+		doc := display.HistoryDocument(theme, observations, columns, u, reducer)
+		return staleResult(meta, writeDocument(cmd.OutOrStdout(), theme, format, doc, &meta))
 	}
 
-	noColor := viper.GetBool("no-color")
-	theme := display.NewTheme(noColor, display.WithNoEmoji(viper.GetBool("no-emoji")))
+	if interactive {
+		m := display.NewHistoryBrowser(theme, observations, display.HistoryBrowserOptions{
+			Columns:  columns,
+			Units:    u,
+			Pressure: reducer,
+			Sort:     sortCol,
+			SortDesc: sortDesc,
+			Start:    start,
+			End:      end,
+			Load: func(start, end time.Time) ([]tempest.Observation, error) {
+				obs, _, err := fetchHistory(ctx, serverURL, sc, start, end, resFlag, resolution)
+				return obs, err
+			},
+			RowJSON: func(o *tempest.Observation) ([]byte, error) {
+				row := historyJSON([]tempest.Observation{*o}, sc, u, reducer, start, end, "").Observations[0]
+				row.fields = fields
+				return json.Marshal(row)
+			},
+			Clipboard: historyClipboard(cmd),
+		})
+		if _, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run(); err != nil {
+			return fmt.Errorf("history browser: %w", err)
+		}
+		return nil
+	}
 
 	termWidth := 80
 	if w, _, err := term.GetSize(0); err == nil && w > 0 {
 		termWidth = w
 	}
 
-	output := display.RenderHistory(theme, observations, imperial, termWidth)
+	output := display.RenderHistory(theme, observations, columns, u, reducer, termWidth)
 	_, _ = fmt.Fprintln(cmd.OutOrStdout(), output)
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), display.RenderSource(theme, string(meta.Source), meta.FetchedAt, meta.Stale))
 
-	return nil
+	return staleResult(meta, nil)
 }
 
+// fetchHistory fetches the observations between start and end from tempestd
+// or the cloud and downsamples them to resolution. resFlag is the resolution
+// as given, if any.
+func fetchHistory(ctx context.Context, serverURL string, sc *config.StationConfig, start, end time.Time, resFlag string, resolution time.Duration) ([]tempest.Observation, fetchMeta, error) {
+	fetchers := sourceFetchers[[]tempest.Observation]{
+		Tempestd: func(ctx context.Context, serverURL string) (*[]tempest.Observation, error) {
+			resLabel := resFlag
+			if resLabel == "" {
+				resLabel = resolutionLabel(resolution)
+			}
+			// Always request metric from tempestd; display layer handles conversion + labeling.
+			obs, err := fetchHistoryFromServer(ctx, serverURL, sc.StationID, start, end, "metric", resLabel)
+			if err != nil {
+				return nil, err
+			}
+			return &obs, nil
+		},
+		Cloud: func(ctx context.Context) (*[]tempest.Observation, error) {
+			deviceID, err := resolveDeviceID(ctx, sc, viper.GetString("device"))
+			if err != nil {
+				return nil, err
+			}
+			// sc is our own copy; the resolved device is reported in JSON output.
+			sc.DeviceID = deviceID
+			obs, err := fetchHistoryFromAPI(ctx, sc, start, end)
+			if err != nil {
+				return nil, err
+			}
+			return &obs, nil
+		},
+	}
+	// tempestd serves the station's observations and cannot pick a device,
+	// so --device is left to the cloud.
+	if viper.GetString("device") != "" {
+		order, err := sourceOrder()
+		if err != nil {
+			return nil, fetchMeta{}, err
+		}
+		if !slices.Contains(order, sourceCloud) {
+			return nil, fetchMeta{}, usageError(fmt.Errorf("--device is not supported by tempestd; add cloud to sources to read a specific device"))
+		}
+		fetchers.Tempestd = nil
+	}
+
+	result, meta, err := fetchWithFallback(ctx, serverURL, fetchers)
+	if err != nil {
+		return nil, meta, err
+	}
+	observations := *result
+
+	// Apply client-side downsampling
+	if resolution > 0 {
+		observations = downsample(observations, resolution)
+	}
+	return observations, meta, nil
+}
+
+// historySchemaVersion is bumped whenever the history JSON changes shape or
+// meaning. Version 1 was unversioned and reported unconverted metric values.
+const historySchemaVersion = 2
+
 type historyJSONOutput struct {
-	Station      stationMeta          `json:"station"`
-	Units        string               `json:"units"`
-	From         time.Time            `json:"from"`
-	To           time.Time            `json:"to"`
-	Resolution   string               `json:"resolution"`
-	Observations []historyObsJSON     `json:"observations"`
+	sourceJSON
+	SchemaVersion int         `json:"schema_version"`
+	Station       stationMeta `json:"station"`
+	Units         string      `json:"units"`
+	Unit          units.Set   `json:"unit"`
+	// Pressure says whether observation pressures are station pressure,
+	// sea-level pressure or the altimeter setting. Elevation, in meters, is
+	// what they were reduced from.
+	Pressure   pressure.Reference `json:"pressure"`
+	Elevation  *float64           `json:"elevation,omitempty"`
+	From       time.Time          `json:"from"`
+	To         time.Time          `json:"to"`
+	Resolution string             `json:"resolution"`
+	// Fields lists the observation fields kept by --columns; absent means
+	// every field.
+	Fields       []string         `json:"fields,omitempty"`
+	Observations []historyObsJSON `json:"observations"`
+}
+
+// selectFields limits every observation to fields, in that order. Nil keeps
+// every field.
+func (h *historyJSONOutput) selectFields(fields []string) {
+	h.Fields = fields
+	for i := range h.Observations {
+		h.Observations[i].fields = fields
+	}
 }
 
 type historyObsJSON struct {
 	Timestamp             time.Time `json:"timestamp"`
 	Temperature           float64   `json:"temperature"`
 	FeelsLike             float64   `json:"feels_like"`
+	DewPoint              float64   `json:"dew_point"`
+	WetBulb               float64   `json:"wet_bulb"`
 	Humidity              float64   `json:"humidity"`
 	WindSpeed             float64   `json:"wind_speed"`
+	WindGust              float64   `json:"wind_gust"`
+	WindLull              float64   `json:"wind_lull"`
 	WindDirection         float64   `json:"wind_direction"`
 	WindDirectionCardinal string    `json:"wind_direction_cardinal"`
+	WindSampleInterval    int       `json:"wind_sample_interval"`
 	Pressure              float64   `json:"pressure"`
 	Rain                  float64   `json:"rain"`
+	PrecipitationType     string    `json:"precipitation_type"`
 	UVIndex               float64   `json:"uv_index"`
+	SolarRadiation        float64   `json:"solar_radiation"`
+	Illuminance           float64   `json:"illuminance"`
+	LightningCount        int       `json:"lightning_count"`
+	LightningDistance     float64   `json:"lightning_distance"`
+	Battery               float64   `json:"battery"`
+	ReportInterval        int       `json:"report_interval"`
+
+	// fields, if set, are the only fields marshaled, in that order.
+	fields []string
 }
 
-func historyJSON(obs []tempest.Observation, sc *config.StationConfig, units string, start, end time.Time, resolution string) historyJSONOutput {
+// MarshalJSON encodes o, keeping only o.fields if set.
+func (o historyObsJSON) MarshalJSON() ([]byte, error) {
+	type plain historyObsJSON
+	data, err := json.Marshal(plain(o))
+	if err != nil || o.fields == nil {
+		return data, err
+	}
+	all, err := o.fieldValues()
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range o.fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(f)
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(all[f])
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// fieldValues returns every field of o as encoded JSON, by name.
+func (o historyObsJSON) fieldValues() (map[string]json.RawMessage, error) {
+	type plain historyObsJSON
+	data, err := json.Marshal(plain(o))
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	err = json.Unmarshal(data, &all)
+	return all, err
+}
+
+// historyFields returns the JSON and CSV fields of columns, in order.
+func historyFields(columns []display.HistoryColumn) []string {
+	var fields []string
+	for _, c := range columns {
+		fields = append(fields, c.Fields...)
+	}
+	return fields
+}
+
+// allHistoryFields returns every observation field in declaration order.
+func allHistoryFields() []string {
+	t := reflect.TypeOf(historyObsJSON{})
+	var fields []string
+	for i := range t.NumField() {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); name != "" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// writeHistoryCSV writes observations as CSV with a header row, limited to
+// fields if set. Values are converted to the chosen units like JSON output.
+func writeHistoryCSV(w io.Writer, obs []historyObsJSON, fields []string) error {
+	if fields == nil {
+		fields = allHistoryFields()
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(fields); err != nil {
+		return err
+	}
+	record := make([]string, len(fields))
+	for _, o := range obs {
+		all, err := o.fieldValues()
+		if err != nil {
+			return err
+		}
+		for i, f := range fields {
+			var s string
+			if err := json.Unmarshal(all[f], &s); err != nil {
+				s = string(all[f]) // not a string: a number
+			}
+			record[i] = s
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func historyJSON(obs []tempest.Observation, sc *config.StationConfig, u units.Set, p pressure.Reducer, start, end time.Time, resolution string) historyJSONOutput {
 	items := make([]historyObsJSON, len(obs))
 	for i, o := range obs {
 		items[i] = historyObsJSON{
 			Timestamp:             o.Timestamp,
-			Temperature:           o.AirTemperature,
-			FeelsLike:             o.FeelsLike,
+			Temperature:           u.Temp(o.AirTemperature),
+			FeelsLike:             u.Temp(o.FeelsLike),
+			DewPoint:              u.Temp(o.DewPoint),
+			WetBulb:               u.Temp(o.WetBulb),
 			Humidity:              o.RelativeHumidity,
-			WindSpeed:             o.WindAvg,
+			WindSpeed:             u.Speed(o.WindAvg),
+			WindGust:              u.Speed(o.WindGust),
+			WindLull:              u.Speed(o.WindLull),
 			WindDirection:         o.WindDirection,
 			WindDirectionCardinal: tempest.WindDirectionToCompass(o.WindDirection),
-			Pressure:              o.StationPressure,
-			Rain:                  o.RainAccumulation,
+			WindSampleInterval:    o.WindSampleInterval,
+			Pressure:              u.Press(p.Reduce(o.StationPressure, o.AirTemperature)),
+			Rain:                  u.Precip(o.RainAccumulation),
+			PrecipitationType:     display.PrecipitationTypeName(o.PrecipitationType),
 			UVIndex:               o.UVIndex,
+			SolarRadiation:        o.SolarRadiation,
+			Illuminance:           o.Illuminance,
+			LightningCount:        o.LightningCount,
+			LightningDistance:     u.Dist(o.LightningAvgDist),
+			Battery:               o.Battery,
+			ReportInterval:        o.ReportInterval,
 		}
 	}
+	var elevation *float64
+	if p.Reference != pressure.Station {
+		elevation = &p.ElevationM
+	}
 	return historyJSONOutput{
+		SchemaVersion: historySchemaVersion,
 		Station: stationMeta{
 			Name:      sc.Name,
 			StationID: sc.StationID,
 			DeviceID:  sc.DeviceID,
 		},
-		Units:        units,
+		Units:        u.System(),
+		Unit:         u,
+		Pressure:     p.Reference,
+		Elevation:    elevation,
 		From:         start,
 		To:           end,
 		Resolution:   resolution,
@@ -153,6 +449,58 @@ func historyJSON(obs []tempest.Observation, sc *config.StationConfig, units stri
 	}
 }
 
+// historyPressure returns the reducer for ref, using the station's configured
+// elevation or else its metadata. If the elevation is unknown it falls back to
+// station pressure and returns why.
+func historyPressure(ctx context.Context, serverURL string, sc *config.StationConfig, ref pressure.Reference) (pressure.Reducer, error) {
+	if ref == pressure.Station {
+		return pressure.Reducer{Reference: ref}, nil
+	}
+	elevation, err := stationElevation(ctx, serverURL, sc)
+	if err != nil {
+		return pressure.Reducer{Reference: pressure.Station}, err
+	}
+	return pressure.Reducer{Reference: ref, ElevationM: elevation}, nil
+}
+
+// stationElevation returns sc's elevation in meters: the configured value, or
+// else the one in the station's metadata.
+func stationElevation(ctx context.Context, serverURL string, sc *config.StationConfig) (float64, error) {
+	if sc.Elevation != nil {
+		return *sc.Elevation, nil
+	}
+	station, err := fetchStationMetadata(ctx, serverURL, sc)
+	if err != nil {
+		return 0, fmt.Errorf("station elevation unknown: %w", err)
+	}
+	return station.Elevation, nil
+}
+
+// fetchStationMetadata returns sc's station metadata from tempestd or the
+// cloud, without counting it towards the command's fetch time.
+func fetchStationMetadata(ctx context.Context, serverURL string, sc *config.StationConfig) (*tempest.Station, error) {
+	station, _, err := fetchWithFallback(withoutFetchInfo(ctx), serverURL, sourceFetchers[tempest.Station]{
+		Tempestd: func(ctx context.Context, serverURL string) (*tempest.Station, error) {
+			return fetchStationFromServer(ctx, serverURL, sc.StationID)
+		},
+		Cloud: func(ctx context.Context) (*tempest.Station, error) {
+			return fetchStationFromAPI(ctx, sc, lazyStationClient(ctx, sc))
+		},
+	})
+	return station, err
+}
+
+// historyClipboard returns where the history browser writes its OSC 52
+// clipboard sequence: stderr, which reaches the terminal without mixing into
+// the browser's frames on stdout. It returns nil if stderr is redirected,
+// since the sequence would only end up in a file.
+func historyClipboard(cmd *cobra.Command) io.Writer {
+	if f, ok := cmd.ErrOrStderr().(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return f
+	}
+	return nil
+}
+
 func resolutionLabel(d time.Duration) string {
 	switch d {
 	case time.Minute:
@@ -168,22 +516,31 @@ func resolutionLabel(d time.Duration) string {
 	}
 }
 
+// fetchHistoryFromAPI returns sc.DeviceID's observations; callers resolve
+// the device first with resolveDeviceID.
 func fetchHistoryFromAPI(ctx context.Context, sc *config.StationConfig, start, end time.Time) ([]tempest.Observation, error) {
-	client, err := tempest.NewClient(sc.Token)
-	if err != nil {
-		return nil, fmt.Errorf("creating API client: %w", err)
-	}
-
 	if sc.DeviceID <= 0 {
-		return nil, fmt.Errorf("device_id is required for historical data; add it to your station config or re-run 'tempest config init'")
+		return nil, newError(KindConfig, nil, "no device to fetch historical data from for station %d", sc.StationID)
 	}
 
-	observations, err := client.GetDeviceObservations(ctx, sc.DeviceID, start, end)
+	key := cloudCacheKey("history", sc,
+		fmt.Sprintf("device=%d", sc.DeviceID), start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339))
+	observations, err := cachedFetch(ctx, key, historyTTL(end), func() (*[]tempest.Observation, error) {
+		client, err := newStationClient(ctx, sc)
+		if err != nil {
+			return nil, fmt.Errorf("creating API client: %w", err)
+		}
+		obs, err := client.GetDeviceObservations(ctx, sc.DeviceID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		return &obs, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("fetching observations: %w", err)
 	}
 
-	return observations, nil
+	return *observations, nil
 }
 
 // serverObservation has JSON tags matching tempestd's snake_case response keys.
@@ -191,13 +548,16 @@ func fetchHistoryFromAPI(ctx context.Context, sc *config.StationConfig, start, e
 type serverObservation struct {
 	Timestamp          time.Time `json:"timestamp"`
 	StationID          int       `json:"station_id"`
+	DeviceID           int       `json:"device_id"`
 	WindLull           float64   `json:"wind_lull"`
 	WindAvg            float64   `json:"wind_avg"`
 	WindGust           float64   `json:"wind_gust"`
 	WindDirection      float64   `json:"wind_direction"`
+	WindSampleInterval int       `json:"wind_sample_interval"`
 	StationPressure    float64   `json:"station_pressure"`
 	AirTemperature     float64   `json:"air_temperature"`
 	RelativeHumidity   float64   `json:"relative_humidity"`
+	Illuminance        float64   `json:"illuminance"`
 	UVIndex            float64   `json:"uv_index"`
 	SolarRadiation     float64   `json:"solar_radiation"`
 	RainAccumulation   float64   `json:"rain_accumulation"`
@@ -205,8 +565,10 @@ type serverObservation struct {
 	LightningAvgDist   float64   `json:"lightning_avg_distance"`
 	LightningCount     int       `json:"lightning_strike_count"`
 	Battery            float64   `json:"battery"`
+	ReportInterval     int       `json:"report_interval"`
 	FeelsLike          float64   `json:"feels_like"`
 	DewPoint           float64   `json:"dew_point"`
+	WetBulb            float64   `json:"wet_bulb"`
 }
 
 // observationsEnvelope matches the envelope response from tempestd's
@@ -217,24 +579,29 @@ type observationsEnvelope struct {
 
 func serverObsToObservation(s serverObservation) tempest.Observation {
 	return tempest.Observation{
-		Timestamp:         s.Timestamp,
-		StationID:         s.StationID,
-		WindLull:          s.WindLull,
-		WindAvg:           s.WindAvg,
-		WindGust:          s.WindGust,
-		WindDirection:     s.WindDirection,
-		StationPressure:   s.StationPressure,
-		AirTemperature:    s.AirTemperature,
-		RelativeHumidity:  s.RelativeHumidity,
-		UVIndex:           s.UVIndex,
-		SolarRadiation:    s.SolarRadiation,
-		RainAccumulation:  s.RainAccumulation,
-		PrecipitationType: s.PrecipitationType,
-		LightningAvgDist:  s.LightningAvgDist,
-		LightningCount:    s.LightningCount,
-		Battery:           s.Battery,
-		FeelsLike:         s.FeelsLike,
-		DewPoint:          s.DewPoint,
+		Timestamp:          s.Timestamp,
+		StationID:          s.StationID,
+		DeviceID:           s.DeviceID,
+		WindLull:           s.WindLull,
+		WindAvg:            s.WindAvg,
+		WindGust:           s.WindGust,
+		WindDirection:      s.WindDirection,
+		WindSampleInterval: s.WindSampleInterval,
+		StationPressure:    s.StationPressure,
+		AirTemperature:     s.AirTemperature,
+		RelativeHumidity:   s.RelativeHumidity,
+		Illuminance:        s.Illuminance,
+		UVIndex:            s.UVIndex,
+		SolarRadiation:     s.SolarRadiation,
+		RainAccumulation:   s.RainAccumulation,
+		PrecipitationType:  s.PrecipitationType,
+		LightningAvgDist:   s.LightningAvgDist,
+		LightningCount:     s.LightningCount,
+		Battery:            s.Battery,
+		ReportInterval:     s.ReportInterval,
+		FeelsLike:          s.FeelsLike,
+		DewPoint:           s.DewPoint,
+		WetBulb:            s.WetBulb,
 	}
 }
 
@@ -245,7 +612,9 @@ func fetchHistoryFromServer(ctx context.Context, serverURL string, stationID int
 	params.Set("units", units)
 	params.Set("resolution", resolution)
 	path := fmt.Sprintf("/api/v1/stations/%d/observations?%s", stationID, params.Encode())
-	result, err := fetchFromTempestd[observationsEnvelope](ctx, serverURL, path)
+	result, err := cachedFetch(ctx, cacheKey("history", serverURL, stationID, params.Encode()), historyTTL(end), func() (*observationsEnvelope, error) {
+		return fetchFromTempestd[observationsEnvelope](ctx, serverURL, path)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -257,29 +626,35 @@ func fetchHistoryFromServer(ctx context.Context, serverURL string, stationID int
 }
 
 func parseHistoryDates(cmd *cobra.Command) (time.Time, time.Time, error) {
+	return parseHistoryDatesIn(cmd, time.UTC)
+}
+
+// parseHistoryDatesIn is parseHistoryDates with days starting at midnight
+// in loc.
+func parseHistoryDatesIn(cmd *cobra.Command, loc *time.Location) (time.Time, time.Time, error) {
 	dateStr, _ := cmd.Flags().GetString("date")
 	fromStr, _ := cmd.Flags().GetString("from")
 	toStr, _ := cmd.Flags().GetString("to")
 
 	if dateStr != "" {
-		d, err := time.Parse("2006-01-02", dateStr)
+		d, err := time.ParseInLocation("2006-01-02", dateStr, loc)
 		if err != nil {
 			return time.Time{}, time.Time{}, fmt.Errorf("invalid --date format (use YYYY-MM-DD): %w", err)
 		}
-		return d, d.Add(24 * time.Hour), nil
+		return d, d.AddDate(0, 0, 1), nil
 	}
 
 	if fromStr != "" && toStr != "" {
-		from, err := time.Parse("2006-01-02", fromStr)
+		from, err := time.ParseInLocation("2006-01-02", fromStr, loc)
 		if err != nil {
 			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from format (use YYYY-MM-DD): %w", err)
 		}
-		to, err := time.Parse("2006-01-02", toStr)
+		to, err := time.ParseInLocation("2006-01-02", toStr, loc)
 		if err != nil {
 			return time.Time{}, time.Time{}, fmt.Errorf("invalid --to format (use YYYY-MM-DD): %w", err)
 		}
 		// Include the end date fully
-		return from, to.Add(24 * time.Hour), nil
+		return from, to.AddDate(0, 0, 1), nil
 	}
 
 	if fromStr != "" || toStr != "" {
@@ -8,8 +8,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/pressure"
 	tempest "github.com/chadmayfield/tempest-go"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func TestFetchHistoryFromServer_QueryParams(t *testing.T) {
@@ -273,3 +276,44 @@ func TestParseHistoryDates(t *testing.T) {
 		}
 	})
 }
+
+func TestHistoryPressure(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("sources", []string{"tempestd"})
+	viper.Set("no-cache", true)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/stations/1001" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"station_id": 1001, "elevation": 1500}`))
+	}))
+	defer srv.Close()
+
+	elevation := 320.0
+	tests := []struct {
+		name    string
+		sc      config.StationConfig
+		ref     pressure.Reference
+		want    pressure.Reducer
+		wantErr bool
+	}{
+		{"station", config.StationConfig{StationID: 1001}, pressure.Station, pressure.Reducer{Reference: pressure.Station}, false},
+		{"configured elevation", config.StationConfig{StationID: 7, Elevation: &elevation}, pressure.SeaLevel, pressure.Reducer{Reference: pressure.SeaLevel, ElevationM: 320}, false},
+		{"metadata elevation", config.StationConfig{StationID: 1001}, pressure.Altimeter, pressure.Reducer{Reference: pressure.Altimeter, ElevationM: 1500}, false},
+		{"unknown elevation", config.StationConfig{StationID: 7}, pressure.SeaLevel, pressure.Reducer{Reference: pressure.Station}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := historyPressure(context.Background(), srv.URL, &tt.sc, tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("reducer = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
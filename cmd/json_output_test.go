@@ -2,11 +2,15 @@ package cmd
 
 import (
 	"encoding/json"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/chadmayfield/tempest-cli/internal/config"
 	"github.com/chadmayfield/tempest-cli/internal/display"
+	"github.com/chadmayfield/tempest-cli/internal/pressure"
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	tempest "github.com/chadmayfield/tempest-go"
 )
 
@@ -44,7 +48,7 @@ func TestCurrentJSON(t *testing.T) {
 	}
 
 	// Test metric
-	result := currentJSON(obs, station, sc, "metric", false)
+	result := currentJSON(obs, station, sc, units.MetricSet)
 	if result.Station.Name != "Home Station" {
 		t.Errorf("Station.Name = %q, want %q", result.Station.Name, "Home Station")
 	}
@@ -65,7 +69,7 @@ func TestCurrentJSON(t *testing.T) {
 	}
 
 	// Test imperial conversion
-	resultImp := currentJSON(obs, station, sc, "imperial", true)
+	resultImp := currentJSON(obs, station, sc, units.ImperialSet)
 	if resultImp.Temperature == 22.5 {
 		t.Error("imperial temperature should be converted from celsius")
 	}
@@ -94,7 +98,7 @@ func TestCurrentJSONFallbackStationName(t *testing.T) {
 	station := &tempest.Station{Name: ""} // empty station name
 	sc := &config.StationConfig{Name: "Config Name"}
 
-	result := currentJSON(obs, station, sc, "metric", false)
+	result := currentJSON(obs, station, sc, units.MetricSet)
 	if result.Station.Name != "Config Name" {
 		t.Errorf("expected fallback to config name, got %q", result.Station.Name)
 	}
@@ -133,7 +137,7 @@ func TestForecastJSON(t *testing.T) {
 	sc := &config.StationConfig{Name: "Test", StationID: 12345, DeviceID: 67890}
 
 	// Metric, 2 days
-	result := forecastJSON(forecast, sc, "metric", false, 2)
+	result := forecastJSON(forecast, sc, units.MetricSet, 2)
 	if len(result.Days) != 2 {
 		t.Errorf("len(Days) = %d, want 2", len(result.Days))
 	}
@@ -148,13 +152,13 @@ func TestForecastJSON(t *testing.T) {
 	}
 
 	// Imperial
-	resultImp := forecastJSON(forecast, sc, "imperial", true, 1)
+	resultImp := forecastJSON(forecast, sc, units.ImperialSet, 1)
 	if resultImp.Days[0].HighTemp == 25.0 {
 		t.Error("imperial high temp should be converted")
 	}
 
 	// Requesting more days than available
-	resultAll := forecastJSON(forecast, sc, "metric", false, 10)
+	resultAll := forecastJSON(forecast, sc, units.MetricSet, 10)
 	if len(resultAll.Days) != 3 {
 		t.Errorf("len(Days) = %d, want 3 (capped by available data)", len(resultAll.Days))
 	}
@@ -196,10 +200,11 @@ func TestHistoryJSON(t *testing.T) {
 		},
 	}
 	sc := &config.StationConfig{Name: "Test", StationID: 12345, DeviceID: 67890}
+	stationPressure := pressure.Reducer{Reference: pressure.Station}
 	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)
 
-	result := historyJSON(obs, sc, "metric", start, end, "5m")
+	result := historyJSON(obs, sc, units.MetricSet, stationPressure, start, end, "5m")
 	if result.Units != "metric" {
 		t.Errorf("Units = %q, want %q", result.Units, "metric")
 	}
@@ -219,8 +224,41 @@ func TestHistoryJSON(t *testing.T) {
 		t.Errorf("Station.StationID = %d, want 12345", result.Station.StationID)
 	}
 
+	if result.SchemaVersion != historySchemaVersion || result.Pressure != pressure.Station {
+		t.Errorf("schema_version = %d, pressure = %q", result.SchemaVersion, result.Pressure)
+	}
+
+	// Every field is converted to the requested units
+	full := []tempest.Observation{{
+		AirTemperature:    0,
+		DewPoint:          -10,
+		WindAvg:           10,
+		WindGust:          20,
+		WindLull:          5,
+		StationPressure:   1000,
+		RainAccumulation:  25.4,
+		PrecipitationType: 3,
+		LightningCount:    2,
+		LightningAvgDist:  1.609344,
+		SolarRadiation:    450,
+		Battery:           2.6,
+	}}
+	imp := historyJSON(full, sc, units.ImperialSet, stationPressure, start, end, "1m").Observations[0]
+	if imp.Temperature != 32 || imp.DewPoint != 14 {
+		t.Errorf("temperatures = %v, %v, want 32, 14", imp.Temperature, imp.DewPoint)
+	}
+	if imp.WindGust <= imp.WindSpeed || imp.WindSpeed <= imp.WindLull || math.Abs(imp.WindSpeed-22.37) > 0.01 {
+		t.Errorf("wind = %v/%v/%v, want mph", imp.WindLull, imp.WindSpeed, imp.WindGust)
+	}
+	if math.Abs(imp.Pressure-29.53) > 0.01 || math.Abs(imp.Rain-1) > 0.001 || math.Abs(imp.LightningDistance-1) > 0.001 {
+		t.Errorf("pressure %v, rain %v, lightning %v not converted", imp.Pressure, imp.Rain, imp.LightningDistance)
+	}
+	if imp.PrecipitationType != "rain_hail" || imp.LightningCount != 2 || imp.SolarRadiation != 450 || imp.Battery != 2.6 {
+		t.Errorf("fields dropped: %+v", imp)
+	}
+
 	// Empty observations
-	empty := historyJSON(nil, sc, "metric", start, end, "1m")
+	empty := historyJSON(nil, sc, units.MetricSet, stationPressure, start, end, "1m")
 	if len(empty.Observations) != 0 {
 		t.Errorf("expected 0 observations, got %d", len(empty.Observations))
 	}
@@ -236,6 +274,58 @@ func TestHistoryJSON(t *testing.T) {
 	}
 }
 
+func TestHistoryColumnsSelectFields(t *testing.T) {
+	obs := []tempest.Observation{{
+		Timestamp:      time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+		AirTemperature: 22.5,
+		WindGust:       5.25,
+		WindDirection:  180,
+	}}
+	sc := &config.StationConfig{Name: "Test"}
+	reducer := pressure.Reducer{Reference: pressure.Station}
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	cols, err := display.ParseHistoryColumns([]string{"gust", "temp", "dir"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := historyFields(cols)
+	want := []string{"timestamp", "wind_gust", "temperature", "wind_direction", "wind_direction_cardinal"}
+	if strings.Join(fields, ",") != strings.Join(want, ",") {
+		t.Fatalf("fields = %v, want %v", fields, want)
+	}
+
+	out := historyJSON(obs, sc, units.MetricSet, reducer, start, start, "1m")
+	out.selectFields(fields)
+	data, err := json.Marshal(out.Observations[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := `{"timestamp":"2024-01-15T10:00:00Z","wind_gust":5.25,"temperature":22.5,"wind_direction":180,"wind_direction_cardinal":"S"}`
+	if string(data) != wantJSON {
+		t.Errorf("JSON = %s, want %s", data, wantJSON)
+	}
+
+	var buf strings.Builder
+	if err := writeHistoryCSV(&buf, out.Observations, fields); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := "timestamp,wind_gust,temperature,wind_direction,wind_direction_cardinal\n" +
+		"2024-01-15T10:00:00Z,5.25,22.5,180,S\n"
+	if buf.String() != wantCSV {
+		t.Errorf("CSV = %q, want %q", buf.String(), wantCSV)
+	}
+
+	// Without a selection every field is written.
+	buf.Reset()
+	if err := writeHistoryCSV(&buf, historyJSON(obs, sc, units.MetricSet, reducer, start, start, "").Observations, nil); err != nil {
+		t.Fatal(err)
+	}
+	header, _, _ := strings.Cut(buf.String(), "\n")
+	if got := strings.Count(header, ",") + 1; got != len(allHistoryFields()) || !strings.HasSuffix(header, ",report_interval") {
+		t.Errorf("header = %q", header)
+	}
+}
+
 func TestStationsJSON(t *testing.T) {
 	rows := []display.StationRow{
 		{
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
+	"github.com/spf13/viper"
+)
+
+// resolveLocale returns the locale for labels, numbers, dates and times:
+// --lang, TEMPEST_LANG or the lang config key if set, else the locale named
+// by LC_ALL, LC_MESSAGES or LANG. JSON output is never localized.
+func resolveLocale() (*i18n.Locale, error) {
+	tag := viper.GetString("lang")
+	if tag == "" {
+		return i18n.FromEnv(), nil
+	}
+	loc, err := i18n.Lookup(tag)
+	if err != nil {
+		if f := rootCmd.PersistentFlags().Lookup("lang"); f != nil && f.Changed {
+			return nil, usageError(err)
+		}
+		return nil, wrapConfigError(err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestResolveLocale(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "fr_FR.UTF-8")
+	loc, err := resolveLocale()
+	if err != nil || loc.Tag != "fr" {
+		t.Fatalf("from LANG: %v, %v", loc, err)
+	}
+
+	viper.Set("lang", "en_GB")
+	if loc, err = resolveLocale(); err != nil || loc.Tag != "en-GB" {
+		t.Fatalf("lang key wins over LANG: %v, %v", loc, err)
+	}
+
+	viper.Set("lang", "xx")
+	if _, err := resolveLocale(); errorKind(err) != KindConfig {
+		t.Errorf("unsupported lang: err = %v, want config error", err)
+	}
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain points the user cache directory at a throwaway location so that
+// tests never read from or write to the real response cache, and pins the
+// locale so that rendered output is English whatever the host's LANG.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "tempest-cmd-test-")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("XDG_CACHE_HOME", dir)
+	_ = os.Setenv("HOME", dir)
+	_ = os.Setenv("LC_ALL", "C")
+
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
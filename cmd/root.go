@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/chadmayfield/tempest-cli/internal/config"
 	"github.com/spf13/cobra"
@@ -28,21 +29,44 @@ func Execute(ctx context.Context) error {
 
 func init() {
 	cobra.OnInitialize(initConfig)
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError(err)
+	})
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default ~/.config/tempest/config.yaml)")
 	rootCmd.PersistentFlags().String("station", "", "station name from config")
+	rootCmd.PersistentFlags().String("device", "", "sensor to read device observations from: device ID, serial number, or type (ST, AR, SK)")
 	rootCmd.PersistentFlags().String("units", "", "unit system: metric or imperial")
+	rootCmd.PersistentFlags().String("temp-unit", "", "temperature unit: c or f")
+	rootCmd.PersistentFlags().String("wind-unit", "", "wind speed unit: m/s, km/h, mph, kn or bft")
+	rootCmd.PersistentFlags().String("pressure-unit", "", "pressure unit: hpa, mb, kpa, inhg or mmhg")
+	rootCmd.PersistentFlags().String("precip-unit", "", "precipitation unit: mm or in")
+	rootCmd.PersistentFlags().String("distance-unit", "", "distance unit: km or mi")
 	rootCmd.PersistentFlags().String("server", "", "tempestd server URL for local data")
 	rootCmd.PersistentFlags().Bool("json", false, "output as JSON")
 	rootCmd.PersistentFlags().Bool("no-color", false, "disable colored output")
 	rootCmd.PersistentFlags().Bool("no-emoji", false, "use text symbols instead of emoji for condition icons")
+	rootCmd.PersistentFlags().String("lang", "", "language and number, date and time formats, e.g. de or en-GB (default from LANG)")
+	rootCmd.PersistentFlags().String("theme", "", "color theme: default, high-contrast, colorblind, a theme name from the themes directory, or a path to a theme file")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "bypass the response cache entirely")
+	rootCmd.PersistentFlags().Bool("refresh", false, "ignore cached responses and fetch fresh data (still updates the cache)")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "report each network request and retry on stderr")
 
 	_ = viper.BindPFlag("station", rootCmd.PersistentFlags().Lookup("station"))
+	_ = viper.BindPFlag("device", rootCmd.PersistentFlags().Lookup("device"))
 	_ = viper.BindPFlag("units", rootCmd.PersistentFlags().Lookup("units"))
+	for _, q := range unitFlags {
+		_ = viper.BindPFlag(q.flag, rootCmd.PersistentFlags().Lookup(q.flag))
+	}
 	_ = viper.BindPFlag("server", rootCmd.PersistentFlags().Lookup("server"))
 	_ = viper.BindPFlag("json", rootCmd.PersistentFlags().Lookup("json"))
 	_ = viper.BindPFlag("no-color", rootCmd.PersistentFlags().Lookup("no-color"))
 	_ = viper.BindPFlag("no-emoji", rootCmd.PersistentFlags().Lookup("no-emoji"))
+	_ = viper.BindPFlag("theme", rootCmd.PersistentFlags().Lookup("theme"))
+	_ = viper.BindPFlag("lang", rootCmd.PersistentFlags().Lookup("lang"))
+	_ = viper.BindPFlag("no-cache", rootCmd.PersistentFlags().Lookup("no-cache"))
+	_ = viper.BindPFlag("refresh", rootCmd.PersistentFlags().Lookup("refresh"))
+	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 }
 
 func initConfig() {
@@ -69,6 +93,7 @@ func initConfig() {
 	}
 
 	viper.SetEnvPrefix("TEMPEST")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
 	// Set defaults per spec
@@ -78,6 +103,11 @@ func initConfig() {
 	_ = viper.BindEnv("units")
 	_ = viper.BindEnv("station")
 	_ = viper.BindEnv("server")
+	_ = viper.BindEnv("cloud_url")
+	// tempestd credentials can be supplied as TEMPEST_TEMPESTD_TOKEN etc.
+	_ = viper.BindEnv("tempestd.token")
+	_ = viper.BindEnv("tempestd.username")
+	_ = viper.BindEnv("tempestd.password")
 
 	// Detect NO_COLOR environment variable (https://no-color.org/)
 	if _, exists := os.LookupEnv("NO_COLOR"); exists {
@@ -114,14 +144,28 @@ func resolveServerURL(cfg *config.Config) string {
 	return s
 }
 
-func checkConfigPermissions(path string) {
-	info, err := os.Stat(path)
-	if err != nil {
+// verbosef prints a progress line to stderr when --verbose is set.
+func verbosef(format string, args ...any) {
+	if !viper.GetBool("verbose") {
 		return
 	}
-	mode := info.Mode().Perm()
-	if mode&0044 != 0 {
+	_, _ = fmt.Fprintf(rootCmd.ErrOrStderr(), format+"\n", args...)
+}
+
+func checkConfigPermissions(path string) {
+	if mode, open := configReadableByOthers(path); open {
 		fmt.Fprintf(os.Stderr, "Warning: config file %s is readable by others (mode %04o). Consider: chmod 600 %s\n",
 			path, mode, path)
 	}
 }
+
+// configReadableByOthers reports the file's permissions and whether group or
+// other users can read it.
+func configReadableByOthers(path string) (os.FileMode, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, false
+	}
+	mode := info.Mode().Perm()
+	return mode, mode&0044 != 0
+}
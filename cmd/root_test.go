@@ -76,6 +76,8 @@ func TestValidateServerURL(t *testing.T) {
 		{"http://localhost:8080", false},
 		{"https://tempestd.local:443", false},
 		{"http://192.168.1.100:8080", false},
+		{"unix:///run/tempestd.sock", false},
+		{"unix://", true},
 		{"ftp://evil.com", true},
 		{"javascript:alert(1)", true},
 		{"file:///etc/passwd", true},
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// dataSource names a place commands can read data from. The order in which
+// sources are tried is set by the sources config key.
+type dataSource string
+
+const (
+	sourceUDP      dataSource = "udp"
+	sourceTempestd dataSource = "tempestd"
+	sourceCache    dataSource = "cache"
+	sourceCloud    dataSource = "cloud"
+)
+
+// defaultSourceOrder prefers a local tempestd, then the cloud API, and falls
+// back to stale cached data if both are unreachable. tempestd is skipped when
+// no server is configured.
+var defaultSourceOrder = []dataSource{sourceTempestd, sourceCloud, sourceCache}
+
+// errNotCached is returned by cache-only fetches that find no entry.
+var errNotCached = errors.New("no cached data")
+
+// errSourceSkipped is returned by fetchers that cannot serve the request at
+// all, such as UDP for a station whose sensor serial is unknown. The source is
+// passed over as if it were not configured.
+var errSourceSkipped = errors.New("source does not apply")
+
+// fetchMeta records where a command's data came from.
+type fetchMeta struct {
+	Source dataSource
+	// FetchedAt is when the data was retrieved from its origin. For cached
+	// data this is the time it was originally fetched, not now.
+	FetchedAt time.Time
+	// Stale is set when the data was served from the cache after the live
+	// sources failed.
+	Stale bool
+}
+
+// sourceJSON is embedded in every JSON payload to report the data's origin.
+type sourceJSON struct {
+	Source    dataSource `json:"source"`
+	FetchedAt time.Time  `json:"fetched_at"`
+	Stale     bool       `json:"stale"`
+}
+
+func (m fetchMeta) json() sourceJSON {
+	return sourceJSON{Source: m.Source, FetchedAt: m.FetchedAt, Stale: m.Stale}
+}
+
+// staleResult returns err, or if the command succeeded with stale data, a
+// quiet error that exits with ExitStale. The output already marks the data
+// stale, so the error is not printed.
+func staleResult(meta fetchMeta, err error) error {
+	if err != nil || !meta.Stale {
+		return err
+	}
+	return &Error{
+		Kind:  KindStale,
+		Msg:   fmt.Sprintf("live sources failed; showing %s data fetched %s", meta.Source, meta.FetchedAt.Format(time.RFC3339)),
+		Quiet: true,
+	}
+}
+
+// sourceFetchers holds a command's fetch function for each source. A nil
+// entry means the command cannot use that source, and it is skipped.
+type sourceFetchers[T any] struct {
+	UDP      func(ctx context.Context) (*T, error)
+	Tempestd func(ctx context.Context, serverURL string) (*T, error)
+	Cloud    func(ctx context.Context) (*T, error)
+}
+
+// sourceOrder returns the configured source order. The sources key may be a
+// YAML list or, from the environment, a comma- or space-separated string.
+func sourceOrder() ([]dataSource, error) {
+	raw := viper.GetStringSlice("sources")
+	if len(raw) == 0 {
+		return defaultSourceOrder, nil
+	}
+
+	var order []dataSource
+	for _, item := range raw {
+		for _, name := range strings.FieldsFunc(item, func(r rune) bool { return r == ',' || r == ' ' }) {
+			src := dataSource(strings.ToLower(name))
+			switch src {
+			case sourceUDP, sourceTempestd, sourceCache, sourceCloud:
+			default:
+				return nil, newError(KindConfig, nil, "unknown data source %q in sources: must be udp, tempestd, cache, or cloud", name)
+			}
+			if !slices.Contains(order, src) {
+				order = append(order, src)
+			}
+		}
+	}
+	if len(order) == 0 {
+		return defaultSourceOrder, nil
+	}
+	return order, nil
+}
+
+// fetchWithFallback tries each configured source in order and returns the
+// first successful result. If every source fails, the error from the first
+// source that was attempted is returned, since it is usually the most relevant.
+func fetchWithFallback[T any](ctx context.Context, serverURL string, f sourceFetchers[T]) (*T, fetchMeta, error) {
+	order, err := sourceOrder()
+	if err != nil {
+		return nil, fetchMeta{}, err
+	}
+
+	live := liveFetchers(order, serverURL, f)
+
+	var firstErr error
+	tried := 0
+	for _, src := range order {
+		var fetch func(context.Context) (*T, error)
+		if src == sourceCache {
+			if openCache() == nil || len(live) == 0 {
+				continue
+			}
+			fetch = func(ctx context.Context) (*T, error) {
+				return fetchFromCache(ctx, order, live)
+			}
+		} else if fetch = live[src]; fetch == nil {
+			continue
+		}
+		tried++
+
+		fctx, info := withFetchInfo(ctx)
+		v, err := fetch(fctx)
+		if err == nil {
+			meta := fetchMeta{Source: src, FetchedAt: info.at(), Stale: src == sourceCache}
+			slog.Debug("data source succeeded", "source", src, "fetched_at", meta.FetchedAt)
+			return v, meta, nil
+		}
+		if ctx.Err() != nil {
+			return nil, fetchMeta{}, ctx.Err()
+		}
+		if errors.Is(err, errSourceSkipped) {
+			tried--
+			verbosef("source %s skipped: %v", src, err)
+			continue
+		}
+		verbosef("source %s failed: %v", src, err)
+		slog.Debug("data source failed, trying next", "source", src, "error", err)
+		if firstErr == nil && !errors.Is(err, errNotCached) {
+			firstErr = err
+		}
+	}
+
+	if firstErr != nil {
+		return nil, fetchMeta{}, firstErr
+	}
+	if tried > 0 {
+		return nil, fetchMeta{}, errNotCached
+	}
+	return nil, fetchMeta{}, newError(KindConfig, nil, "no usable data source in %s; configure a tempestd server or add cloud to sources", formatSources(order))
+}
+
+// liveFetchers returns the network fetchers that are configured and supported.
+func liveFetchers[T any](order []dataSource, serverURL string, f sourceFetchers[T]) map[dataSource]func(context.Context) (*T, error) {
+	live := make(map[dataSource]func(context.Context) (*T, error))
+	for _, src := range order {
+		switch {
+		case src == sourceUDP && f.UDP != nil:
+			live[src] = f.UDP
+		case src == sourceTempestd && f.Tempestd != nil && serverURL != "":
+			live[src] = func(ctx context.Context) (*T, error) { return f.Tempestd(ctx, serverURL) }
+		case src == sourceCloud && f.Cloud != nil:
+			live[src] = f.Cloud
+		}
+	}
+	return live
+}
+
+// fetchFromCache replays the live fetchers in source order against the cache
+// only, accepting entries of any age.
+func fetchFromCache[T any](ctx context.Context, order []dataSource, live map[dataSource]func(context.Context) (*T, error)) (*T, error) {
+	ctx = context.WithValue(ctx, cacheOnlyKey{}, true)
+	for _, src := range order {
+		fetch := live[src]
+		if fetch == nil {
+			continue
+		}
+		if v, err := fetch(ctx); err == nil {
+			return v, nil
+		}
+	}
+	return nil, errNotCached
+}
+
+func formatSources(order []dataSource) string {
+	names := make([]string, len(order))
+	for i, src := range order {
+		names[i] = string(src)
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+type cacheOnlyKey struct{}
+
+// cacheOnly reports whether fetches on ctx must be served from the cache.
+func cacheOnly(ctx context.Context) bool {
+	v, _ := ctx.Value(cacheOnlyKey{}).(bool)
+	return v
+}
+
+// fetchInfo collects the fetch time of every response a command combines,
+// e.g. an observation and its station metadata. The oldest one is reported.
+type fetchInfo struct {
+	mu     sync.Mutex
+	oldest time.Time
+}
+
+type fetchInfoKey struct{}
+
+func withFetchInfo(ctx context.Context) (context.Context, *fetchInfo) {
+	info := &fetchInfo{}
+	return context.WithValue(ctx, fetchInfoKey{}, info), info
+}
+
+// withoutFetchInfo hides the fetch recorder from ctx. Station metadata is
+// fetched this way, since its age says nothing about how fresh the data is.
+func withoutFetchInfo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fetchInfoKey{}, (*fetchInfo)(nil))
+}
+
+// recordFetchedAt notes that a response fetched at t was used.
+func recordFetchedAt(ctx context.Context, t time.Time) {
+	info, ok := ctx.Value(fetchInfoKey{}).(*fetchInfo)
+	if !ok || info == nil {
+		return
+	}
+	info.mu.Lock()
+	defer info.mu.Unlock()
+	if info.oldest.IsZero() || t.Before(info.oldest) {
+		info.oldest = t
+	}
+}
+
+func (i *fetchInfo) at() time.Time {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.oldest.IsZero() {
+		return time.Now()
+	}
+	return i.oldest
+}
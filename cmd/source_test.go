@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestSourceOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    []dataSource
+		wantErr bool
+	}{
+		{name: "default", want: defaultSourceOrder},
+		{name: "list", value: []string{"udp", "tempestd", "cache", "cloud"}, want: []dataSource{sourceUDP, sourceTempestd, sourceCache, sourceCloud}},
+		{name: "comma separated env value", value: "cloud,Cache", want: []dataSource{sourceCloud, sourceCache}},
+		{name: "duplicates removed", value: []string{"cloud", "cloud"}, want: []dataSource{sourceCloud}},
+		{name: "unknown", value: []string{"carrier-pigeon"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			if tt.value != nil {
+				viper.Set("sources", tt.value)
+			}
+			got, err := sourceOrder()
+			if tt.wantErr {
+				if errorKind(err) != KindConfig {
+					t.Fatalf("error = %v, want config error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+type sourceTestData struct {
+	From string `json:"from"`
+}
+
+func TestFetchWithFallback(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	unavailable := statusError(http.StatusServiceUnavailable, errors.New("down"))
+	var calls []dataSource
+	fetchers := sourceFetchers[sourceTestData]{
+		Tempestd: func(ctx context.Context, serverURL string) (*sourceTestData, error) {
+			calls = append(calls, sourceTempestd)
+			return nil, unavailable
+		},
+		Cloud: func(ctx context.Context) (*sourceTestData, error) {
+			calls = append(calls, sourceCloud)
+			return &sourceTestData{From: "cloud"}, nil
+		},
+	}
+
+	got, meta, err := fetchWithFallback(context.Background(), "http://tempestd.invalid", fetchers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.From != "cloud" || meta.Source != sourceCloud || meta.Stale {
+		t.Errorf("got %+v, %+v; want fresh cloud data", got, meta)
+	}
+	if len(calls) != 2 || calls[0] != sourceTempestd {
+		t.Errorf("calls = %v, want tempestd then cloud", calls)
+	}
+
+	// Without a server, tempestd is skipped entirely.
+	calls = nil
+	if _, _, err := fetchWithFallback(context.Background(), "", fetchers); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 1 || calls[0] != sourceCloud {
+		t.Errorf("calls = %v, want only cloud", calls)
+	}
+
+	// When every source fails, the first source's error is returned.
+	viper.Set("sources", []string{"tempestd"})
+	_, _, err = fetchWithFallback(context.Background(), "http://tempestd.invalid", fetchers)
+	if errorKind(err) != KindNetwork {
+		t.Errorf("error = %v, want the tempestd network error", err)
+	}
+
+	// A source the command doesn't support leaves nothing to try.
+	viper.Set("sources", []string{"udp"})
+	_, _, err = fetchWithFallback(context.Background(), "", fetchers)
+	if errorKind(err) != KindConfig {
+		t.Errorf("error = %v, want config error", err)
+	}
+}
+
+func TestFetchWithFallbackSkippedSource(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("sources", []string{"udp", "cloud"})
+
+	fetchers := sourceFetchers[sourceTestData]{
+		UDP: func(ctx context.Context) (*sourceTestData, error) {
+			return nil, fmt.Errorf("%w: serial unknown", errSourceSkipped)
+		},
+		Cloud: func(ctx context.Context) (*sourceTestData, error) {
+			return nil, statusError(http.StatusUnauthorized, errors.New("unauthorized"))
+		},
+	}
+	// The skipped source does not hide the real failure.
+	if _, _, err := fetchWithFallback(context.Background(), "", fetchers); errorKind(err) != KindAuth {
+		t.Errorf("error = %v, want the cloud auth error", err)
+	}
+	viper.Set("sources", []string{"udp"})
+	if _, _, err := fetchWithFallback(context.Background(), "", fetchers); errorKind(err) != KindConfig {
+		t.Errorf("error = %v, want no usable data source", err)
+	}
+}
+
+func TestFetchWithFallbackStaleCache(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("cache.dir", t.TempDir())
+	viper.Set("sources", []string{"tempestd", "cache"})
+
+	up := true
+	fetchers := sourceFetchers[sourceTestData]{
+		Tempestd: func(ctx context.Context, serverURL string) (*sourceTestData, error) {
+			return cachedFetch(ctx, cacheKey("test", serverURL, 1), time.Millisecond, func() (*sourceTestData, error) {
+				if !up {
+					return nil, statusError(http.StatusBadGateway, errors.New("down"))
+				}
+				return &sourceTestData{From: "tempestd"}, nil
+			})
+		},
+	}
+
+	_, first, err := fetchWithFallback(context.Background(), "http://tempestd.invalid", fetchers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Source != sourceTempestd || first.Stale {
+		t.Fatalf("first fetch meta = %+v, want fresh tempestd", first)
+	}
+
+	up = false
+	time.Sleep(5 * time.Millisecond)
+	got, meta, err := fetchWithFallback(context.Background(), "http://tempestd.invalid", fetchers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.From != "tempestd" || meta.Source != sourceCache || !meta.Stale {
+		t.Errorf("got %+v, %+v; want stale cached tempestd data", got, meta)
+	}
+	if meta.FetchedAt.After(first.FetchedAt.Add(time.Second)) {
+		t.Errorf("FetchedAt = %v, want the original fetch time %v", meta.FetchedAt, first.FetchedAt)
+	}
+}
+
+func TestFetchInfoIgnoresStationMetadata(t *testing.T) {
+	ctx, info := withFetchInfo(context.Background())
+	old := time.Now().Add(-time.Hour)
+	recordFetchedAt(withoutFetchInfo(ctx), old)
+	recordFetchedAt(ctx, old.Add(30*time.Minute))
+
+	if got := info.at(); !got.Equal(old.Add(30 * time.Minute)) {
+		t.Errorf("at() = %v, want the observation's fetch time", got)
+	}
+}
+
+func TestStaleResult(t *testing.T) {
+	live := fetchMeta{Source: sourceCloud, FetchedAt: time.Now()}
+	if err := staleResult(live, nil); err != nil {
+		t.Errorf("live data: error = %v, want nil", err)
+	}
+
+	stale := fetchMeta{Source: sourceCache, FetchedAt: time.Now().Add(-time.Hour), Stale: true}
+	err := staleResult(stale, nil)
+	var e *Error
+	if !errors.As(err, &e) || !e.Quiet || ExitCode(err) != ExitStale {
+		t.Errorf("stale data: error = %#v, want a quiet stale_data error", err)
+	}
+
+	writeErr := errors.New("broken pipe")
+	if err := staleResult(stale, writeErr); err != writeErr {
+		t.Errorf("error = %v, want the write error", err)
+	}
+}
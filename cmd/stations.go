@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/chadmayfield/tempest-cli/internal/config"
@@ -21,9 +23,19 @@ var stationsCmd = &cobra.Command{
 }
 
 func init() {
+	stationsCmd.Flags().Duration("timeout", defaultStationTimeout, "time limit for checking each station")
+	stationsCmd.Flags().Bool("health", false, "show battery, power and activity details; exit non-zero if any station is degraded")
+	addFormatFlag(stationsCmd)
 	rootCmd.AddCommand(stationsCmd)
 }
 
+const (
+	// stationWorkers bounds how many stations are checked at once.
+	stationWorkers = 4
+	// defaultStationTimeout bounds all requests made for a single station.
+	defaultStationTimeout = 15 * time.Second
+)
+
 func runStations(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
@@ -33,11 +45,23 @@ func runStations(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(cfg.Stations) == 0 {
-		return fmt.Errorf("no stations configured; run 'tempest config init' to set up")
+		return newError(KindConfig, nil, "no stations configured; run 'tempest config init' to set up")
+	}
+
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout <= 0 {
+		return usageError(fmt.Errorf("invalid --timeout %s: must be positive", timeout))
+	}
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	health, _ := cmd.Flags().GetBool("health")
+	if health && format != display.FormatText {
+		return usageError(fmt.Errorf("--format %s cannot be used with --health", format))
 	}
 
 	serverURL := resolveServerURL(cfg)
-	var rows []display.StationRow
 
 	// When using tempestd, try the list endpoint first
 	var serverStations map[int]*tempest.Station
@@ -45,51 +69,35 @@ func runStations(cmd *cobra.Command, args []string) error {
 		serverStations = fetchStationListFromServer(ctx, serverURL)
 	}
 
-	for name, sc := range cfg.Stations {
-		row := display.StationRow{
-			ConfigName: name,
-			StationID:  sc.StationID,
-			DeviceID:   sc.DeviceID,
-			IsDefault:  name == cfg.DefaultStation,
-		}
-
-		var station *tempest.Station
-		var obs *tempest.StationObservation
-
-		if serverURL != "" {
-			// Use cached list data if available, otherwise fall back to per-station query
-			if s, ok := serverStations[sc.StationID]; ok {
-				station = s
-			} else {
-				station, _, err = fetchStationStatusFromServer(ctx, serverURL, sc.StationID)
-			}
-			if station != nil {
-				obs, _ = fetchCurrentObsFromServer(ctx, serverURL, sc.StationID)
-			}
-		} else {
-			station, obs, err = fetchStationStatus(ctx, &sc)
-		}
+	if health {
+		return runStationHealth(cmd, cfg, serverURL, serverStations, timeout)
+	}
 
-		if err != nil || station == nil {
-			row.StationName = sc.Name
-			row.Online = false
-		} else {
-			row.StationName = station.Name
-			if obs != nil {
-				row.LastObserved = obs.Timestamp
-				row.Online = time.Since(obs.Timestamp) < 30*time.Minute
-			}
-		}
+	names := cfg.StationNames()
+	rows := make([]display.StationRow, len(names))
+	forEachStation(ctx, names, timeout, func(ctx context.Context, i int, name string) {
+		sc := cfg.Stations[name]
+		rows[i], _ = checkStation(ctx, serverURL, serverStations, &sc, cfg.StaleThreshold(&sc))
+		rows[i].ConfigName = name
+		rows[i].IsDefault = name == cfg.DefaultStation
+	})
 
-		rows = append(rows, row)
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	if viper.GetBool("json") {
 		return jsonout.Write(cmd.OutOrStdout(), stationsJSON(rows))
 	}
 
-	noColor := viper.GetBool("no-color")
-	theme := display.NewTheme(noColor, display.WithNoEmoji(viper.GetBool("no-emoji")))
+	theme, err := newTheme()
+	if err != nil {
+		return err
+	}
+
+	if format != display.FormatText {
+		return writeDocument(cmd.OutOrStdout(), theme, format, display.StationsDocument(theme, rows), nil)
+	}
 
 	output := display.RenderStations(theme, rows)
 	_, _ = fmt.Fprintln(cmd.OutOrStdout(), output)
@@ -97,12 +105,123 @@ func runStations(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// forEachStation calls fn for each station name on a bounded pool of workers,
+// giving each call its own timeout. It returns once all calls have finished.
+func forEachStation(ctx context.Context, names []string, timeout time.Duration, fn func(ctx context.Context, i int, name string)) {
+	sem := make(chan struct{}, stationWorkers)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			sctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			fn(sctx, i, name)
+		}()
+	}
+	wg.Wait()
+}
+
+// checkStation fetches the metadata and latest observation for one station and
+// summarises them as a table row. Failures are reported in the row's Reason
+// rather than returned, so one bad station doesn't hide the others. A station
+// is online if its latest observation is younger than staleAfter.
+func checkStation(ctx context.Context, serverURL string, serverStations map[int]*tempest.Station, sc *config.StationConfig, staleAfter time.Duration) (display.StationRow, *tempest.StationObservation) {
+	row := display.StationRow{
+		StationName: sc.Name,
+		StationID:   sc.StationID,
+		DeviceID:    sc.DeviceID,
+	}
+
+	data, meta, err := fetchWithFallback(ctx, serverURL, sourceFetchers[currentData]{
+		Tempestd: func(ctx context.Context, serverURL string) (*currentData, error) {
+			// Use cached list data if available, otherwise fall back to per-station query
+			if s, ok := serverStations[sc.StationID]; ok {
+				obs, err := fetchCurrentObsFromServer(ctx, serverURL, sc.StationID)
+				return stationStatus(s, obs, err)
+			}
+			return stationStatus(fetchStationStatusFromServer(ctx, serverURL, sc.StationID))
+		},
+		Cloud: func(ctx context.Context) (*currentData, error) {
+			return stationStatus(fetchStationStatus(ctx, sc))
+		},
+	})
+	if err != nil {
+		slog.Debug("station check failed", "station_id", sc.StationID, "error", err)
+		row.Reason = stationReason(err)
+		return row, nil
+	}
+
+	row.Source = string(meta.Source)
+	row.FetchedAt = meta.FetchedAt
+	row.Stale = meta.Stale
+	if data.Station != nil && data.Station.Name != "" {
+		row.StationName = data.Station.Name
+	}
+	obs := data.Observation
+	if obs == nil || obs.Timestamp.IsZero() {
+		row.Reason = "no observations"
+		return row, obs
+	}
+
+	row.LastObserved = obs.Timestamp
+	row.Online = time.Since(obs.Timestamp) < staleAfter
+	if !row.Online {
+		row.Outdated = true
+		row.Reason = "no recent observations"
+	}
+	return row, obs
+}
+
+// stationStatus combines the results of a station status fetch. A station
+// that exists but has no observations is a valid result rather than an error,
+// so it is not retried against the next data source.
+func stationStatus(station *tempest.Station, obs *tempest.StationObservation, err error) (*currentData, error) {
+	if err != nil {
+		if station == nil || errorKind(wrapAPIError(err)) != KindNotFound {
+			return nil, err
+		}
+		obs = nil
+	}
+	return &currentData{Observation: obs, Station: station}, nil
+}
+
+// stationReason gives a short explanation of why a station check failed.
+func stationReason(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	switch errorKind(wrapAPIError(err)) {
+	case KindAuth:
+		return "auth failed"
+	case KindNotFound:
+		return "not found"
+	case KindRateLimit:
+		return "rate limited"
+	case KindTimeout:
+		return "timeout"
+	case KindNetwork:
+		return "unreachable"
+	case KindConfig:
+		return "invalid config"
+	default:
+		return "error"
+	}
+}
+
 type stationJSONOutput struct {
 	Name            string  `json:"name"`
 	StationID       int     `json:"station_id"`
 	DeviceID        int     `json:"device_id"`
 	Status          string  `json:"status"`
 	LastObservation *string `json:"last_observation"`
+	Reason          string  `json:"reason,omitempty"`
+	Source          string  `json:"source,omitempty"`
+	FetchedAt       *string `json:"fetched_at"`
+	Stale           bool    `json:"stale"`
 }
 
 func stationsJSON(rows []display.StationRow) []stationJSONOutput {
@@ -123,34 +242,38 @@ func stationsJSON(rows []display.StationRow) []stationJSONOutput {
 			DeviceID:        r.DeviceID,
 			Status:          status,
 			LastObservation: lastObs,
+			Reason:          r.Reason,
+			Source:          r.Source,
+			FetchedAt:       formatOptionalTime(r.FetchedAt),
+			Stale:           r.Stale,
 		}
 	}
 	return out
 }
 
 func fetchStationStatus(ctx context.Context, sc *config.StationConfig) (*tempest.Station, *tempest.StationObservation, error) {
-	client, err := tempest.NewClient(sc.Token)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	station, err := client.GetStation(ctx, sc.StationID)
+	client := lazyStationClient(ctx, sc)
+	station, err := fetchStationFromAPI(ctx, sc, client)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	obs, err := client.GetStationObservation(ctx, sc.StationID)
-	if err != nil {
-		return station, nil, nil // station found but no observation
-	}
-
-	return station, obs, nil
+	obs, err := cachedFetch(ctx, cloudCacheKey("current", sc), currentTTL(), func() (*tempest.StationObservation, error) {
+		c, err := client()
+		if err != nil {
+			return nil, err
+		}
+		return c.GetStationObservation(ctx, sc.StationID)
+	})
+	return station, obs, err
 }
 
 // fetchStationListFromServer tries the /api/v1/stations list endpoint and returns
 // a map of station ID → Station. Returns an empty map if the endpoint is unavailable.
 func fetchStationListFromServer(ctx context.Context, serverURL string) map[int]*tempest.Station {
-	result, err := fetchFromTempestd[[]tempest.Station](ctx, serverURL, "/api/v1/stations")
+	result, err := cachedFetch(ctx, cacheKey("stations", serverURL, 0), stationTTL(), func() (*[]tempest.Station, error) {
+		return fetchFromTempestd[[]tempest.Station](ctx, serverURL, "/api/v1/stations")
+	})
 	if err != nil {
 		slog.Debug("tempestd stations list endpoint unavailable, falling back to per-station queries", "error", err)
 		return nil
@@ -165,19 +288,17 @@ func fetchStationListFromServer(ctx context.Context, serverURL string) map[int]*
 
 // fetchCurrentObsFromServer fetches the current observation for a single station from tempestd.
 func fetchCurrentObsFromServer(ctx context.Context, serverURL string, stationID int) (*tempest.StationObservation, error) {
-	return fetchFromTempestd[tempest.StationObservation](ctx, serverURL, fmt.Sprintf("/api/v1/stations/%d/current", stationID))
+	return cachedFetch(ctx, cacheKey("current", serverURL, stationID), currentTTL(), func() (*tempest.StationObservation, error) {
+		return fetchFromTempestd[tempest.StationObservation](ctx, serverURL, fmt.Sprintf("/api/v1/stations/%d/current", stationID))
+	})
 }
 
 func fetchStationStatusFromServer(ctx context.Context, serverURL string, stationID int) (*tempest.Station, *tempest.StationObservation, error) {
-	station, err := fetchFromTempestd[tempest.Station](ctx, serverURL, fmt.Sprintf("/api/v1/stations/%d", stationID))
+	station, err := fetchStationFromServer(ctx, serverURL, stationID)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	obs, err := fetchFromTempestd[tempest.StationObservation](ctx, serverURL, fmt.Sprintf("/api/v1/stations/%d/current", stationID))
-	if err != nil {
-		return station, nil, nil
-	}
-
-	return station, obs, nil
+	obs, err := fetchCurrentObsFromServer(ctx, serverURL, stationID)
+	return station, obs, err
 }
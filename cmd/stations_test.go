@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestRunStationsConcurrentWithReasons(t *testing.T) {
+	obs := func(w http.ResponseWriter, id int, age time.Duration) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"StationID": id, "Timestamp": time.Now().Add(-age)})
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/stations/1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"StationID": 1, "Name": "Alpha"})
+		case "/api/v1/stations/1/current":
+			obs(w, 1, time.Minute)
+		case "/api/v1/stations/2":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/api/v1/stations/3":
+			_ = json.NewEncoder(w).Encode(map[string]any{"StationID": 3, "Name": "Charlie"})
+		case "/api/v1/stations/4":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+		case "/api/v1/stations/5":
+			_ = json.NewEncoder(w).Encode(map[string]any{"StationID": 5, "Name": "Echo"})
+		case "/api/v1/stations/5/current":
+			obs(w, 5, 2*time.Hour)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigType("yaml")
+	_ = viper.ReadConfig(strings.NewReader(`
+default_station: alpha
+stations:
+  echo:    {token: t, station_id: 5}
+  delta:   {token: t, station_id: 4}
+  charlie: {token: t, station_id: 3}
+  bravo:   {token: t, station_id: 2}
+  alpha:   {token: t, station_id: 1}
+`))
+	viper.Set("server", srv.URL)
+	// Keep tests off the real cloud API.
+	viper.Set("sources", []string{"tempestd"})
+	viper.Set("json", true)
+	viper.Set("no-cache", true)
+	viper.Set("tempestd.retries", 0)
+
+	_ = stationsCmd.Flags().Set("timeout", "300ms")
+	defer func() { _ = stationsCmd.Flags().Set("timeout", defaultStationTimeout.String()) }()
+	var out bytes.Buffer
+	stationsCmd.SetOut(&out)
+	stationsCmd.SetContext(context.Background())
+
+	start := time.Now()
+	if err := runStations(stationsCmd, nil); err != nil {
+		t.Fatalf("runStations() error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+		t.Errorf("runStations took %v; slow station should be cut off by --timeout", elapsed)
+	}
+
+	var got []stationJSONOutput
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	want := []struct {
+		id     int
+		status string
+		reason string
+	}{
+		{1, "online", ""},
+		{2, "offline", "auth failed"},
+		{3, "offline", "no observations"},
+		{4, "offline", "timeout"},
+		{5, "offline", "no recent observations"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].StationID != w.id || got[i].Status != w.status || got[i].Reason != w.reason {
+			t.Errorf("row %d = {%d %q %q}, want {%d %q %q}", i, got[i].StationID, got[i].Status, got[i].Reason, w.id, w.status, w.reason)
+		}
+	}
+}
+
+func TestStationReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"auth", statusError(http.StatusForbidden, errors.New("x")), "auth failed"},
+		{"not found", statusError(http.StatusNotFound, errors.New("x")), "not found"},
+		{"rate limit", statusError(http.StatusTooManyRequests, errors.New("x")), "rate limited"},
+		{"deadline", context.DeadlineExceeded, "timeout"},
+		{"unavailable", statusError(http.StatusBadGateway, errors.New("x")), "unreachable"},
+		{"other", errors.New("boom"), "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stationReason(tt.err); got != tt.want {
+				t.Errorf("stationReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,37 +3,236 @@ package cmd
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"math"
+	"math/rand/v2"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
+	"sync"
 	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/spf13/viper"
 )
 
 const maxResponseBody = 10 * 1024 * 1024 // 10 MB
 
-var tempestdClient = &http.Client{
-	Timeout: 60 * time.Second,
-	Transport: &http.Transport{
-		DialContext: (&net.Dialer{
-			Timeout: 30 * time.Second,
-		}).DialContext,
-		TLSClientConfig: &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		},
-	},
+// Defaults for tempestd requests, overridable under the tempestd: config block.
+const (
+	defaultTempestdTimeout = 60 * time.Second
+	defaultTempestdRetries = 2
+	defaultTempestdBackoff = 500 * time.Millisecond
+
+	// maxTempestdBackoff caps exponential backoff between attempts.
+	maxTempestdBackoff = 10 * time.Second
+	// maxRetryAfter caps how long a Retry-After header can make us wait.
+	maxRetryAfter = 60 * time.Second
+)
+
+// unixSocketHost is the placeholder host used in request URLs when tempestd
+// is reached over a unix socket.
+const unixSocketHost = "tempestd.sock"
+
+// tempestdTransportKey identifies the settings an http.Client was built with,
+// so that clients (and their connection pools) are reused across requests.
+type tempestdTransportKey struct {
+	caFile, certFile, keyFile string
+	insecure                  bool
+	socket                    string
+}
+
+var (
+	tempestdClientsMu sync.Mutex
+	tempestdClients   = map[tempestdTransportKey]*http.Client{}
+)
+
+// tempestdSettings reads the auth and TLS options from the tempestd: config
+// block. Each key is read individually so TEMPEST_TEMPESTD_* env vars apply.
+func tempestdSettings() config.TempestdConfig {
+	return config.TempestdConfig{
+		Token:              viper.GetString("tempestd.token"),
+		Username:           viper.GetString("tempestd.username"),
+		Password:           viper.GetString("tempestd.password"),
+		CAFile:             viper.GetString("tempestd.ca_file"),
+		CertFile:           viper.GetString("tempestd.cert_file"),
+		KeyFile:            viper.GetString("tempestd.key_file"),
+		InsecureSkipVerify: viper.GetBool("tempestd.insecure_skip_verify"),
+	}
 }
 
-// validateServerURL checks that a server URL is a valid HTTP/HTTPS URL.
+// tempestdClient returns an HTTP client for the given TLS settings and optional
+// unix socket path. Clients have no overall timeout; each attempt is bounded
+// by tempestd.timeout via its request context instead.
+func tempestdClient(tc config.TempestdConfig, socket string) (*http.Client, error) {
+	key := tempestdTransportKey{
+		caFile:   tc.CAFile,
+		certFile: tc.CertFile,
+		keyFile:  tc.KeyFile,
+		insecure: tc.InsecureSkipVerify,
+		socket:   socket,
+	}
+
+	tempestdClientsMu.Lock()
+	defer tempestdClientsMu.Unlock()
+	if c, ok := tempestdClients[key]; ok {
+		return c, nil
+	}
+
+	tlsConfig, err := tempestdTLSConfig(tc)
+	if err != nil {
+		return nil, &Error{Kind: KindConfig, Err: err}
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		DialContext:     dialer.DialContext,
+		TLSClientConfig: tlsConfig,
+	}
+	if socket != "" {
+		transport.Proxy = nil
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+	}
+
+	c := &http.Client{Transport: transport}
+	tempestdClients[key] = c
+	return c, nil
+}
+
+// tempestdTLSConfig builds the TLS configuration for tempestd connections.
+func tempestdTLSConfig(tc config.TempestdConfig) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if tc.CAFile != "" {
+		pem, err := os.ReadFile(tc.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading tempestd ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tempestd ca_file %s contains no PEM certificates", tc.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if tc.CertFile != "" || tc.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(tc.CertFile, tc.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading tempestd client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if tc.InsecureSkipVerify {
+		slog.Warn("TLS certificate verification for tempestd is disabled (tempestd.insecure_skip_verify)")
+		cfg.InsecureSkipVerify = true //nolint:gosec // explicit opt-in for lab setups
+	}
+
+	return cfg, nil
+}
+
+// tempestdBaseURL converts a configured server URL into the base URL used for
+// requests. For unix:// URLs it also returns the socket path.
+func tempestdBaseURL(serverURL string) (*url.URL, string, error) {
+	base, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid server URL: %w", err)
+	}
+	if base.Scheme == "unix" {
+		return &url.URL{Scheme: "http", Host: unixSocketHost}, base.Path, nil
+	}
+	return base, "", nil
+}
+
+// setTempestdAuth adds bearer or basic credentials to req.
+func setTempestdAuth(req *http.Request, tc config.TempestdConfig) {
+	switch {
+	case tc.Token != "":
+		req.Header.Set("Authorization", "Bearer "+tc.Token)
+	case tc.Username != "":
+		req.SetBasicAuth(tc.Username, tc.Password)
+	}
+}
+
+// tempestdStatusError is returned when tempestd answers with a non-200 status.
+type tempestdStatusError struct {
+	StatusCode int
+	Path       string
+	RetryAfter time.Duration
+}
+
+func (e *tempestdStatusError) Error() string {
+	return fmt.Sprintf("tempestd returned status %d for %s", e.StatusCode, e.Path)
+}
+
+// tempestdRetryPolicy controls how fetchFromTempestd retries failed requests.
+type tempestdRetryPolicy struct {
+	Timeout time.Duration // per attempt
+	Retries int           // additional attempts after the first
+	Backoff time.Duration // initial backoff, doubled on each retry
+}
+
+// tempestdPolicy reads the retry policy from the tempestd: config block.
+func tempestdPolicy() tempestdRetryPolicy {
+	p := tempestdRetryPolicy{
+		Timeout: defaultTempestdTimeout,
+		Retries: defaultTempestdRetries,
+		Backoff: defaultTempestdBackoff,
+	}
+	if viper.IsSet("tempestd.timeout") {
+		if d := viper.GetDuration("tempestd.timeout"); d > 0 {
+			p.Timeout = d
+		}
+	}
+	if viper.IsSet("tempestd.retries") {
+		p.Retries = max(viper.GetInt("tempestd.retries"), 0)
+	}
+	if viper.IsSet("tempestd.backoff") {
+		if d := viper.GetDuration("tempestd.backoff"); d > 0 {
+			p.Backoff = d
+		}
+	}
+	return p
+}
+
+// backoff returns the wait before retry number attempt (0-based): exponential
+// growth from p.Backoff with ±25% jitter, capped at maxTempestdBackoff.
+func (p tempestdRetryPolicy) backoff(attempt int) time.Duration {
+	base := float64(p.Backoff) * math.Pow(2, float64(attempt))
+	jitter := base * 0.25 * (rand.Float64()*2 - 1)
+	return min(time.Duration(base+jitter), maxTempestdBackoff)
+}
+
+// validateServerURL checks that a server URL is a valid HTTP/HTTPS URL or a
+// unix:///path/to/socket URL.
 func validateServerURL(serverURL string) error {
 	u, err := url.Parse(serverURL)
 	if err != nil {
 		return fmt.Errorf("invalid server URL %q: %w", serverURL, err)
 	}
+	if u.Scheme == "unix" {
+		if u.Path == "" {
+			return fmt.Errorf("invalid server URL %q: missing socket path", serverURL)
+		}
+		return nil
+	}
 	if u.Scheme != "http" && u.Scheme != "https" {
-		return fmt.Errorf("invalid server URL %q: scheme must be http or https", serverURL)
+		return fmt.Errorf("invalid server URL %q: scheme must be http, https, or unix", serverURL)
 	}
 	if u.Host == "" {
 		return fmt.Errorf("invalid server URL %q: missing host", serverURL)
@@ -42,31 +241,84 @@ func validateServerURL(serverURL string) error {
 }
 
 // fetchFromTempestd makes an HTTP GET to a tempestd endpoint and decodes the JSON response.
+// Network failures, timeouts, 429 and 5xx responses are retried according to the
+// tempestd: config block; a Retry-After header on 429/503 overrides the backoff.
 func fetchFromTempestd[T any](ctx context.Context, serverURL, path string) (*T, error) {
 	// Parse and validate the base URL, then resolve the path safely.
-	base, err := url.Parse(serverURL)
+	base, socket, err := tempestdBaseURL(serverURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid server URL: %w", err)
+		return nil, err
 	}
 	ref, err := url.Parse(path)
 	if err != nil {
 		return nil, fmt.Errorf("invalid path: %w", err)
 	}
-	resolved := base.ResolveReference(ref)
+	resolved := base.ResolveReference(ref).String()
+
+	tc := tempestdSettings()
+	if err := tc.Validate(); err != nil {
+		return nil, &Error{Kind: KindConfig, Err: err}
+	}
+	client, err := tempestdClient(tc, socket)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := tempestdPolicy()
+	attempts := policy.Retries + 1
+	for attempt := 0; ; attempt++ {
+		verbosef("tempestd: GET %s (attempt %d/%d)", resolved, attempt+1, attempts)
+		start := time.Now()
+		result, err := fetchTempestdOnce[T](ctx, client, tc, resolved, serverURL, path, policy.Timeout)
+		if err == nil {
+			verbosef("tempestd: 200 OK in %s", time.Since(start).Round(time.Millisecond))
+			return result, nil
+		}
+		if attempt+1 >= attempts || !isRetryableTempestdError(ctx, err) {
+			if attempt > 0 {
+				verbosef("tempestd: giving up after %d attempts: %v", attempt+1, err)
+			}
+			return nil, err
+		}
+
+		wait := policy.backoff(attempt)
+		var se *tempestdStatusError
+		if errors.As(err, &se) && se.RetryAfter > 0 {
+			wait = min(se.RetryAfter, maxRetryAfter)
+		}
+		verbosef("tempestd: %v; retrying in %s", err, wait.Round(time.Millisecond))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resolved.String(), nil)
+// fetchTempestdOnce performs a single attempt bounded by timeout.
+func fetchTempestdOnce[T any](ctx context.Context, client *http.Client, tc config.TempestdConfig, resolved, serverURL, path string, timeout time.Duration) (*T, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resolved, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
+	setTempestdAuth(req, tc)
 
-	resp, err := tempestdClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("contacting tempestd at %s: %w", serverURL, err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("tempestd returned status %d for %s", resp.StatusCode, path)
+		return nil, &tempestdStatusError{
+			StatusCode: resp.StatusCode,
+			Path:       path,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
 	limited := io.LimitReader(resp.Body, maxResponseBody)
@@ -77,3 +329,41 @@ func fetchFromTempestd[T any](ctx context.Context, serverURL, path string) (*T,
 
 	return &result, nil
 }
+
+// isRetryableTempestdError reports whether a failed attempt is worth retrying.
+// Errors caused by the caller's context being cancelled are never retried.
+func isRetryableTempestdError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var se *tempestdStatusError
+	if errors.As(err, &se) {
+		return se.StatusCode == http.StatusTooManyRequests || se.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// parseRetryAfter parses a Retry-After header given either as delay seconds or
+// as an HTTP date. It returns zero if the header is absent or invalid.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
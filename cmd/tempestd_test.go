@@ -1,11 +1,23 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/spf13/viper"
 )
 
 func TestFetchFromTempestd_Success(t *testing.T) {
@@ -77,3 +89,335 @@ func TestFetchFromTempestd_CancelledContext(t *testing.T) {
 		t.Fatal("expected error for cancelled context")
 	}
 }
+
+func setupRetryTest(t *testing.T, retries int) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("tempestd.retries", retries)
+	viper.Set("tempestd.backoff", "1ms")
+}
+
+func TestFetchFromTempestd_RetriesTransientErrors(t *testing.T) {
+	setupRetryTest(t, 3)
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]int{"value": 7})
+	}))
+	defer srv.Close()
+
+	type payload struct{ Value int }
+	result, err := fetchFromTempestd[payload](context.Background(), srv.URL, "/api/v1/test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Value != 7 {
+		t.Errorf("Value = %d, want 7", result.Value)
+	}
+	if n := hits.Load(); n != 3 {
+		t.Errorf("attempts = %d, want 3", n)
+	}
+}
+
+func TestFetchFromTempestd_GivesUpAfterRetries(t *testing.T) {
+	setupRetryTest(t, 2)
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	type dummy struct{}
+	_, err := fetchFromTempestd[dummy](context.Background(), srv.URL, "/api/v1/test")
+	var se *tempestdStatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 status error, got %v", err)
+	}
+	if n := hits.Load(); n != 3 {
+		t.Errorf("attempts = %d, want 3 (1 + 2 retries)", n)
+	}
+}
+
+func TestFetchFromTempestd_NoRetryOnClientError(t *testing.T) {
+	setupRetryTest(t, 3)
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	type dummy struct{}
+	if _, err := fetchFromTempestd[dummy](context.Background(), srv.URL, "/api/v1/test"); err == nil {
+		t.Fatal("expected error for 404")
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("attempts = %d, want 1", n)
+	}
+}
+
+func TestFetchFromTempestd_HonorsRetryAfter(t *testing.T) {
+	setupRetryTest(t, 1)
+	viper.Set("tempestd.backoff", "5s") // would time out the test if Retry-After were ignored
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]int{"value": 1})
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	type payload struct{ Value int }
+	if _, err := fetchFromTempestd[payload](context.Background(), srv.URL, "/api/v1/test"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond || elapsed > 4*time.Second {
+		t.Errorf("elapsed = %v, want ~1s from Retry-After", elapsed)
+	}
+}
+
+func TestFetchFromTempestd_PerAttemptTimeout(t *testing.T) {
+	setupRetryTest(t, 1)
+	viper.Set("tempestd.timeout", "50ms")
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]int{"value": 1})
+	}))
+	defer srv.Close()
+
+	type payload struct{ Value int }
+	if _, err := fetchFromTempestd[payload](context.Background(), srv.URL, "/api/v1/test"); err != nil {
+		t.Fatalf("expected the retry to succeed after a timed-out attempt, got %v", err)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("attempts = %d, want 2", n)
+	}
+}
+
+func TestFetchFromTempestd_VerboseReportsAttempts(t *testing.T) {
+	setupRetryTest(t, 1)
+	viper.Set("verbose", true)
+
+	var stderr bytes.Buffer
+	rootCmd.SetErr(&stderr)
+	defer rootCmd.SetErr(nil)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	type dummy struct{}
+	_, _ = fetchFromTempestd[dummy](context.Background(), srv.URL, "/api/v1/test")
+
+	out := stderr.String()
+	for _, want := range []string{"attempt 1/2", "attempt 2/2", "retrying in", "giving up after 2 attempts"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("verbose output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{"soon", 0},
+		{now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second},
+		{now.Add(-10 * time.Second).Format(http.TimeFormat), 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.in, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTempestdPolicyBackoff(t *testing.T) {
+	p := tempestdRetryPolicy{Backoff: time.Second}
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		got := p.backoff(attempt)
+		if got < want*3/4 || got > want*5/4 {
+			t.Errorf("backoff(%d) = %v, want %v ±25%%", attempt, got, want)
+		}
+	}
+	if got := p.backoff(20); got != maxTempestdBackoff {
+		t.Errorf("backoff(20) = %v, want cap %v", got, maxTempestdBackoff)
+	}
+}
+
+func TestFetchFromTempestd_Auth(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]string
+		wantUser string
+		wantPass string
+		wantAuth string
+	}{
+		{
+			name:     "bearer token",
+			settings: map[string]string{"tempestd.token": "s3cret"},
+			wantAuth: "Bearer s3cret",
+		},
+		{
+			name:     "basic auth",
+			settings: map[string]string{"tempestd.username": "admin", "tempestd.password": "hunter2"},
+			wantUser: "admin",
+			wantPass: "hunter2",
+		},
+		{
+			name:     "no auth",
+			settings: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			for k, v := range tt.settings {
+				viper.Set(k, v)
+			}
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.wantUser != "" {
+					user, pass, ok := r.BasicAuth()
+					if !ok || user != tt.wantUser || pass != tt.wantPass {
+						t.Errorf("basic auth = %q/%q (ok=%v), want %q/%q", user, pass, ok, tt.wantUser, tt.wantPass)
+					}
+				} else if got := r.Header.Get("Authorization"); got != tt.wantAuth {
+					t.Errorf("Authorization = %q, want %q", got, tt.wantAuth)
+				}
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			if _, err := fetchFromTempestd[map[string]any](context.Background(), srv.URL, "/"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFetchFromTempestd_InvalidAuthConfig(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("tempestd.token", "tok")
+	viper.Set("tempestd.username", "admin")
+
+	_, err := fetchFromTempestd[map[string]any](context.Background(), "http://127.0.0.1:1", "/")
+	if got := errorKind(err); got != KindConfig {
+		t.Fatalf("errorKind = %q, want %q (err: %v)", got, KindConfig, err)
+	}
+}
+
+func TestFetchFromTempestd_TLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	badCA := filepath.Join(t.TempDir(), "bad.pem")
+	if err := os.WriteFile(badCA, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		settings map[string]any
+		wantKind ErrorKind
+		wantErr  bool
+	}{
+		{name: "untrusted", settings: map[string]any{}, wantErr: true},
+		{name: "ca_file", settings: map[string]any{"tempestd.ca_file": caFile}},
+		{name: "insecure_skip_verify", settings: map[string]any{"tempestd.insecure_skip_verify": true}},
+		{name: "invalid ca_file", settings: map[string]any{"tempestd.ca_file": badCA}, wantErr: true, wantKind: KindConfig},
+		{name: "missing client cert", settings: map[string]any{"tempestd.cert_file": "/nonexistent.pem", "tempestd.key_file": "/nonexistent-key.pem"}, wantErr: true, wantKind: KindConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			viper.Set("tempestd.retries", 0)
+			for k, v := range tt.settings {
+				viper.Set(k, v)
+			}
+
+			_, err := fetchFromTempestd[map[string]any](context.Background(), srv.URL, "/")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantKind != "" && errorKind(err) != tt.wantKind {
+				t.Errorf("errorKind = %q, want %q (err: %v)", errorKind(err), tt.wantKind, err)
+			}
+		})
+	}
+}
+
+func TestFetchFromTempestd_UnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not tested on windows")
+	}
+	viper.Reset()
+	defer viper.Reset()
+
+	// Socket paths are length-limited, so avoid the long t.TempDir() path.
+	dir, err := os.MkdirTemp("", "tempestd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	sock := filepath.Join(dir, "d.sock")
+
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &httptest.Server{
+		Listener: ln,
+		Config: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/stations" {
+				t.Errorf("path = %q, want /api/v1/stations", r.URL.Path)
+			}
+			_, _ = w.Write([]byte(`{"via":"socket"}`))
+		})},
+	}
+	srv.Start()
+	defer srv.Close()
+
+	got, err := fetchFromTempestd[map[string]string](context.Background(), "unix://"+sock, "/api/v1/stations")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if (*got)["via"] != "socket" {
+		t.Errorf("got %v, want via=socket", *got)
+	}
+}
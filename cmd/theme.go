@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/chadmayfield/tempest-cli/internal/display"
+	"github.com/spf13/viper"
+)
+
+// newTheme builds the display theme from --theme or the theme config key,
+// honoring --no-color, --no-emoji and the locale. Named themes that are not built in are
+// looked up in a themes directory next to the config file.
+func newTheme() (*display.Theme, error) {
+	var themeDir string
+	if path, err := configPath(); err == nil {
+		themeDir = filepath.Join(filepath.Dir(path), "themes")
+	}
+	spec, err := display.LoadTheme(viper.GetString("theme"), themeDir)
+	if err != nil {
+		return nil, wrapConfigError(err)
+	}
+	loc, err := resolveLocale()
+	if err != nil {
+		return nil, err
+	}
+	return display.NewTheme(viper.GetBool("no-color"),
+		display.WithNoEmoji(viper.GetBool("no-emoji")),
+		display.WithSpec(spec),
+		display.WithLocale(loc)), nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestNewTheme(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "themes"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "themes", "anchorage.yaml"),
+		[]byte("thresholds:\n  temperature:\n    - {max: -10, color: '#00f'}\n    - {color: '#fff'}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	viper.SetConfigFile(filepath.Join(dir, "config.yaml"))
+
+	theme, err := newTheme()
+	if err != nil || theme.Name() != "default" {
+		t.Fatalf("default theme = %v, %v", theme, err)
+	}
+
+	viper.Set("theme", "anchorage")
+	if theme, err = newTheme(); err != nil || theme.Name() != "anchorage" {
+		t.Fatalf("theme from the themes directory = %v, %v", theme, err)
+	}
+
+	viper.Set("theme", "high-contrast")
+	if theme, err = newTheme(); err != nil || theme.Name() != "high-contrast" {
+		t.Fatalf("preset = %v, %v", theme, err)
+	}
+
+	viper.Set("theme", "neon")
+	if _, err := newTheme(); errorKind(err) != KindConfig {
+		t.Errorf("unknown theme: err = %v, want config error", err)
+	}
+}
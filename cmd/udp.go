@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/pressure"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/spf13/viper"
+)
+
+const (
+	// defaultUDPListen is the port Tempest hubs broadcast on.
+	defaultUDPListen = ":50222"
+	// defaultUDPTimeout allows for one full obs_st interval, which is a minute.
+	defaultUDPTimeout = 65 * time.Second
+)
+
+func udpListenAddr() string {
+	if addr := viper.GetString("udp.listen"); addr != "" {
+		return addr
+	}
+	return defaultUDPListen
+}
+
+func udpTimeout() time.Duration {
+	if !viper.IsSet("udp.timeout") {
+		return defaultUDPTimeout
+	}
+	return viper.GetDuration("udp.timeout")
+}
+
+// udpHeader holds the fields used to pick out the messages of interest.
+type udpHeader struct {
+	Type         string `json:"type"`
+	SerialNumber string `json:"serial_number"`
+}
+
+// fetchCurrentFromUDP waits for an obs_st broadcast from one of sc's sensors
+// on the local network. Results are cached like any other current observation
+// so that repeated commands don't each wait for the next broadcast. Station
+// pressure is reduced to sea level with the station's elevation; if that is
+// unknown, sea-level pressure is left empty.
+func fetchCurrentFromUDP(ctx context.Context, serverURL string, sc *config.StationConfig) (*currentData, error) {
+	obs, err := cachedFetch(ctx, cacheKey("current", string(sourceUDP), sc.StationID), currentTTL(), func() (*tempest.StationObservation, error) {
+		serials, err := udpSerials(ctx, serverURL, sc)
+		if err != nil {
+			return nil, err
+		}
+		o, err := listenForObservation(ctx, udpListenAddr(), serials, udpTimeout())
+		if err != nil {
+			return nil, err
+		}
+		obs := stationObservationFromUDP(o, sc.StationID)
+		elevation, err := stationElevation(ctx, serverURL, sc)
+		if err != nil {
+			slog.Debug("not reducing UDP pressure to sea level", "error", err)
+			return obs, nil
+		}
+		obs.SeaLevelPressure = pressure.ToSeaLevel(o.StationPressure, o.AirTemperature, elevation)
+		return obs, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// UDP broadcasts carry no station metadata.
+	return &currentData{
+		Observation: obs,
+		Station:     &tempest.Station{StationID: sc.StationID, Name: sc.Name},
+	}, nil
+}
+
+// udpSerials returns the serial numbers of sc's sensors. Every station on the
+// network broadcasts to the same port, so without them UDP is skipped rather
+// than risk showing another station's data.
+func udpSerials(ctx context.Context, serverURL string, sc *config.StationConfig) ([]string, error) {
+	if sc.Serial != "" {
+		return []string{sc.Serial}, nil
+	}
+	station, err := fetchStationMetadata(ctx, serverURL, sc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: sensor serial for station %d unknown; set serial in the config: %v", errSourceSkipped, sc.StationID, err)
+	}
+	var serials []string
+	for _, d := range sensorDevices(station) {
+		if d.SerialNum != "" {
+			serials = append(serials, d.SerialNum)
+		}
+	}
+	if len(serials) == 0 {
+		return nil, fmt.Errorf("%w: station %d lists no sensor serial numbers; set serial in the config", errSourceSkipped, sc.StationID)
+	}
+	return serials, nil
+}
+
+// listenForObservation returns the latest observation from the first obs_st
+// message received on addr from a sensor with one of the given serial
+// numbers.
+func listenForObservation(ctx context.Context, addr string, serials []string, timeout time.Duration) (*tempest.Observation, error) {
+	var obs *tempest.Observation
+	err := listenUDP(ctx, addr, "observation", timeout, func(hdr udpHeader, msg []byte) bool {
+		if hdr.Type != "obs_st" || !hasSerial(serials, hdr.SerialNumber) {
+			return false
+		}
+		observations, err := tempest.ParseObsStMessage(msg)
+		if err != nil || len(observations) == 0 {
+			slog.Debug("ignoring malformed obs_st message", "error", err)
+			return false
+		}
+		obs = &observations[len(observations)-1]
+		return true
+	})
+	return obs, err
+}
+
+// deviceSignal is the signal strength reported in a sensor's device_status
+// broadcast, in dBm.
+type deviceSignal struct {
+	// SensorRSSI is how well the sensor hears the hub, and HubRSSI how well
+	// the hub hears the sensor.
+	SensorRSSI int `json:"rssi"`
+	HubRSSI    int `json:"hub_rssi"`
+}
+
+// listenForSignals collects the signal strength of the sensors with the
+// given serial numbers from their device_status broadcasts, which are sent
+// once a minute. It returns when every sensor has reported or timeout
+// passes, with whatever was received by then.
+func listenForSignals(ctx context.Context, addr string, serials []string, timeout time.Duration) (map[string]deviceSignal, error) {
+	signals := make(map[string]deviceSignal)
+	err := listenUDP(ctx, addr, "device status", timeout, func(hdr udpHeader, msg []byte) bool {
+		if hdr.Type != "device_status" || !hasSerial(serials, hdr.SerialNumber) {
+			return false
+		}
+		var sig deviceSignal
+		if err := json.Unmarshal(msg, &sig); err != nil {
+			slog.Debug("ignoring malformed device_status message", "error", err)
+			return false
+		}
+		signals[strings.ToUpper(hdr.SerialNumber)] = sig
+		return len(signals) == len(serials)
+	})
+	if errorKind(err) == KindTimeout {
+		err = nil
+	}
+	return signals, err
+}
+
+// listenUDP passes each UDP broadcast received on addr to handle until it
+// returns true. It fails with a timeout error, naming what it waited for, if
+// that takes longer than timeout.
+func listenUDP(ctx context.Context, addr, what string, timeout time.Duration, handle func(hdr udpHeader, msg []byte) bool) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lc net.ListenConfig
+	conn, err := lc.ListenPacket(ctx, "udp", addr)
+	if err != nil {
+		return newError(KindNetwork, err, "listening for UDP broadcasts on %s: %v", addr, err)
+	}
+	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	verbosef("listening for UDP broadcasts on %s (up to %s)", addr, timeout)
+	buf := make([]byte, 8192)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return newError(KindTimeout, ctx.Err(), "no UDP %s received on %s within %s", what, addr, timeout)
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("reading UDP broadcast: %w", err)
+		}
+
+		msg := buf[:n]
+		var hdr udpHeader
+		if err := json.Unmarshal(msg, &hdr); err != nil {
+			continue
+		}
+		if handle(hdr, msg) {
+			return nil
+		}
+	}
+}
+
+func hasSerial(serials []string, serial string) bool {
+	return slices.ContainsFunc(serials, func(s string) bool { return strings.EqualFold(s, serial) })
+}
+
+// stationObservationFromUDP converts a device observation into the station
+// observation shape used by the current view. UDP messages report station
+// pressure and per-interval rain and lightning only, so sea-level pressure and
+// the daily totals are left empty.
+func stationObservationFromUDP(o *tempest.Observation, stationID int) *tempest.StationObservation {
+	obs := &tempest.StationObservation{
+		StationID:          stationID,
+		Timestamp:          o.Timestamp,
+		AirTemperature:     o.AirTemperature,
+		RelativeHumidity:   o.RelativeHumidity,
+		WindAvg:            o.WindAvg,
+		WindGust:           o.WindGust,
+		WindLull:           o.WindLull,
+		WindDirection:      o.WindDirection,
+		BarometricPressure: o.StationPressure,
+		SolarRadiation:     o.SolarRadiation,
+		UV:                 o.UVIndex,
+		Brightness:         o.Illuminance,
+		FeelsLike:          tempest.FeelsLike(o.AirTemperature, o.RelativeHumidity, o.WindAvg),
+	}
+	// Dew point and wet bulb are undefined without a humidity reading.
+	if o.RelativeHumidity > 0 {
+		obs.DewPoint = tempest.DewPoint(o.AirTemperature, o.RelativeHumidity)
+		obs.WetBulbTemperature = tempest.WetBulb(o.AirTemperature, o.RelativeHumidity)
+	}
+	return obs
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/fakeserver"
+	"github.com/spf13/viper"
+)
+
+const testObsSt = `{"serial_number":"ST-00000512","type":"obs_st","hub_sn":"HB-00013030","obs":[[1588948614,0.18,0.22,0.27,144,6,1017.57,22.37,50.26,328,0.03,3,0.000000,0,0,0,2.410,1]],"firmware_revision":129}`
+
+func freeUDPAddr(t *testing.T) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := pc.LocalAddr().String()
+	_ = pc.Close()
+	return addr
+}
+
+func TestListenForObservation(t *testing.T) {
+	addr := freeUDPAddr(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		conn, err := net.Dial("udp", addr)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		for ctx.Err() == nil {
+			_, _ = conn.Write([]byte(`{"serial_number":"ST-00000512","type":"rapid_wind","ob":[1588948614,2.3,128]}`))
+			_, _ = conn.Write([]byte(`{"serial_number":"ST-99999999","type":"obs_st","obs":[[1588948000,0,0,0,0,6,900,-10,10]]}`))
+			_, _ = conn.Write([]byte(testObsSt))
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	obs, err := listenForObservation(ctx, addr, []string{"ST-00000512"}, 5*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if obs.AirTemperature != 22.37 || obs.RelativeHumidity != 50.26 {
+		t.Errorf("got temp %v, humidity %v; want 22.37, 50.26", obs.AirTemperature, obs.RelativeHumidity)
+	}
+
+	so := stationObservationFromUDP(obs, 42)
+	if so.StationID != 42 || so.BarometricPressure != 1017.57 || so.SeaLevelPressure != 0 || so.DewPoint == 0 {
+		t.Errorf("converted observation = %+v", so)
+	}
+}
+
+func TestListenForObservationTimeout(t *testing.T) {
+	_, err := listenForObservation(context.Background(), freeUDPAddr(t), nil, 50*time.Millisecond)
+	if errorKind(err) != KindTimeout {
+		t.Errorf("error = %v, want timeout", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = listenForObservation(ctx, freeUDPAddr(t), nil, time.Second)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestUDPSerials(t *testing.T) {
+	setupFakeServer(t, currentCmd, fakeserver.Options{}, "tempestd")
+	serverURL := viper.GetString("server")
+	home := fakeserver.Stations()[0]
+
+	sc := &config.StationConfig{StationID: home.StationID, Serial: "ST-00000512"}
+	if got, err := udpSerials(context.Background(), serverURL, sc); err != nil || !slices.Equal(got, []string{"ST-00000512"}) {
+		t.Errorf("configured serial: got %v, %v", got, err)
+	}
+
+	// Without a configured serial, the sensor's comes from the metadata and
+	// the hub's is left out.
+	sc.Serial = ""
+	got, err := udpSerials(context.Background(), serverURL, sc)
+	if err != nil || len(got) != 1 || got[0] != home.Devices[1].SerialNum {
+		t.Errorf("metadata serial: got %v, %v; want %s", got, err, home.Devices[1].SerialNum)
+	}
+
+	// An unknown station skips UDP so that the next source is tried.
+	sc.StationID = 9
+	if _, err := udpSerials(context.Background(), serverURL, sc); !errors.Is(err, errSourceSkipped) {
+		t.Errorf("unknown station: err = %v, want errSourceSkipped", err)
+	}
+}
+
+func TestListenForSignals(t *testing.T) {
+	addr := freeUDPAddr(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		conn, err := net.Dial("udp", addr)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		for ctx.Err() == nil {
+			_, _ = conn.Write([]byte(testObsSt))
+			_, _ = conn.Write([]byte(`{"serial_number":"ST-99999999","type":"device_status","rssi":-40,"hub_rssi":-50}`))
+			_, _ = conn.Write([]byte(`{"serial_number":"ST-00000512","type":"device_status","hub_sn":"HB-00013030","voltage":2.6,"rssi":-17,"hub_rssi":-87}`))
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	got, err := listenForSignals(ctx, addr, []string{"st-00000512"}, 5*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got["ST-00000512"] != (deviceSignal{SensorRSSI: -17, HubRSSI: -87}) {
+		t.Errorf("signals = %+v, want only ST-00000512's", got)
+	}
+
+	// A sensor that never reports leaves its signal unknown without failing.
+	got, err = listenForSignals(context.Background(), freeUDPAddr(t), []string{"ST-00000512"}, 50*time.Millisecond)
+	if err != nil || len(got) != 0 {
+		t.Errorf("got %+v, %v; want nothing after the timeout", got, err)
+	}
+}
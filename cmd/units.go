@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/units"
+	"github.com/spf13/viper"
+)
+
+// unitFlags are the per-quantity unit flags and the Set field each sets.
+var unitFlags = []struct {
+	flag string
+	set  func(*units.Set, string)
+}{
+	{"temp-unit", func(u *units.Set, v string) { u.Temperature = v }},
+	{"wind-unit", func(u *units.Set, v string) { u.Wind = v }},
+	{"pressure-unit", func(u *units.Set, v string) { u.Pressure = v }},
+	{"precip-unit", func(u *units.Set, v string) { u.Precipitation = v }},
+	{"distance-unit", func(u *units.Set, v string) { u.Distance = v }},
+}
+
+// resolveUnits returns the units to show sc's data in. --units replaces the
+// configured system and overrides; the per-quantity flags win over both.
+func resolveUnits(cfg *config.Config, sc *config.StationConfig) (units.Set, error) {
+	u, err := cfg.UnitsFor(sc)
+	if err != nil {
+		return units.Set{}, wrapConfigError(err)
+	}
+	if f := rootCmd.PersistentFlags().Lookup("units"); f != nil && f.Changed {
+		if u, err = units.System(f.Value.String()); err != nil {
+			return units.Set{}, usageError(err)
+		}
+	}
+	var override units.Set
+	for _, q := range unitFlags {
+		q.set(&override, viper.GetString(q.flag))
+	}
+	if override, err = override.Normalize(); err != nil {
+		return units.Set{}, usageError(err)
+	}
+	return u.With(override), nil
+}
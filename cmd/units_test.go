@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/config"
+	"github.com/chadmayfield/tempest-cli/internal/units"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/spf13/viper"
+)
+
+func TestResolveUnits(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	cfg := &config.Config{Units: "imperial", Unit: units.Set{Pressure: "hpa"}}
+	sc := &config.StationConfig{Unit: units.Set{Wind: "kn"}}
+
+	u, err := resolveUnits(cfg, sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := units.ImperialSet.With(units.Set{Pressure: units.Hectopascal, Wind: units.Knots})
+	if u != want {
+		t.Errorf("resolveUnits() = %+v, want %+v", u, want)
+	}
+
+	viper.Set("wind-unit", "bft")
+	viper.Set("temp-unit", "C")
+	if u, err = resolveUnits(cfg, sc); err != nil {
+		t.Fatal(err)
+	}
+	if u.Wind != units.Beaufort || u.Temperature != units.Celsius {
+		t.Errorf("flags not applied: %+v", u)
+	}
+
+	viper.Set("pressure-unit", "psi")
+	if _, err := resolveUnits(cfg, sc); errorKind(err) != KindUsage {
+		t.Errorf("bad flag: err = %v, want usage error", err)
+	}
+
+	viper.Reset()
+	cfg.Unit.Pressure = "psi"
+	if _, err := resolveUnits(cfg, sc); errorKind(err) != KindConfig {
+		t.Errorf("bad config: err = %v, want config error", err)
+	}
+}
+
+func TestCurrentJSONMixedUnits(t *testing.T) {
+	obs := &tempest.StationObservation{
+		Timestamp:        time.Now(),
+		AirTemperature:   20,
+		WindAvg:          10,
+		SeaLevelPressure: 1000,
+	}
+	u := units.MetricSet.With(units.Set{Wind: units.KilometersPerHour, Pressure: units.Kilopascal})
+	out := currentJSON(obs, &tempest.Station{}, &config.StationConfig{}, u)
+	if out.Units != units.Custom || out.Unit != u {
+		t.Errorf("units = %q %+v, want custom with the unit set", out.Units, out.Unit)
+	}
+	if out.Temperature != 20 || out.WindSpeed != 36 || out.Pressure != 100 {
+		t.Errorf("got temp %v wind %v pressure %v, want 20, 36, 100", out.Temperature, out.WindSpeed, out.Pressure)
+	}
+}
@@ -0,0 +1,188 @@
+// Package cache provides a small file-backed store for API results so that
+// frequent callers (status bars, scripts) don't hit the network on every run.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// entry is the on-disk representation of a cached value.
+type entry struct {
+	Key       string          `json:"key"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Store is a directory of JSON cache entries, one file per key.
+type Store struct {
+	dir string
+}
+
+// New returns a Store rooted at dir. The directory is created on first write.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultDir returns the per-user cache directory for tempest
+// (e.g. ~/.cache/tempest on Linux).
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("determining cache directory: %w", err)
+	}
+	return filepath.Join(base, "tempest"), nil
+}
+
+// Dir returns the directory backing the store.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Get decodes the entry for key into v if it exists and is younger than maxAge.
+// It returns the time the entry was stored and whether v was populated.
+// A maxAge of zero or less accepts an entry of any age.
+func (s *Store) Get(key string, maxAge time.Duration, v any) (time.Time, bool) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key {
+		return time.Time{}, false
+	}
+	if maxAge > 0 && time.Since(e.FetchedAt) > maxAge {
+		return e.FetchedAt, false
+	}
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return time.Time{}, false
+	}
+	return e.FetchedAt, true
+}
+
+// Put stores v under key with the current time. The file is written atomically
+// with 0600 permissions since cached payloads may identify the user's station.
+func (s *Store) Put(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+	raw, err := json.Marshal(entry{Key: key, FetchedAt: time.Now(), Data: data})
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating cache file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	return nil
+}
+
+// path maps a key to a file name. Keys are hashed so that arbitrary
+// strings (URLs, query parameters) are safe to use.
+func (s *Store) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// Stats summarises the contents of a store.
+type Stats struct {
+	Dir        string         `json:"dir"`
+	Entries    int            `json:"entries"`
+	Bytes      int64          `json:"bytes"`
+	Oldest     time.Time      `json:"oldest,omitzero"`
+	Newest     time.Time      `json:"newest,omitzero"`
+	ByEndpoint map[string]int `json:"by_endpoint"`
+}
+
+// Endpoints returns the endpoint names in Stats.ByEndpoint, sorted.
+func (st Stats) Endpoints() []string {
+	names := make([]string, 0, len(st.ByEndpoint))
+	for name := range st.ByEndpoint {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Stats walks the store and reports entry counts and sizes. Entries are grouped
+// by endpoint, the part of the key before the first "|". A missing directory
+// is reported as an empty store.
+func (s *Store) Stats() (Stats, error) {
+	st := Stats{Dir: s.dir, ByEndpoint: make(map[string]int)}
+	files, err := s.entryFiles()
+	if err != nil {
+		return st, err
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var e entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			continue
+		}
+		st.Entries++
+		st.Bytes += int64(len(data))
+		endpoint, _, _ := strings.Cut(e.Key, "|")
+		st.ByEndpoint[endpoint]++
+		if st.Oldest.IsZero() || e.FetchedAt.Before(st.Oldest) {
+			st.Oldest = e.FetchedAt
+		}
+		if e.FetchedAt.After(st.Newest) {
+			st.Newest = e.FetchedAt
+		}
+	}
+	return st, nil
+}
+
+// Clear removes every entry from the store and returns how many were removed.
+func (s *Store) Clear() (int, error) {
+	files, err := s.entryFiles()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, path := range files {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return n, fmt.Errorf("removing cache entry: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (s *Store) entryFiles() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("listing cache directory: %w", err)
+	}
+	return files, nil
+}
@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type payload struct {
+	Name  string
+	Value float64
+}
+
+func TestPutGet(t *testing.T) {
+	s := New(t.TempDir())
+
+	if err := s.Put("current|cloud|12345", payload{Name: "home", Value: 22.5}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+
+	var got payload
+	fetchedAt, ok := s.Get("current|cloud|12345", time.Minute, &got)
+	if !ok {
+		t.Fatal("expected cache hit")
+	}
+	if got.Name != "home" || got.Value != 22.5 {
+		t.Errorf("got %+v, want {home 22.5}", got)
+	}
+	if time.Since(fetchedAt) > time.Minute {
+		t.Errorf("fetchedAt = %v, want recent", fetchedAt)
+	}
+}
+
+func TestGetMissing(t *testing.T) {
+	s := New(t.TempDir())
+	var got payload
+	if _, ok := s.Get("nope", time.Minute, &got); ok {
+		t.Error("expected miss for unknown key")
+	}
+}
+
+func TestGetExpired(t *testing.T) {
+	s := New(t.TempDir())
+	if err := s.Put("k", payload{Name: "old"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got payload
+	if _, ok := s.Get("k", time.Nanosecond, &got); ok {
+		t.Error("expected miss for expired entry")
+	}
+	// maxAge <= 0 accepts any age
+	if _, ok := s.Get("k", 0, &got); !ok || got.Name != "old" {
+		t.Errorf("expected stale hit with maxAge 0, got ok=%v %+v", ok, got)
+	}
+}
+
+func TestPutPermissions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tempest")
+	s := New(dir)
+	if err := s.Put("k", payload{}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(s.path("k"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("cache file mode = %04o, want 0600", mode)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected exactly one file (no temp leftovers), got %d", len(entries))
+	}
+}
+
+func TestGetCorrupt(t *testing.T) {
+	s := New(t.TempDir())
+	if err := os.WriteFile(s.path("k"), []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var got payload
+	if _, ok := s.Get("k", 0, &got); ok {
+		t.Error("expected miss for corrupt entry")
+	}
+}
+
+func TestStatsAndClear(t *testing.T) {
+	s := New(t.TempDir())
+	for _, k := range []string{"current|cloud|1", "current|cloud|2", "forecast|cloud|1"} {
+		if err := s.Put(k, payload{Name: k}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	st, err := s.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error: %v", err)
+	}
+	if st.Entries != 3 {
+		t.Errorf("Entries = %d, want 3", st.Entries)
+	}
+	if st.ByEndpoint["current"] != 2 || st.ByEndpoint["forecast"] != 1 {
+		t.Errorf("ByEndpoint = %v", st.ByEndpoint)
+	}
+	if got := st.Endpoints(); len(got) != 2 || got[0] != "current" {
+		t.Errorf("Endpoints() = %v", got)
+	}
+
+	n, err := s.Clear()
+	if err != nil || n != 3 {
+		t.Fatalf("Clear() = %d, %v; want 3, nil", n, err)
+	}
+	if st, _ := s.Stats(); st.Entries != 0 {
+		t.Errorf("Entries after Clear = %d, want 0", st.Entries)
+	}
+}
+
+func TestStatsMissingDir(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "missing"))
+	st, err := s.Stats()
+	if err != nil || st.Entries != 0 {
+		t.Errorf("Stats() on missing dir = %+v, %v", st, err)
+	}
+}
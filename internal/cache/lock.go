@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+const (
+	// staleLockAge is how old a lock file may be before it is assumed to belong
+	// to a crashed process and is broken.
+	staleLockAge     = 30 * time.Second
+	lockPollInterval = 50 * time.Millisecond
+)
+
+// Lock acquires an exclusive cross-process lock for key, blocking until the
+// lock is free or ctx is done. Callers must call the returned unlock function.
+//
+// The lock is an O_EXCL lock file next to the entry, which works the same on
+// every platform and on network home directories.
+func (s *Store) Lock(ctx context.Context, key string) (func(), error) {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	path := s.path(key) + ".lock"
+
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, _ = fmt.Fprintf(f, "%d\n", os.Getpid())
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("acquiring cache lock: %w", err)
+		}
+
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(path)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLockExclusive(t *testing.T) {
+	s := New(t.TempDir())
+	ctx := context.Background()
+
+	var inside, maxInside atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := s.Lock(ctx, "k")
+			if err != nil {
+				t.Errorf("Lock() error: %v", err)
+				return
+			}
+			n := inside.Add(1)
+			if n > maxInside.Load() {
+				maxInside.Store(n)
+			}
+			time.Sleep(5 * time.Millisecond)
+			inside.Add(-1)
+			unlock()
+		}()
+	}
+	wg.Wait()
+	if maxInside.Load() != 1 {
+		t.Errorf("max concurrent holders = %d, want 1", maxInside.Load())
+	}
+}
+
+func TestLockContextTimeout(t *testing.T) {
+	s := New(t.TempDir())
+	unlock, err := s.Lock(context.Background(), "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := s.Lock(ctx, "k"); err == nil {
+		t.Error("expected timeout while lock is held")
+	}
+}
+
+func TestLockBreaksStaleLock(t *testing.T) {
+	s := New(t.TempDir())
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	path := s.path("k") + ".lock"
+	if err := os.WriteFile(path, []byte("99999\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleLockAge)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	unlock, err := s.Lock(ctx, "k")
+	if err != nil {
+		t.Fatalf("expected stale lock to be broken, got %v", err)
+	}
+	unlock()
+}
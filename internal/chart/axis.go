@@ -0,0 +1,86 @@
+package chart
+
+import (
+	"math"
+	"time"
+)
+
+// niceNum returns a "nice" number near x: 1, 2 or 5 times a power of ten,
+// rounded if round is set and otherwise at least x.
+func niceNum(x float64, round bool) float64 {
+	exp := math.Floor(math.Log10(x))
+	f := x / math.Pow(10, exp)
+	var nf float64
+	switch {
+	case round && f < 1.5, !round && f <= 1:
+		nf = 1
+	case round && f < 3, !round && f <= 2:
+		nf = 2
+	case round && f < 7, !round && f <= 5:
+		nf = 5
+	default:
+		nf = 10
+	}
+	return nf * math.Pow(10, exp)
+}
+
+// valueTicks returns axis bounds covering lo to hi at a nice step, with
+// about n ticks, and the decimals needed to label them.
+func valueTicks(lo, hi float64, n int) (axisMin, axisMax, step float64, decimals int) {
+	if hi-lo < 1e-9 {
+		lo, hi = lo-1, hi+1
+	}
+	step = niceNum(niceNum(hi-lo, false)/float64(max(n-1, 1)), true)
+	axisMin = math.Floor(lo/step) * step
+	axisMax = math.Ceil(hi/step) * step
+	decimals = max(0, int(-math.Floor(math.Log10(step))))
+	return axisMin, axisMax, step, decimals
+}
+
+// tickSteps are the time axis intervals, finest first.
+var tickSteps = []time.Duration{
+	time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour,
+	24 * time.Hour, 2 * 24 * time.Hour, 7 * 24 * time.Hour, 14 * 24 * time.Hour,
+}
+
+// timeTicks returns at most about n tick times between start and end. Ticks
+// fall on whole hours, minutes or days of the wall clock in loc, so they
+// stay on the hour across daylight saving changes.
+func timeTicks(start, end time.Time, loc *time.Location, n int) ([]time.Time, time.Duration) {
+	span := end.Sub(start)
+	step := time.Duration(0)
+	for _, s := range tickSteps {
+		if span/s <= time.Duration(max(n, 1)) {
+			step = s
+			break
+		}
+	}
+	if step == 0 {
+		weeks := span/(time.Duration(max(n, 1))*7*24*time.Hour) + 1
+		step = weeks * 7 * 24 * time.Hour
+	}
+
+	s := start.In(loc)
+	var ticks []time.Time
+	for i := 0; ; i++ {
+		var t time.Time
+		if step < 24*time.Hour {
+			t = time.Date(s.Year(), s.Month(), s.Day(), 0, i*int(step/time.Minute), 0, 0, loc)
+		} else {
+			t = time.Date(s.Year(), s.Month(), s.Day()+i*int(step/(24*time.Hour)), 0, 0, 0, 0, loc)
+		}
+		if t.After(end) {
+			break
+		}
+		if !t.Before(start) {
+			ticks = append(ticks, t)
+		}
+	}
+	return ticks, step
+}
+
+// isMidnight reports whether t is midnight in its location.
+func isMidnight(t time.Time) bool {
+	return t.Hour() == 0 && t.Minute() == 0
+}
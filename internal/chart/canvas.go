@@ -0,0 +1,250 @@
+package chart
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// anchor is which part of a text is at its x coordinate.
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+type point struct{ X, Y float64 }
+
+// canvas is a drawing surface. Coordinates are pixels from the top left;
+// text is placed by its baseline.
+type canvas interface {
+	fillRect(x, y, w, h float64, c color.NRGBA)
+	// stroke draws a line through pts.
+	stroke(pts []point, width float64, c color.NRGBA)
+	// fill fills the polygon pts.
+	fill(pts []point, c color.NRGBA)
+	text(x, y float64, s string, size float64, bold bool, a anchor, c color.NRGBA)
+	textWidth(s string, size float64, bold bool) float64
+	encode(w io.Writer) error
+}
+
+// Both formats lay text out with the Go fonts, which SVG viewers are asked
+// to use too, so that labels are measured the same way in each.
+var fonts = sync.OnceValues(func() ([2]*opentype.Font, error) {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return [2]*opentype.Font{}, err
+	}
+	bold, err := opentype.Parse(gobold.TTF)
+	return [2]*opentype.Font{regular, bold}, err
+})
+
+// faces caches font faces by size and weight. Faces are not safe for
+// concurrent use, so each canvas has its own.
+type faces map[[2]int]font.Face
+
+func (f faces) get(size float64, bold bool) font.Face {
+	key := [2]int{int(size), 0}
+	if bold {
+		key[1] = 1
+	}
+	if face, ok := f[key]; ok {
+		return face
+	}
+	ttfs, err := fonts()
+	if err != nil {
+		panic(fmt.Sprintf("chart: parsing embedded font: %v", err))
+	}
+	face, err := opentype.NewFace(ttfs[key[1]], &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		panic(fmt.Sprintf("chart: creating font face: %v", err))
+	}
+	f[key] = face
+	return face
+}
+
+// textWidth returns the width of s in pixels.
+func (f faces) textWidth(s string, size float64, bold bool) float64 {
+	return float64(font.MeasureString(f.get(size, bold), s)) / 64
+}
+
+func anchorOffset(a anchor, width float64) float64 {
+	switch a {
+	case anchorMiddle:
+		return width / 2
+	case anchorEnd:
+		return width
+	}
+	return 0
+}
+
+// pngCanvas draws antialiased shapes and text into an RGBA image.
+type pngCanvas struct {
+	faces
+	img *image.RGBA
+	r   *vector.Rasterizer
+}
+
+func newPNGCanvas(w, h int) *pngCanvas {
+	return &pngCanvas{img: image.NewRGBA(image.Rect(0, 0, w, h)), r: vector.NewRasterizer(w, h), faces: faces{}}
+}
+
+func (p *pngCanvas) fillRect(x, y, w, h float64, c color.NRGBA) {
+	p.fill([]point{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}, c)
+}
+
+func (p *pngCanvas) fill(pts []point, c color.NRGBA) {
+	if len(pts) < 3 {
+		return
+	}
+	b := p.img.Bounds()
+	p.r.Reset(b.Dx(), b.Dy())
+	p.path(pts)
+	p.r.Draw(p.img, b, image.NewUniform(c), image.Point{})
+}
+
+func (p *pngCanvas) path(pts []point) {
+	p.r.MoveTo(float32(pts[0].X), float32(pts[0].Y))
+	for _, pt := range pts[1:] {
+		p.r.LineTo(float32(pt.X), float32(pt.Y))
+	}
+	p.r.ClosePath()
+}
+
+// stroke rasterizes each segment as a rectangle and each joint as an
+// octagon. They all wind the same way, so overlaps are not drawn twice.
+func (p *pngCanvas) stroke(pts []point, width float64, c color.NRGBA) {
+	if len(pts) < 2 {
+		return
+	}
+	b := p.img.Bounds()
+	p.r.Reset(b.Dx(), b.Dy())
+	hw := width / 2
+	for i := 1; i < len(pts); i++ {
+		a, z := pts[i-1], pts[i]
+		dx, dy := z.X-a.X, z.Y-a.Y
+		l := math.Hypot(dx, dy)
+		if l == 0 {
+			continue
+		}
+		nx, ny := -dy/l*hw, dx/l*hw
+		p.path([]point{{a.X + nx, a.Y + ny}, {z.X + nx, z.Y + ny}, {z.X - nx, z.Y - ny}, {a.X - nx, a.Y - ny}})
+	}
+	for _, pt := range pts[1 : len(pts)-1] {
+		oct := make([]point, 8)
+		for k := range oct {
+			angle := -float64(k) * math.Pi / 4
+			oct[k] = point{pt.X + hw*math.Cos(angle), pt.Y + hw*math.Sin(angle)}
+		}
+		p.path(oct)
+	}
+	p.r.Draw(p.img, b, image.NewUniform(c), image.Point{})
+}
+
+func (p *pngCanvas) text(x, y float64, s string, size float64, bold bool, a anchor, c color.NRGBA) {
+	face := p.faces.get(size, bold)
+	x -= anchorOffset(a, p.faces.textWidth(s, size, bold))
+	d := font.Drawer{Dst: p.img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(int(math.Round(x)), int(math.Round(y)))}
+	d.DrawString(s)
+}
+
+func (p *pngCanvas) encode(w io.Writer) error {
+	// Every pixel is opaque once the background is drawn; NRGBA avoids
+	// encoding premultiplied values.
+	out := image.NewNRGBA(p.img.Bounds())
+	draw.Draw(out, out.Bounds(), p.img, image.Point{}, draw.Src)
+	return png.Encode(w, out)
+}
+
+// svgCanvas writes shapes and text as SVG elements.
+type svgCanvas struct {
+	faces
+	w, h int
+	b    strings.Builder
+}
+
+func newSVGCanvas(w, h int) *svgCanvas {
+	return &svgCanvas{faces: faces{}, w: w, h: h}
+}
+
+func (s *svgCanvas) fillRect(x, y, w, h float64, c color.NRGBA) {
+	s.b.WriteString(fmt.Sprintf(`<rect x="%s" y="%s" width="%s" height="%s"%s/>`+"\n", num(x), num(y), num(w), num(h), svgPaint("fill", c)))
+}
+
+func (s *svgCanvas) stroke(pts []point, width float64, c color.NRGBA) {
+	if len(pts) < 2 {
+		return
+	}
+	s.b.WriteString(fmt.Sprintf(`<polyline points="%s" fill="none" stroke-width="%s" stroke-linejoin="round" stroke-linecap="round"%s/>`+"\n",
+		svgPoints(pts), num(width), svgPaint("stroke", c)))
+}
+
+func (s *svgCanvas) fill(pts []point, c color.NRGBA) {
+	if len(pts) < 3 {
+		return
+	}
+	s.b.WriteString(fmt.Sprintf(`<polygon points="%s"%s/>`+"\n", svgPoints(pts), svgPaint("fill", c)))
+}
+
+func (s *svgCanvas) text(x, y float64, str string, size float64, bold bool, a anchor, c color.NRGBA) {
+	attrs := ""
+	switch a {
+	case anchorMiddle:
+		attrs = ` text-anchor="middle"`
+	case anchorEnd:
+		attrs = ` text-anchor="end"`
+	}
+	if bold {
+		attrs += ` font-weight="bold"`
+	}
+	s.b.WriteString(fmt.Sprintf(`<text x="%s" y="%s" font-size="%s"%s%s>%s</text>`+"\n", num(x), num(y), num(size), attrs, svgPaint("fill", c), html.EscapeString(str)))
+}
+
+func (s *svgCanvas) encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Go, 'Helvetica Neue', Arial, sans-serif">`+"\n", s.w, s.h, s.w, s.h)
+	_, _ = bw.WriteString(s.b.String())
+	_, _ = bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// svgPaint returns a fill or stroke attribute for c, with its opacity if
+// it is translucent.
+func svgPaint(attr string, c color.NRGBA) string {
+	s := fmt.Sprintf(` %s="#%02x%02x%02x"`, attr, c.R, c.G, c.B)
+	if c.A != 0xff {
+		s += fmt.Sprintf(` %s-opacity="%s"`, attr, num(float64(c.A)/255))
+	}
+	return s
+}
+
+func svgPoints(pts []point) string {
+	parts := make([]string, len(pts))
+	for i, p := range pts {
+		parts[i] = num(p.X) + "," + num(p.Y)
+	}
+	return strings.Join(parts, " ")
+}
+
+// num formats a coordinate to a tenth of a pixel.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
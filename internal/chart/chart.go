@@ -0,0 +1,179 @@
+// Package chart draws time-series charts, meteograms and wind roses as PNG
+// or SVG images, in pure Go.
+package chart
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is an image format.
+type Format string
+
+const (
+	PNG Format = "png"
+	SVG Format = "svg"
+)
+
+// FormatFromPath returns the image format for path's extension.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return PNG, nil
+	case ".svg":
+		return SVG, nil
+	}
+	return "", fmt.Errorf("unknown image type for %q; use a .png or .svg file", path)
+}
+
+// Chart is something Render can draw: a TimeSeries or a WindRose.
+type Chart interface {
+	draw(c canvas, o *Options)
+}
+
+// Options control a chart's size, title, colors and labels. Zero values
+// use the defaults.
+type Options struct {
+	// Width and Height are the image size in pixels.
+	Width, Height int
+	Title         string
+	// Footer is a note in the bottom right corner, e.g. the time zone.
+	Footer string
+	// Location is the time zone of the time axis; nil means UTC.
+	Location *time.Location
+	Palette  Palette
+	// TimeLabel and DateLabel format time axis labels at times of day and
+	// at midnight; the defaults are "15:04" and "Jan 2".
+	TimeLabel, DateLabel func(time.Time) string
+	// Number formats axis values with a number of decimals.
+	Number func(v float64, decimals int) string
+}
+
+// DefaultWidth and DefaultHeight are the size of a chart without one.
+const (
+	DefaultWidth  = 1000
+	DefaultHeight = 500
+)
+
+func (o *Options) setDefaults() {
+	if o.Width <= 0 {
+		o.Width = DefaultWidth
+	}
+	if o.Height <= 0 {
+		o.Height = DefaultHeight
+	}
+	if o.Location == nil {
+		o.Location = time.UTC
+	}
+	if o.Palette.Background.A == 0 {
+		o.Palette = PaletteFor(color.NRGBA{0xff, 0xff, 0xff, 0xff}, o.Palette.Series)
+	}
+	if len(o.Palette.Series) == 0 {
+		o.Palette.Series = defaultSeries
+	}
+	if o.TimeLabel == nil {
+		o.TimeLabel = func(t time.Time) string { return t.Format("15:04") }
+	}
+	if o.DateLabel == nil {
+		o.DateLabel = func(t time.Time) string { return t.Format("Jan 2") }
+	}
+	if o.Number == nil {
+		o.Number = func(v float64, decimals int) string { return strconv.FormatFloat(v, 'f', decimals, 64) }
+	}
+}
+
+// Palette is a chart's colors.
+type Palette struct {
+	Background, Text, Grid color.NRGBA
+	// Series colors series that do not set their own, in order.
+	Series []color.NRGBA
+}
+
+// defaultSeries is a categorical palette that reads on light and dark
+// backgrounds.
+var defaultSeries = []color.NRGBA{
+	{0xd6, 0x27, 0x28, 0xff}, {0x2c, 0xa0, 0x2c, 0xff}, {0x94, 0x67, 0xbd, 0xff}, {0x1f, 0x77, 0xb4, 0xff},
+	{0xff, 0x7f, 0x0e, 0xff}, {0x17, 0xbe, 0xcf, 0xff}, {0x8c, 0x56, 0x4b, 0xff}, {0xe3, 0x77, 0xc2, 0xff},
+}
+
+// PaletteFor returns a palette on background, with text and grid colors
+// that contrast with it. Nil series uses the default series colors.
+func PaletteFor(background color.NRGBA, series []color.NRGBA) Palette {
+	background.A = 0xff
+	p := Palette{
+		Background: background,
+		Text:       color.NRGBA{0x33, 0x33, 0x33, 0xff},
+		Grid:       color.NRGBA{0xdd, 0xdd, 0xdd, 0xff},
+		Series:     series,
+	}
+	// Rec. 601 luma is close enough to pick light or dark text.
+	if 299*int(background.R)+587*int(background.G)+114*int(background.B) < 128000 {
+		p.Text = color.NRGBA{0xdd, 0xdd, 0xdd, 0xff}
+		p.Grid = color.NRGBA{0x44, 0x44, 0x44, 0xff}
+	}
+	return p
+}
+
+// seriesColor returns the i'th series color, cycling through the palette.
+func (p Palette) seriesColor(i int) color.NRGBA {
+	return p.Series[i%len(p.Series)]
+}
+
+// ParseColor parses "#rrggbb" or "#rgb".
+func ParseColor(s string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q; use #rrggbb or #rgb", s)
+	}
+	return color.NRGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}, nil
+}
+
+// Render draws c in format f to w.
+func Render(w io.Writer, f Format, c Chart, o Options) error {
+	o.setDefaults()
+	var cv canvas
+	switch f {
+	case PNG:
+		cv = newPNGCanvas(o.Width, o.Height)
+	case SVG:
+		cv = newSVGCanvas(o.Width, o.Height)
+	default:
+		return fmt.Errorf("unknown image format %q", f)
+	}
+	cv.fillRect(0, 0, float64(o.Width), float64(o.Height), o.Palette.Background)
+	c.draw(cv, &o)
+	return cv.encode(w)
+}
+
+// Font sizes in pixels.
+const (
+	titleSize = 16
+	labelSize = 12
+	smallSize = 11
+)
+
+// drawTitle draws the title, if any, and returns the y below it.
+func drawTitle(c canvas, o *Options, y float64) float64 {
+	if o.Title == "" {
+		return y
+	}
+	c.text(float64(o.Width)/2, y+titleSize, o.Title, titleSize, true, anchorMiddle, o.Palette.Text)
+	return y + titleSize + 14
+}
+
+// drawFooter draws the footer, if any, in the bottom right corner.
+func drawFooter(c canvas, o *Options, margin float64) {
+	if o.Footer == "" {
+		return
+	}
+	c.text(float64(o.Width)-margin, float64(o.Height)-margin/2, o.Footer, smallSize, false, anchorEnd, o.Palette.Text)
+}
@@ -0,0 +1,213 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatFromPath(t *testing.T) {
+	for path, want := range map[string]Format{"a.png": PNG, "dir/B.PNG": PNG, "c.svg": SVG} {
+		if got, err := FormatFromPath(path); err != nil || got != want {
+			t.Errorf("FormatFromPath(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	if _, err := FormatFromPath("chart.jpg"); err == nil {
+		t.Error("FormatFromPath(chart.jpg) should fail")
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := map[string]color.NRGBA{
+		"#ff8000": {0xff, 0x80, 0x00, 0xff},
+		"0a0B0c":  {0x0a, 0x0b, 0x0c, 0xff},
+		"#f80":    {0xff, 0x88, 0x00, 0xff},
+	}
+	for in, want := range tests {
+		if got, err := ParseColor(in); err != nil || got != want {
+			t.Errorf("ParseColor(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "red", "#12345", "#ggg"} {
+		if _, err := ParseColor(bad); err == nil {
+			t.Errorf("ParseColor(%q) should fail", bad)
+		}
+	}
+}
+
+func TestPaletteFor(t *testing.T) {
+	dark := PaletteFor(color.NRGBA{0x10, 0x10, 0x10, 0xff}, nil)
+	light := PaletteFor(color.NRGBA{0xf0, 0xf0, 0xf0, 0xff}, nil)
+	if dark.Text.R <= dark.Background.R || light.Text.R >= light.Background.R {
+		t.Errorf("text should contrast with the background: dark %v, light %v", dark, light)
+	}
+}
+
+func TestValueTicks(t *testing.T) {
+	tests := []struct {
+		lo, hi                 float64
+		wantMin, wantMax, step float64
+		decimals               int
+	}{
+		{21.3, 38.9, 20, 40, 5, 0},
+		{29.82, 30.07, 29.8, 30.1, 0.1, 1},
+		{0, 0.37, 0, 0.4, 0.1, 1},
+		{5, 5, 4, 6, 0.5, 1},
+	}
+	for _, tt := range tests {
+		lo, hi, step, dec := valueTicks(tt.lo, tt.hi, 5)
+		if math.Abs(lo-tt.wantMin) > 1e-9 || math.Abs(hi-tt.wantMax) > 1e-9 || math.Abs(step-tt.step) > 1e-9 || dec != tt.decimals {
+			t.Errorf("valueTicks(%v, %v) = %v, %v, %v, %d; want %v, %v, %v, %d",
+				tt.lo, tt.hi, lo, hi, step, dec, tt.wantMin, tt.wantMax, tt.step, tt.decimals)
+		}
+	}
+}
+
+func TestTimeTicksFollowWallClock(t *testing.T) {
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	// Clocks went forward at 2:00 on March 10, 2024.
+	start := time.Date(2024, 3, 9, 0, 0, 0, 0, denver)
+	end := time.Date(2024, 3, 11, 0, 0, 0, 0, denver)
+	ticks, step := timeTicks(start, end, denver, 8)
+	if step != 6*time.Hour {
+		t.Fatalf("step = %v, want 6h", step)
+	}
+	var got []string
+	for _, tk := range ticks {
+		got = append(got, tk.In(denver).Format("Jan 2 15:04"))
+	}
+	want := "Mar 9 00:00,Mar 9 06:00,Mar 9 12:00,Mar 9 18:00,Mar 10 00:00,Mar 10 06:00,Mar 10 12:00,Mar 10 18:00,Mar 11 00:00"
+	if strings.Join(got, ",") != want {
+		t.Errorf("ticks = %s\nwant    %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestDecimateKeepsExtremes(t *testing.T) {
+	var pts []point
+	for i := 0; i < 1000; i++ {
+		pts = append(pts, point{X: float64(i) / 100, Y: 50})
+	}
+	pts[420].Y = 5
+	pts[421].Y = 95
+	out := decimate(pts, 10)
+	if len(out) >= len(pts) {
+		t.Fatalf("decimate kept %d of %d points", len(out), len(pts))
+	}
+	var low, high bool
+	for _, p := range out {
+		low = low || p.Y == 5
+		high = high || p.Y == 95
+	}
+	if !low || !high {
+		t.Error("decimate dropped the peak")
+	}
+}
+
+func TestWindRoseFrequencies(t *testing.T) {
+	r := WindRose{Calm: 0.5, Samples: []WindSample{
+		{Direction: 0, Speed: 2}, {Direction: 355, Speed: 9}, // N
+		{Direction: 90, Speed: 4},  // E
+		{Direction: 200, Speed: 0}, // calm
+	}}
+	step, n, _ := r.bands()
+	if step != 2 || n != 5 {
+		t.Fatalf("bands = %v, %d; want 2, 5", step, n)
+	}
+	freq, calm := r.frequencies(step, n)
+	if calm != 25 {
+		t.Errorf("calm = %v, want 25", calm)
+	}
+	if freq[0][1] != 25 || freq[0][4] != 25 || freq[4][2] != 25 {
+		t.Errorf("freq N = %v, E = %v", freq[0], freq[4])
+	}
+}
+
+func testSeries(loc *time.Location) TimeSeries {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, loc)
+	var temp, rain []Point
+	for i := 0; i < 48; i++ {
+		at := start.Add(time.Duration(i) * time.Hour)
+		temp = append(temp, Point{at, 20 + 5*math.Sin(float64(i)/4)})
+		rain = append(rain, Point{at, float64(i%3) * 0.1})
+	}
+	temp[10].Value = math.NaN()
+	return TimeSeries{Panels: []Panel{
+		{Label: "Temperature (°C)", Series: []Series{{Name: "Temp", Points: temp}}},
+		{Label: "Rain (mm)", Series: []Series{{Name: "Rain", Points: rain, Step: time.Hour}}},
+	}}
+}
+
+func TestRenderPNG(t *testing.T) {
+	var buf bytes.Buffer
+	bg := color.NRGBA{0x20, 0x20, 0x20, 0xff}
+	err := Render(&buf, PNG, testSeries(time.UTC), Options{Width: 400, Height: 300, Title: "Test", Palette: PaletteFor(bg, nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 400 || b.Dy() != 300 {
+		t.Fatalf("size = %v, want 400x300", b)
+	}
+	if got := color.NRGBAModel.Convert(img.At(1, 1)); got != bg {
+		t.Errorf("background = %v, want %v", got, bg)
+	}
+	colors := map[color.Color]bool{}
+	for y := 0; y < 300; y += 2 {
+		for x := 0; x < 400; x += 2 {
+			colors[img.At(x, y)] = true
+		}
+	}
+	if len(colors) < 10 {
+		t.Errorf("image has only %d colors; nothing was drawn", len(colors))
+	}
+}
+
+func TestRenderSVGUsesLocation(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*3600)
+	var buf bytes.Buffer
+	// The data covers June 1 and 2 in UTC, which starts at 09:00 in Tokyo.
+	err := Render(&buf, SVG, testSeries(time.UTC), Options{Title: "A & B", Footer: "Time zone: JST", Location: tokyo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if err := xml.Unmarshal(buf.Bytes(), new(struct{})); err != nil {
+		t.Fatalf("invalid SVG: %v", err)
+	}
+	for _, want := range []string{`width="1000" height="500"`, ">A &amp; B</text>", ">Temperature (°C)</text>", ">12:00</text>", ">Jun 2</text>", ">Time zone: JST</text>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("SVG missing %q", want)
+		}
+	}
+	// In Tokyo the data starts at 09:00 on June 1, so the first date on the
+	// axis is June 2.
+	if strings.Contains(out, ">Jun 1</text>") {
+		t.Error("ticks should be placed in the chart's location")
+	}
+}
+
+func TestRenderWindRose(t *testing.T) {
+	var buf bytes.Buffer
+	rose := WindRose{Unit: "mph", Compass: [4]string{"N", "O", "S", "W"}, CalmLabel: "Windstill",
+		Samples: []WindSample{{90, 3}, {90, 12}, {270, 0}}}
+	if err := Render(&buf, SVG, rose, Options{Width: 500, Height: 400}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{">O</text>", ">mph</text>", ">Windstill 33.3%</text>", "<polygon"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("wind rose missing %q", want)
+		}
+	}
+}
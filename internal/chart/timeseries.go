@@ -0,0 +1,262 @@
+package chart
+
+import (
+	"image/color"
+	"math"
+	"slices"
+	"time"
+)
+
+// Point is a value at a time.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is a named sequence of points. NaN values leave a gap in a line.
+type Series struct {
+	Name   string
+	Points []Point
+	// Step, if set, draws the series as bars, each Step wide from its
+	// point's time, instead of a line.
+	Step time.Duration
+	// Color is the series color; zero takes the next palette color.
+	Color color.NRGBA
+}
+
+// Panel is a plot of series sharing a value axis, e.g. temperature and dew
+// point.
+type Panel struct {
+	Label  string
+	Series []Series
+}
+
+// TimeSeries is one or more panels stacked on a shared time axis. With one
+// panel it is a line or bar chart; with several, a meteogram.
+type TimeSeries struct {
+	Panels []Panel
+}
+
+// span returns the times covered by every series.
+func (ts TimeSeries) span() (start, end time.Time, ok bool) {
+	for _, p := range ts.Panels {
+		for _, s := range p.Series {
+			for _, pt := range s.Points {
+				last := pt.Time.Add(s.Step)
+				if !ok || pt.Time.Before(start) {
+					start = pt.Time
+				}
+				if !ok || last.After(end) {
+					end = last
+				}
+				ok = true
+			}
+		}
+	}
+	return start, end, ok && end.After(start)
+}
+
+// valueRange returns the smallest and largest values in p. Bars always
+// include zero.
+func (p Panel) valueRange() (lo, hi float64, ok bool) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, s := range p.Series {
+		if s.Step > 0 {
+			lo, hi = math.Min(lo, 0), math.Max(hi, 0)
+		}
+		for _, pt := range s.Points {
+			if !math.IsNaN(pt.Value) && !math.IsInf(pt.Value, 0) {
+				lo, hi = math.Min(lo, pt.Value), math.Max(hi, pt.Value)
+			}
+		}
+	}
+	return lo, hi, lo <= hi
+}
+
+// Layout, in pixels.
+const (
+	margin      = 16.0
+	panelHeader = 20.0
+	panelGap    = 14.0
+	axisLabels  = 20.0
+	footerRow   = 16.0
+	tickSpacing = 90.0
+)
+
+type valueAxis struct {
+	min, max, step float64
+	decimals       int
+}
+
+func (ts TimeSeries) draw(c canvas, o *Options) {
+	w, h := float64(o.Width), float64(o.Height)
+	top := drawTitle(c, o, margin)
+	drawFooter(c, o, margin)
+	start, end, ok := ts.span()
+	if !ok || len(ts.Panels) == 0 {
+		return
+	}
+
+	bottom := h - margin - axisLabels
+	if o.Footer != "" {
+		bottom -= footerRow
+	}
+	n := float64(len(ts.Panels))
+	panelH := (bottom - top - panelGap*(n-1)) / n
+	plotH := panelH - panelHeader
+
+	// The value axes decide the left margin.
+	axes := make([]valueAxis, len(ts.Panels))
+	labelW := 0.0
+	for i, p := range ts.Panels {
+		lo, hi, ok := p.valueRange()
+		if !ok {
+			lo, hi = 0, 1
+		}
+		a := &axes[i]
+		a.min, a.max, a.step, a.decimals = valueTicks(lo, hi, max(2, int(plotH/40)))
+		for v := a.min; v <= a.max+a.step/2; v += a.step {
+			labelW = math.Max(labelW, c.textWidth(o.Number(v, a.decimals), smallSize, false))
+		}
+	}
+	x0, x1 := margin+labelW+8, w-margin
+	xOf := func(t time.Time) float64 {
+		return x0 + float64(t.Sub(start))/float64(end.Sub(start))*(x1-x0)
+	}
+	ticks, step := timeTicks(start, end, o.Location, int((x1-x0)/tickSpacing))
+
+	seriesIndex := 0
+	var y1 float64
+	for i, p := range ts.Panels {
+		y0 := top + float64(i)*(panelH+panelGap) + panelHeader
+		y1 = y0 + plotH
+		a := axes[i]
+		yOf := func(v float64) float64 {
+			return y1 - (v-a.min)/(a.max-a.min)*(y1-y0)
+		}
+
+		for v := a.min; v <= a.max+a.step/2; v += a.step {
+			y := crisp(yOf(v))
+			c.stroke([]point{{x0, y}, {x1, y}}, 1, o.Palette.Grid)
+			c.text(x0-6, y+4, o.Number(v, a.decimals), smallSize, false, anchorEnd, o.Palette.Text)
+		}
+		for _, t := range ticks {
+			x := crisp(xOf(t))
+			c.stroke([]point{{x, y0}, {x, y1}}, 1, o.Palette.Grid)
+		}
+
+		colors := make([]color.NRGBA, len(p.Series))
+		for k, s := range p.Series {
+			colors[k] = s.Color
+			if colors[k].A == 0 {
+				colors[k] = o.Palette.seriesColor(seriesIndex)
+			}
+			seriesIndex++
+		}
+		// Bars go under lines.
+		for k, s := range p.Series {
+			if s.Step > 0 {
+				drawBars(c, s, xOf, yOf(math.Max(a.min, 0)), yOf, colors[k])
+			}
+		}
+		for k, s := range p.Series {
+			if s.Step == 0 {
+				drawLine(c, s, xOf, yOf, x1-x0, colors[k])
+			}
+		}
+		drawPanelHeader(c, o, p, colors, x0, y0-6)
+	}
+
+	for _, t := range ticks {
+		label, bold := o.TimeLabel(t.In(o.Location)), false
+		if step >= 24*time.Hour || isMidnight(t.In(o.Location)) {
+			label, bold = o.DateLabel(t.In(o.Location)), step < 24*time.Hour
+		}
+		c.text(xOf(t), y1+16, label, smallSize, bold, anchorMiddle, o.Palette.Text)
+	}
+}
+
+// drawPanelHeader draws a panel's label and, if it has several series, a
+// legend after it.
+func drawPanelHeader(c canvas, o *Options, p Panel, colors []color.NRGBA, x, baseline float64) {
+	c.text(x, baseline, p.Label, labelSize, true, anchorStart, o.Palette.Text)
+	if len(p.Series) < 2 {
+		return
+	}
+	x += c.textWidth(p.Label, labelSize, true) + 18
+	for k, s := range p.Series {
+		if s.Step > 0 {
+			c.fillRect(x, baseline-9, 10, 10, colors[k])
+		} else {
+			c.fillRect(x, baseline-5, 14, 3, colors[k])
+		}
+		x += 18
+		c.text(x, baseline, s.Name, smallSize, false, anchorStart, o.Palette.Text)
+		x += c.textWidth(s.Name, smallSize, false) + 16
+	}
+}
+
+func drawBars(c canvas, s Series, xOf func(time.Time) float64, zero float64, yOf func(float64) float64, col color.NRGBA) {
+	for _, pt := range s.Points {
+		if pt.Value == 0 || math.IsNaN(pt.Value) {
+			continue
+		}
+		bx0, bx1 := xOf(pt.Time), xOf(pt.Time.Add(s.Step))
+		if bx1-bx0 > 3 {
+			bx0, bx1 = bx0+0.5, bx1-0.5
+		}
+		y := yOf(pt.Value)
+		c.fillRect(bx0, math.Min(y, zero), bx1-bx0, math.Abs(zero-y), col)
+	}
+}
+
+func drawLine(c canvas, s Series, xOf func(time.Time) float64, yOf func(float64) float64, width float64, col color.NRGBA) {
+	pts := slices.Clone(s.Points)
+	slices.SortStableFunc(pts, func(a, b Point) int { return a.Time.Compare(b.Time) })
+	var line []point
+	flush := func() {
+		c.stroke(decimate(line, width), 1.5, col)
+		line = line[:0]
+	}
+	for _, pt := range pts {
+		if math.IsNaN(pt.Value) {
+			flush()
+			continue
+		}
+		line = append(line, point{xOf(pt.Time), yOf(pt.Value)})
+	}
+	flush()
+}
+
+// decimate thins a line with many more points than width pixels, keeping
+// the lowest and highest point in each pixel column so peaks survive.
+func decimate(pts []point, width float64) []point {
+	if len(pts) <= int(2*width) {
+		return pts
+	}
+	var out []point
+	for i := 0; i < len(pts); {
+		col := math.Floor(pts[i].X)
+		lo, hi, j := i, i, i
+		for ; j < len(pts) && math.Floor(pts[j].X) == col; j++ {
+			if pts[j].Y < pts[lo].Y {
+				lo = j
+			}
+			if pts[j].Y > pts[hi].Y {
+				hi = j
+			}
+		}
+		out = append(out, pts[min(lo, hi)])
+		if lo != hi {
+			out = append(out, pts[max(lo, hi)])
+		}
+		i = j
+	}
+	return out
+}
+
+// crisp moves a coordinate to a pixel center, so that one pixel lines are
+// not blurred across two.
+func crisp(v float64) float64 {
+	return math.Floor(v) + 0.5
+}
@@ -0,0 +1,190 @@
+package chart
+
+import (
+	"image/color"
+	"math"
+)
+
+// WindSample is a wind observation: where it blew from, in degrees, and
+// how fast.
+type WindSample struct {
+	Direction, Speed float64
+}
+
+// WindRose shows how often the wind blew from each of 16 directions, split
+// into speed bands.
+type WindRose struct {
+	Samples []WindSample
+	// Unit names the speed unit in the legend, e.g. "mph".
+	Unit string
+	// Calm is the speed at or below which wind has no direction.
+	Calm float64
+	// Compass labels north, east, south and west; default N, E, S and W.
+	Compass [4]string
+	// CalmLabel names calm wind in the legend; default "Calm".
+	CalmLabel string
+	// Colors are the speed bands' colors, slowest first; nil uses a blue
+	// scale.
+	Colors []color.NRGBA
+}
+
+var roseColors = []color.NRGBA{
+	{0xc6, 0xdb, 0xef, 0xff}, {0x9e, 0xca, 0xe1, 0xff}, {0x6b, 0xae, 0xd6, 0xff},
+	{0x31, 0x82, 0xbd, 0xff}, {0x08, 0x51, 0x9c, 0xff}, {0x08, 0x30, 0x6b, 0xff},
+}
+
+const sectors = 16
+
+// bands returns the width of each speed band and how many there are, so
+// that about five bands cover the fastest sample.
+func (r WindRose) bands() (step float64, n, decimals int) {
+	fastest := 0.0
+	for _, s := range r.Samples {
+		fastest = math.Max(fastest, s.Speed)
+	}
+	if fastest <= 0 {
+		return 1, 1, 0
+	}
+	step = niceNum(fastest/5, false)
+	return step, max(1, int(math.Ceil(fastest/step))), max(0, int(-math.Floor(math.Log10(step))))
+}
+
+// frequencies returns the percentage of samples in each sector and speed
+// band, and the percentage that were calm.
+func (r WindRose) frequencies(step float64, n int) (freq [sectors][]float64, calm float64) {
+	for k := range freq {
+		freq[k] = make([]float64, n)
+	}
+	if len(r.Samples) == 0 {
+		return freq, 0
+	}
+	pct := 100 / float64(len(r.Samples))
+	for _, s := range r.Samples {
+		if s.Speed <= r.Calm {
+			calm += pct
+			continue
+		}
+		dir := math.Mod(s.Direction, 360)
+		if dir < 0 {
+			dir += 360
+		}
+		k := int(math.Round(dir/(360/sectors))) % sectors
+		freq[k][min(int(s.Speed/step), n-1)] += pct
+	}
+	return freq, calm
+}
+
+func (r WindRose) draw(c canvas, o *Options) {
+	w, h := float64(o.Width), float64(o.Height)
+	top := drawTitle(c, o, margin)
+	drawFooter(c, o, margin)
+	compass := r.Compass
+	if compass == [4]string{} {
+		compass = [4]string{"N", "E", "S", "W"}
+	}
+	calmLabel := r.CalmLabel
+	if calmLabel == "" {
+		calmLabel = "Calm"
+	}
+	step, n, decimals := r.bands()
+	colors := r.Colors
+	if len(colors) == 0 {
+		// Spread the bands over the whole scale.
+		colors = make([]color.NRGBA, n)
+		for b := range colors {
+			colors[b] = roseColors[(b+1)*len(roseColors)/(n+1)]
+		}
+	}
+	freq, calm := r.frequencies(step, n)
+	most := 0.0
+	for _, bands := range freq {
+		total := 0.0
+		for _, f := range bands {
+			total += f
+		}
+		most = math.Max(most, total)
+	}
+	_, ringMax, ringStep, ringDecimals := valueTicks(0, math.Max(most, 1), 5)
+
+	// Legend on the right: the speed bands, then calm.
+	labels := make([]string, n)
+	legendW := c.textWidth(r.Unit, labelSize, true)
+	for b := range labels {
+		labels[b] = o.Number(float64(b)*step, decimals) + "–" + o.Number(float64(b+1)*step, decimals)
+		legendW = math.Max(legendW, 18+c.textWidth(labels[b], smallSize, false))
+	}
+	calmText := calmLabel + " " + o.Number(calm, 1) + "%"
+	legendW = math.Max(legendW, c.textWidth(calmText, smallSize, false))
+
+	bottom := h - margin
+	if o.Footer != "" {
+		bottom -= footerRow
+	}
+	areaW := w - 2*margin - legendW - 20
+	radius := math.Min(areaW, bottom-top)/2 - 24
+	if radius < 10 {
+		radius = 10
+	}
+	cx, cy := margin+areaW/2, top+(bottom-top)/2
+	at := func(deg, r float64) point {
+		rad := deg * math.Pi / 180
+		return point{cx + r*math.Sin(rad), cy - r*math.Cos(rad)}
+	}
+	rOf := func(f float64) float64 { return f / ringMax * radius }
+
+	for k := 0; k < sectors; k++ {
+		c.stroke([]point{{cx, cy}, at(float64(k)*360/sectors, radius)}, 1, o.Palette.Grid)
+	}
+	for v := ringStep; v <= ringMax+ringStep/2; v += ringStep {
+		ring := make([]point, 73)
+		for i := range ring {
+			ring[i] = at(float64(i)*5, rOf(v))
+		}
+		c.stroke(ring, 1, o.Palette.Grid)
+	}
+
+	const half = 360 / sectors * 0.45
+	for k, bands := range freq {
+		center := float64(k) * 360 / sectors
+		inner := 0.0
+		for b, f := range bands {
+			if f == 0 {
+				continue
+			}
+			outer := inner + f
+			var wedge []point
+			for i := 0; i <= 6; i++ {
+				wedge = append(wedge, at(center-half+float64(i)*2*half/6, rOf(outer)))
+			}
+			for i := 6; i >= 0; i-- {
+				wedge = append(wedge, at(center-half+float64(i)*2*half/6, rOf(inner)))
+			}
+			c.fill(wedge, colors[min(b, len(colors)-1)])
+			inner = outer
+		}
+	}
+
+	// Ring labels sit on a translucent patch of background so they stay
+	// readable over the wedges.
+	halo := o.Palette.Background
+	halo.A = 0xc0
+	for v := ringStep; v <= ringMax+ringStep/2; v += ringStep {
+		p := at(60, rOf(v))
+		label := o.Number(v, ringDecimals) + "%"
+		c.fillRect(p.X+1, p.Y-13, c.textWidth(label, smallSize, false)+4, 14, halo)
+		c.text(p.X+3, p.Y-3, label, smallSize, false, anchorStart, o.Palette.Text)
+	}
+	c.text(cx, cy-radius-8, compass[0], labelSize, true, anchorMiddle, o.Palette.Text)
+	c.text(cx+radius+8, cy+4, compass[1], labelSize, true, anchorStart, o.Palette.Text)
+	c.text(cx, cy+radius+18, compass[2], labelSize, true, anchorMiddle, o.Palette.Text)
+	c.text(cx-radius-8, cy+4, compass[3], labelSize, true, anchorEnd, o.Palette.Text)
+
+	lx, ly := w-margin-legendW, top+labelSize
+	c.text(lx, ly, r.Unit, labelSize, true, anchorStart, o.Palette.Text)
+	for b, label := range labels {
+		ly += 20
+		c.fillRect(lx, ly-10, 12, 12, colors[min(b, len(colors)-1)])
+		c.text(lx+18, ly, label, smallSize, false, anchorStart, o.Palette.Text)
+	}
+	c.text(lx, ly+24, calmText, smallSize, false, anchorStart, o.Palette.Text)
+}
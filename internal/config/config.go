@@ -6,7 +6,9 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	"github.com/spf13/viper"
 )
 
@@ -17,11 +19,55 @@ type Config struct {
 	Stations       map[string]StationConfig `mapstructure:"stations" yaml:"stations"`
 	Tempestd       TempestdConfig           `mapstructure:"tempestd" yaml:"tempestd,omitempty"`
 	ServerURL      string                   `mapstructure:"server" yaml:"server,omitempty"` // flat alias for backward compat
+	// StaleAfter is the default staleness threshold for all stations.
+	StaleAfter time.Duration `mapstructure:"stale_after" yaml:"stale_after,omitempty"`
+	// Unit overrides the unit system per quantity, e.g. wind: kn.
+	Unit units.Set `mapstructure:"unit" yaml:"unit,omitempty"`
+	// Theme is a built-in theme name, a theme in the themes directory next to
+	// the config file, or a path to a theme file.
+	Theme string `mapstructure:"theme" yaml:"theme,omitempty"`
+	// Lang selects the language and number, date and time formats, e.g. "de"
+	// or "en-GB". Empty means LC_ALL, LC_MESSAGES or LANG.
+	Lang string `mapstructure:"lang" yaml:"lang,omitempty"`
+	// Groups name lists of stations, e.g. mountains: [cabin, summit], so
+	// that --station can show several at once.
+	Groups map[string][]string `mapstructure:"groups" yaml:"groups,omitempty"`
 }
 
+// DefaultStaleAfter is how old a station's latest observation may be before
+// the station is reported offline, unless stale_after is configured.
+const DefaultStaleAfter = 30 * time.Minute
+
 // TempestdConfig holds tempestd daemon settings.
 type TempestdConfig struct {
 	Server string `mapstructure:"server" yaml:"server,omitempty"`
+
+	// Token is sent as a bearer token. Username and Password enable HTTP basic
+	// auth instead; the two are mutually exclusive.
+	Token    string `mapstructure:"token" yaml:"token,omitempty"`
+	Username string `mapstructure:"username" yaml:"username,omitempty"`
+	Password string `mapstructure:"password" yaml:"password,omitempty"`
+
+	// CAFile is a PEM bundle trusted in addition to the system roots.
+	// CertFile and KeyFile present a client certificate for mTLS.
+	CAFile             string `mapstructure:"ca_file" yaml:"ca_file,omitempty"`
+	CertFile           string `mapstructure:"cert_file" yaml:"cert_file,omitempty"`
+	KeyFile            string `mapstructure:"key_file" yaml:"key_file,omitempty"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify" yaml:"insecure_skip_verify,omitempty"`
+}
+
+// Validate checks that the auth and TLS settings are consistent.
+func (t *TempestdConfig) Validate() error {
+	if t.Token != "" && (t.Username != "" || t.Password != "") {
+		return fmt.Errorf("tempestd: set either token or username/password, not both")
+	}
+	if t.Password != "" && t.Username == "" {
+		return fmt.Errorf("tempestd: password is set but username is empty")
+	}
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("tempestd: cert_file and key_file must be set together")
+	}
+	return nil
 }
 
 // EffectiveServerURL returns the server URL from tempestd.server or the flat server key.
@@ -34,21 +80,45 @@ func (c *Config) EffectiveServerURL() string {
 
 // StationConfig holds per-station settings.
 type StationConfig struct {
-	Token     string `mapstructure:"token" yaml:"token"`
+	Token string `mapstructure:"token" yaml:"token"`
+	// TokenFile and TokenCommand read the token from a file or from a
+	// command's stdout instead. At most one of the three may be set.
+	TokenFile    string `mapstructure:"token_file" yaml:"token_file,omitempty"`
+	TokenCommand string `mapstructure:"token_command" yaml:"token_command,omitempty"`
+	// TokenEnv names the environment variable that overrode Token, if any.
+	TokenEnv string `mapstructure:"-" yaml:"-"`
+
 	StationID int    `mapstructure:"station_id" yaml:"station_id"`
 	DeviceID  int    `mapstructure:"device_id" yaml:"device_id"`
 	Name      string `mapstructure:"name" yaml:"name"`
+	// Serial is the sensor's serial number, e.g. ST-00012345, which picks
+	// out the station's UDP broadcasts. If empty it is taken from the
+	// station metadata.
+	Serial string `mapstructure:"serial" yaml:"serial,omitempty"`
+	// StaleAfter overrides the global stale_after for this station.
+	StaleAfter time.Duration `mapstructure:"stale_after" yaml:"stale_after,omitempty"`
+	// Elevation in meters overrides the station metadata's elevation when
+	// reducing station pressure to sea level.
+	Elevation *float64 `mapstructure:"elevation" yaml:"elevation,omitempty"`
+	// Timezone is the station's IANA time zone, e.g. "America/Denver", for
+	// charts. If empty it is looked up from the cloud API.
+	Timezone string `mapstructure:"timezone" yaml:"timezone,omitempty"`
+	// Units and Unit override the global units and unit for this station.
+	Units string    `mapstructure:"units" yaml:"units,omitempty"`
+	Unit  units.Set `mapstructure:"unit" yaml:"unit,omitempty"`
 }
 
 // Load reads the merged config from viper into a Config struct.
 // It also applies TEMPEST_TOKEN, TEMPEST_STATION_ID, and TEMPEST_DEVICE_ID env vars
-// as overrides for the default station.
+// as overrides for the default station, then the per-station
+// TEMPEST_STATIONS_<NAME>_* overrides.
 func Load() (*Config, error) {
 	var cfg Config
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
 	applyEnvOverrides(&cfg)
+	applyStationEnvOverrides(&cfg)
 	return &cfg, nil
 }
 
@@ -76,6 +146,7 @@ func applyEnvOverrides(cfg *Config) {
 	sc := cfg.Stations[name]
 	if token != "" {
 		sc.Token = token
+		sc.TokenEnv = "TEMPEST_TOKEN"
 	}
 	if stationIDStr != "" {
 		if id, err := strconv.Atoi(stationIDStr); err == nil {
@@ -95,15 +166,57 @@ func (c *Config) Validate() error {
 	if len(c.Stations) == 0 {
 		return fmt.Errorf("no stations configured; run 'tempest config init' to set up")
 	}
+	return c.ValidateSettings()
+}
+
+// ValidateSettings checks everything Validate does except that at least one
+// station is configured.
+func (c *Config) ValidateSettings() error {
 	if c.DefaultStation != "" {
 		if _, ok := c.Stations[c.DefaultStation]; !ok {
 			return fmt.Errorf("default_station %q not found in stations", c.DefaultStation)
 		}
 	}
-	if c.Units != "" && c.Units != "metric" && c.Units != "imperial" {
-		return fmt.Errorf("units must be 'metric' or 'imperial', got %q", c.Units)
+	if _, err := units.System(c.Units); err != nil {
+		return err
 	}
-	return nil
+	if _, err := c.Unit.Normalize(); err != nil {
+		return fmt.Errorf("unit: %w", err)
+	}
+	if c.StaleAfter < 0 {
+		return fmt.Errorf("stale_after must not be negative, got %s", c.StaleAfter)
+	}
+	for _, name := range c.StationNames() {
+		sc := c.Stations[name]
+		if sc.StaleAfter < 0 {
+			return fmt.Errorf("station %q: stale_after must not be negative, got %s", name, sc.StaleAfter)
+		}
+		if err := sc.validateTokenSources(); err != nil {
+			return fmt.Errorf("station %q: %w", name, err)
+		}
+		if sc.Timezone != "" {
+			if _, err := time.LoadLocation(sc.Timezone); err != nil {
+				return fmt.Errorf("station %q: unknown timezone %q", name, sc.Timezone)
+			}
+		}
+		if _, err := c.UnitsFor(&sc); err != nil {
+			return fmt.Errorf("station %q: %w", name, err)
+		}
+	}
+	for _, group := range c.GroupNames() {
+		if _, ok := c.Stations[group]; ok {
+			return fmt.Errorf("group %q has the same name as a station", group)
+		}
+		if len(c.Groups[group]) == 0 {
+			return fmt.Errorf("group %q has no stations", group)
+		}
+		for _, name := range c.Groups[group] {
+			if _, ok := c.Stations[name]; !ok {
+				return fmt.Errorf("group %q: station %q not found in stations", group, name)
+			}
+		}
+	}
+	return c.Tempestd.Validate()
 }
 
 // ResolveStation returns the StationConfig for the given name, or the default.
@@ -129,6 +242,57 @@ func (c *Config) ResolveStation(name string) (*StationConfig, error) {
 	return &sc, nil
 }
 
+// IsStationList reports whether spec names more than a single station: a
+// comma-separated list or a group.
+func (c *Config) IsStationList(spec string) bool {
+	_, group := c.Groups[spec]
+	return group || strings.Contains(spec, ",")
+}
+
+// ResolveStationList returns the station names in spec, a comma-separated
+// list of station and group names, in order and without repeats.
+func (c *Config) ResolveStationList(spec string) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if members, ok := c.Groups[part]; ok {
+			for _, name := range members {
+				add(name)
+			}
+			continue
+		}
+		if _, ok := c.Stations[part]; !ok {
+			available := append(c.StationNames(), c.GroupNames()...)
+			return nil, fmt.Errorf("station or group %q not found; available: %s", part, strings.Join(available, ", "))
+		}
+		add(part)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no stations in %q", spec)
+	}
+	return names, nil
+}
+
+// GroupNames returns sorted group names.
+func (c *Config) GroupNames() []string {
+	names := make([]string, 0, len(c.Groups))
+	for name := range c.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // StationNames returns sorted station names.
 func (c *Config) StationNames() []string {
 	names := make([]string, 0, len(c.Stations))
@@ -139,11 +303,50 @@ func (c *Config) StationNames() []string {
 	return names
 }
 
+// StaleThreshold returns the staleness threshold for sc: its own stale_after,
+// then the global stale_after, then DefaultStaleAfter.
+func (c *Config) StaleThreshold(sc *StationConfig) time.Duration {
+	switch {
+	case sc != nil && sc.StaleAfter > 0:
+		return sc.StaleAfter
+	case c.StaleAfter > 0:
+		return c.StaleAfter
+	default:
+		return DefaultStaleAfter
+	}
+}
+
 // IsImperial returns true if the configured units are imperial.
 func (c *Config) IsImperial() bool {
 	return strings.EqualFold(c.Units, "imperial")
 }
 
+// UnitsFor returns the units to show sc's data in. The station's units, or
+// else the global units, pick the system; the global unit overrides apply
+// on top, then the station's. sc may be nil.
+func (c *Config) UnitsFor(sc *StationConfig) (units.Set, error) {
+	system, override := c.Units, units.Set{}
+	if sc != nil {
+		if sc.Units != "" {
+			system = sc.Units
+		}
+		override = sc.Unit
+	}
+	u, err := units.System(system)
+	if err != nil {
+		return units.Set{}, err
+	}
+	global, err := c.Unit.Normalize()
+	if err != nil {
+		return units.Set{}, fmt.Errorf("unit: %w", err)
+	}
+	override, err = override.Normalize()
+	if err != nil {
+		return units.Set{}, fmt.Errorf("unit: %w", err)
+	}
+	return u.With(global).With(override), nil
+}
+
 // RedactToken returns a token with the first 4 characters visible and 4 stars.
 func RedactToken(token string) string {
 	if len(token) <= 4 {
@@ -4,7 +4,9 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	"github.com/spf13/viper"
 )
 
@@ -188,6 +190,40 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "negative station stale_after",
+			cfg: Config{
+				Stations: map[string]StationConfig{
+					"home": {Token: "tok", StationID: 1, StaleAfter: -time.Minute},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown station timezone",
+			cfg: Config{
+				Stations: map[string]StationConfig{
+					"home": {Token: "tok", StationID: 1, Timezone: "Mars/Olympus_Mons"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "group named like a station",
+			cfg: Config{
+				Stations: map[string]StationConfig{"home": {Token: "tok", StationID: 1}},
+				Groups:   map[string][]string{"home": {"home"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "group with unknown station",
+			cfg: Config{
+				Stations: map[string]StationConfig{"home": {Token: "tok", StationID: 1}},
+				Groups:   map[string][]string{"all": {"home", "office"}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "empty units is valid",
 			cfg: Config{
@@ -209,6 +245,83 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestTempestdValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     TempestdConfig
+		wantErr bool
+	}{
+		{name: "empty", cfg: TempestdConfig{}},
+		{name: "token", cfg: TempestdConfig{Token: "tok"}},
+		{name: "basic auth", cfg: TempestdConfig{Username: "u", Password: "p"}},
+		{name: "username only", cfg: TempestdConfig{Username: "u"}},
+		{name: "token and basic", cfg: TempestdConfig{Token: "tok", Username: "u", Password: "p"}, wantErr: true},
+		{name: "password without username", cfg: TempestdConfig{Password: "p"}, wantErr: true},
+		{name: "cert and key", cfg: TempestdConfig{CertFile: "c.pem", KeyFile: "k.pem"}},
+		{name: "cert without key", cfg: TempestdConfig{CertFile: "c.pem"}, wantErr: true},
+		{name: "key without cert", cfg: TempestdConfig{KeyFile: "k.pem"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStaleThreshold(t *testing.T) {
+	tests := []struct {
+		name   string
+		global time.Duration
+		sc     *StationConfig
+		want   time.Duration
+	}{
+		{name: "default", sc: &StationConfig{}, want: DefaultStaleAfter},
+		{name: "global", global: time.Hour, sc: &StationConfig{}, want: time.Hour},
+		{name: "station overrides global", global: time.Hour, sc: &StationConfig{StaleAfter: 10 * time.Minute}, want: 10 * time.Minute},
+		{name: "nil station", global: 2 * time.Hour, want: 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{StaleAfter: tt.global}
+			if got := cfg.StaleThreshold(tt.sc); got != tt.want {
+				t.Errorf("StaleThreshold() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadStaleAfter(t *testing.T) {
+	clearTempestEnv(t)
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigType("yaml")
+	if err := viper.ReadConfig(strings.NewReader(`
+stale_after: 1h
+stations:
+  cabin:
+    token: tok
+    station_id: 1
+    stale_after: 6h
+`)); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.StaleAfter != time.Hour {
+		t.Errorf("StaleAfter = %v, want 1h", cfg.StaleAfter)
+	}
+	if got := cfg.Stations["cabin"].StaleAfter; got != 6*time.Hour {
+		t.Errorf("cabin StaleAfter = %v, want 6h", got)
+	}
+}
+
 func TestResolveStation(t *testing.T) {
 	cfg := &Config{
 		DefaultStation: "home",
@@ -258,6 +371,43 @@ func TestResolveStation(t *testing.T) {
 	}
 }
 
+func TestResolveStationList(t *testing.T) {
+	cfg := &Config{
+		Stations: map[string]StationConfig{
+			"home":   {StationID: 1},
+			"office": {StationID: 2},
+			"cabin":  {StationID: 3},
+		},
+		Groups: map[string][]string{"work": {"office", "home"}},
+	}
+	tests := []struct {
+		spec    string
+		list    bool
+		want    string
+		wantErr bool
+	}{
+		{spec: "home", want: "home"},
+		{spec: "cabin, home", list: true, want: "cabin,home"},
+		{spec: "work", list: true, want: "office,home"},
+		{spec: "home,work,cabin", list: true, want: "home,office,cabin"},
+		{spec: "home,nowhere", list: true, wantErr: true},
+		{spec: " , ", list: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		if got := cfg.IsStationList(tt.spec); got != tt.list {
+			t.Errorf("IsStationList(%q) = %v, want %v", tt.spec, got, tt.list)
+		}
+		names, err := cfg.ResolveStationList(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ResolveStationList(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if got := strings.Join(names, ","); !tt.wantErr && got != tt.want {
+			t.Errorf("ResolveStationList(%q) = %s, want %s", tt.spec, got, tt.want)
+		}
+	}
+}
+
 func TestRedactToken(t *testing.T) {
 	tests := []struct {
 		input string
@@ -375,6 +525,40 @@ func TestEffectiveServerURL(t *testing.T) {
 	}
 }
 
+func TestUnitsFor(t *testing.T) {
+	cfg := &Config{
+		Units: "metric",
+		Unit:  units.Set{Wind: "km/h", Pressure: "mb"},
+		Stations: map[string]StationConfig{
+			"home":  {},
+			"cabin": {Units: "imperial", Unit: units.Set{Wind: "knots"}},
+		},
+	}
+
+	tests := []struct {
+		station string
+		want    units.Set
+	}{
+		{"home", units.Set{Temperature: "c", Wind: "km/h", Pressure: "mb", Precipitation: "mm", Distance: "km"}},
+		{"cabin", units.Set{Temperature: "f", Wind: "kn", Pressure: "mb", Precipitation: "in", Distance: "mi"}},
+	}
+	for _, tt := range tests {
+		sc := cfg.Stations[tt.station]
+		got, err := cfg.UnitsFor(&sc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("UnitsFor(%s) = %+v, want %+v", tt.station, got, tt.want)
+		}
+	}
+
+	cfg.Stations["bad"] = StationConfig{Unit: units.Set{Pressure: "psi"}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "psi") {
+		t.Errorf("Validate() = %v, want an error naming the bad unit", err)
+	}
+}
+
 func TestIsImperial(t *testing.T) {
 	if (&Config{Units: "imperial"}).IsImperial() != true {
 		t.Error("expected imperial")
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// File is a config file opened for editing. Changes are made to the parsed
+// YAML node tree, so comments and key order survive a round trip.
+type File struct {
+	Path string
+	doc  *yaml.Node
+}
+
+// DefaultPath returns ~/.config/tempest/config.yaml.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "tempest", "config.yaml"), nil
+}
+
+// OpenFile reads the config file at path. A missing file yields an empty
+// document that Save will create.
+func OpenFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		data = nil
+	} else if err != nil {
+		return nil, err
+	}
+	return ParseFile(path, data)
+}
+
+// ParseFile parses data as the contents of the config file at path.
+func ParseFile(path string, data []byte) (*File, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parsing %s: top level must be a mapping", path)
+	}
+	return &File{Path: path, doc: &doc}, nil
+}
+
+func (f *File) root() *yaml.Node { return f.doc.Content[0] }
+
+// Get returns the node at a dotted key such as "stations.home.token".
+func (f *File) Get(key string) (*yaml.Node, bool) {
+	n := f.root()
+	for _, part := range strings.Split(key, ".") {
+		if n.Kind != yaml.MappingNode {
+			return nil, false
+		}
+		if n = mappingValue(n, part); n == nil {
+			return nil, false
+		}
+	}
+	return n, true
+}
+
+// Set stores value at a dotted key, creating intermediate sections as
+// needed. An existing scalar keeps its comments and position.
+func (f *File) Set(key, value string) error {
+	parts := strings.Split(key, ".")
+	n := f.root()
+	for i, part := range parts {
+		if part == "" {
+			return fmt.Errorf("invalid key %q", key)
+		}
+		child := mappingValue(n, part)
+		last := i == len(parts)-1
+		switch {
+		case child == nil && last:
+			n.Content = append(n.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part},
+				scalarNode(value))
+			return nil
+		case child == nil:
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part}, child)
+		case last && child.Kind != yaml.ScalarNode:
+			return fmt.Errorf("%s is a section; set its keys individually", key)
+		case last:
+			s := scalarNode(value)
+			child.Tag, child.Value = s.Tag, s.Value
+			if s.Tag != "!!str" {
+				child.Style = 0
+			}
+			return nil
+		case child.Kind != yaml.MappingNode:
+			return fmt.Errorf("%s is not a section", strings.Join(parts[:i+1], "."))
+		}
+		n = child
+	}
+	return nil
+}
+
+// Delete removes the dotted key and reports whether it existed.
+func (f *File) Delete(key string) bool {
+	parts := strings.Split(key, ".")
+	parent := f.root()
+	if len(parts) > 1 {
+		var ok bool
+		if parent, ok = f.Get(strings.Join(parts[:len(parts)-1], ".")); !ok || parent.Kind != yaml.MappingNode {
+			return false
+		}
+	}
+	name := parts[len(parts)-1]
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == name {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Bytes encodes the document as YAML.
+func (f *File) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(f.doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Config decodes the document the same way Load reads the config file,
+// without environment overrides.
+func (f *File) Config() (*Config, error) {
+	data, err := f.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Save validates the document and writes it atomically with 0600
+// permissions. A config with no stations is allowed so that settings can be
+// written before the first station is added.
+func (f *File) Save() error {
+	cfg, err := f.Config()
+	if err != nil {
+		return err
+	}
+	if err := cfg.ValidateSettings(); err != nil {
+		return err
+	}
+	data, err := f.Bytes()
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(f.Path, data)
+}
+
+// Parse decodes YAML config data into a Config.
+func Parse(data []byte) (*Config, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// WriteFileAtomic writes data to a temporary file next to path and renames it
+// into place, so readers never see a partial config. The file is created
+// with 0600 permissions since it holds API tokens.
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".config-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err := tmp.Chmod(0600); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalarNode returns a node for value, typed as a bool or int when it looks
+// like one so that the file reads naturally.
+func scalarNode(value string) *yaml.Node {
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if value == "true" || value == "false" {
+		n.Tag = "!!bool"
+	} else if _, err := strconv.Atoi(value); err == nil {
+		n.Tag = "!!int"
+	}
+	return n
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const commentedConfig = `# Tempest CLI config
+default_station: home
+units: imperial # or metric
+
+stations:
+  # my backyard station
+  home:
+    token: "abc123"
+    station_id: 12345
+`
+
+func TestFileSetPreservesComments(t *testing.T) {
+	f, err := ParseFile("config.yaml", []byte(commentedConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Set("units", "metric"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Set("stations.home.device_id", "67890"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Set("tempestd.server", "http://localhost:8080"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := f.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"# Tempest CLI config",
+		"units: metric # or metric",
+		"# my backyard station",
+		"device_id: 67890",
+		"tempestd:\n  server: http://localhost:8080",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "default_station") > strings.Index(out, "stations:") {
+		t.Errorf("key order changed:\n%s", out)
+	}
+
+	cfg, err := f.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Units != "metric" || cfg.Stations["home"].DeviceID != 67890 || cfg.Tempestd.Server != "http://localhost:8080" {
+		t.Errorf("decoded config = %+v", cfg)
+	}
+}
+
+func TestFileSetErrors(t *testing.T) {
+	f, err := ParseFile("config.yaml", []byte(commentedConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Set("stations", "x"); err == nil {
+		t.Error("expected error replacing a section with a scalar")
+	}
+	if err := f.Set("units.extra", "x"); err == nil {
+		t.Error("expected error setting a key under a scalar")
+	}
+	if err := f.Set("stations..token", "x"); err == nil {
+		t.Error("expected error for an empty key segment")
+	}
+}
+
+func TestFileDelete(t *testing.T) {
+	f, err := ParseFile("config.yaml", []byte(commentedConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Delete("stations.home") {
+		t.Fatal("Delete returned false for an existing key")
+	}
+	if _, ok := f.Get("stations.home"); ok {
+		t.Error("stations.home still present")
+	}
+	if f.Delete("stations.missing") || f.Delete("nope.key") {
+		t.Error("Delete returned true for a missing key")
+	}
+}
+
+func TestFileSaveAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tempest", "config.yaml")
+	f, err := OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Set("units", "metric"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %04o, want 0600", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+
+	if err := f.Set("units", "kelvin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Save(); err == nil {
+		t.Error("expected validation error for invalid units")
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "units: metric") {
+		t.Errorf("invalid save modified the file:\n%s", data)
+	}
+}
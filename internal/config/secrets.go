@@ -0,0 +1,175 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenCommandTimeout bounds how long a token_command may run.
+const TokenCommandTimeout = 30 * time.Second
+
+// commandTokens memoizes token_command output so a command that prompts (or
+// is slow) runs at most once per process.
+var commandTokens = struct {
+	sync.Mutex
+	m map[string]string
+}{m: make(map[string]string)}
+
+// StationEnvPrefix returns the prefix of the environment variables that
+// override a station's settings, e.g. TEMPEST_STATIONS_BACK_YARD_ for
+// "back-yard".
+func StationEnvPrefix(name string) string {
+	upper := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	return "TEMPEST_STATIONS_" + upper + "_"
+}
+
+// applyStationEnvOverrides applies TEMPEST_STATIONS_<NAME>_TOKEN,
+// _STATION_ID and _DEVICE_ID to each configured station. They take
+// precedence over TEMPEST_TOKEN and friends.
+func applyStationEnvOverrides(cfg *Config) {
+	for name, sc := range cfg.Stations {
+		prefix := StationEnvPrefix(name)
+		if token := os.Getenv(prefix + "TOKEN"); token != "" {
+			sc.Token = token
+			sc.TokenEnv = prefix + "TOKEN"
+		}
+		if id, err := strconv.Atoi(os.Getenv(prefix + "STATION_ID")); err == nil {
+			sc.StationID = id
+		}
+		if id, err := strconv.Atoi(os.Getenv(prefix + "DEVICE_ID")); err == nil {
+			sc.DeviceID = id
+		}
+		cfg.Stations[name] = sc
+	}
+}
+
+// validateTokenSources rejects a station that sets more than one of token,
+// token_file and token_command in the config file. A token from the
+// environment overrides the others and is not counted.
+func (sc *StationConfig) validateTokenSources() error {
+	var set []string
+	if sc.Token != "" && sc.TokenEnv == "" {
+		set = append(set, "token")
+	}
+	if sc.TokenFile != "" {
+		set = append(set, "token_file")
+	}
+	if sc.TokenCommand != "" {
+		set = append(set, "token_command")
+	}
+	if len(set) > 1 {
+		return fmt.Errorf("set only one of token, token_file and token_command, got %s", strings.Join(set, " and "))
+	}
+	return nil
+}
+
+// TokenSource describes where the station's token comes from without
+// revealing it: "env TEMPEST_STATIONS_HOME_TOKEN", "config", "file <path>",
+// "command <command>", or "none".
+func (sc *StationConfig) TokenSource() string {
+	switch {
+	case sc.TokenEnv != "":
+		return "env " + sc.TokenEnv
+	case sc.Token != "":
+		return "config"
+	case sc.TokenFile != "":
+		return "file " + sc.TokenFile
+	case sc.TokenCommand != "":
+		return "command " + sc.TokenCommand
+	default:
+		return "none"
+	}
+}
+
+// HasToken reports whether any token source is configured.
+func (sc *StationConfig) HasToken() bool {
+	return sc.Token != "" || sc.TokenFile != "" || sc.TokenCommand != ""
+}
+
+// APIToken returns the station's token, reading token_file or running
+// token_command when the token is not set directly. Errors never include
+// the token.
+func (sc *StationConfig) APIToken(ctx context.Context) (string, error) {
+	switch {
+	case sc.Token != "":
+		return sc.Token, nil
+	case sc.TokenFile != "":
+		return readTokenFile(sc.TokenFile)
+	case sc.TokenCommand != "":
+		return runTokenCommand(ctx, sc.TokenCommand)
+	default:
+		return "", errors.New("no token configured")
+	}
+}
+
+func readTokenFile(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("token_file: %w", err)
+		}
+		path = filepath.Join(home, rest)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("token_file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token_file %s is empty", path)
+	}
+	return token, nil
+}
+
+func runTokenCommand(ctx context.Context, command string) (string, error) {
+	commandTokens.Lock()
+	defer commandTokens.Unlock()
+	if token, ok := commandTokens.m[command]; ok {
+		return token, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, TokenCommandTimeout)
+	defer cancel()
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout bytes.Buffer
+	c.Stdout = &stdout
+	c.Stdin = os.Stdin
+	// Pass stderr through so password prompts and helper errors are visible.
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("token_command timed out after %s", TokenCommandTimeout)
+		}
+		return "", fmt.Errorf("token_command failed: %w", err)
+	}
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", errors.New("token_command printed nothing")
+	}
+	commandTokens.m[command] = token
+	return token, nil
+}
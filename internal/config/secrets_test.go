@@ -0,0 +1,142 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestStationEnvPrefix(t *testing.T) {
+	tests := map[string]string{
+		"home":      "TEMPEST_STATIONS_HOME_",
+		"back-yard": "TEMPEST_STATIONS_BACK_YARD_",
+		"Cabin2":    "TEMPEST_STATIONS_CABIN2_",
+	}
+	for name, want := range tests {
+		if got := StationEnvPrefix(name); got != want {
+			t.Errorf("StationEnvPrefix(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestApplyStationEnvOverrides(t *testing.T) {
+	t.Setenv("TEMPEST_TOKEN", "default-token")
+	t.Setenv("TEMPEST_STATIONS_HOME_TOKEN", "home-token")
+	t.Setenv("TEMPEST_STATIONS_BACK_YARD_STATION_ID", "42")
+
+	cfg := &Config{
+		DefaultStation: "home",
+		Stations: map[string]StationConfig{
+			"home":      {Token: "file-token", StationID: 1},
+			"back-yard": {TokenCommand: "echo x", StationID: 2},
+		},
+	}
+	applyEnvOverrides(cfg)
+	applyStationEnvOverrides(cfg)
+
+	home := cfg.Stations["home"]
+	if home.Token != "home-token" || home.TokenSource() != "env TEMPEST_STATIONS_HOME_TOKEN" {
+		t.Errorf("home token = %q from %q, want the per-station override", home.Token, home.TokenSource())
+	}
+	yard := cfg.Stations["back-yard"]
+	if yard.StationID != 42 {
+		t.Errorf("back-yard station_id = %d, want 42", yard.StationID)
+	}
+	if yard.TokenSource() != "command echo x" {
+		t.Errorf("back-yard token source = %q", yard.TokenSource())
+	}
+}
+
+func TestValidateTokenSources(t *testing.T) {
+	tests := []struct {
+		name    string
+		sc      StationConfig
+		wantErr bool
+	}{
+		{"token", StationConfig{Token: "a"}, false},
+		{"file", StationConfig{TokenFile: "/t"}, false},
+		{"token and command", StationConfig{Token: "a", TokenCommand: "x"}, true},
+		{"file and command", StationConfig{TokenFile: "/t", TokenCommand: "x"}, true},
+		{"env over file", StationConfig{Token: "a", TokenEnv: "TEMPEST_TOKEN", TokenFile: "/t"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Stations: map[string]StationConfig{"home": tt.sc}}
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAPIToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		sc      StationConfig
+		want    string
+		wantErr string
+	}{
+		{"token", StationConfig{Token: "plain"}, "plain", ""},
+		{"file", StationConfig{TokenFile: tokenFile}, "file-secret", ""},
+		{"empty file", StationConfig{TokenFile: emptyFile}, "", "is empty"},
+		{"missing file", StationConfig{TokenFile: filepath.Join(dir, "nope")}, "", "token_file"},
+		{"command", StationConfig{TokenCommand: "echo cmd-secret"}, "cmd-secret", ""},
+		{"command prints nothing", StationConfig{TokenCommand: "echo"}, "", "printed nothing"},
+		{"command fails", StationConfig{TokenCommand: "exit 3"}, "", "token_command failed"},
+		{"none", StationConfig{}, "", "no token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.sc.TokenCommand != "" && runtime.GOOS == "windows" {
+				t.Skip("token_command tests use POSIX shell syntax")
+			}
+			got, err := tt.sc.APIToken(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("token = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPITokenCommandRunsOnce(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell syntax")
+	}
+	counter := filepath.Join(t.TempDir(), "runs")
+	sc := StationConfig{TokenCommand: "echo run >> " + counter + " && echo once-secret"}
+	for i := 0; i < 3; i++ {
+		if _, err := sc.APIToken(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs := strings.Count(string(data), "run"); runs != 1 {
+		t.Errorf("command ran %d times, want 1", runs)
+	}
+}
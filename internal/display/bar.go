@@ -0,0 +1,98 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
+	"github.com/chadmayfield/tempest-cli/internal/units"
+	tempest "github.com/chadmayfield/tempest-go"
+)
+
+// AlertLevel classifies current conditions for status bar styling.
+type AlertLevel string
+
+const (
+	AlertNormal   AlertLevel = "normal"
+	AlertWarning  AlertLevel = "warning"
+	AlertCritical AlertLevel = "critical"
+)
+
+// Alert thresholds (metric). Wind values follow the Beaufort scale:
+// 10.8 m/s is a strong breeze, 17.2 m/s a gale.
+const (
+	alertNearLightningKm = 10.0
+	alertGustWarning     = 10.8
+	alertGustCritical    = 17.2
+	alertHeatWarning     = 32.0
+	alertHeatCritical    = 40.0
+	alertColdWarning     = 0.0
+	alertColdCritical    = -25.0
+	alertUVWarning       = 8.0
+	alertUVCritical      = 11.0
+)
+
+// ConditionsAlert returns the most severe alert level for an observation.
+func ConditionsAlert(obs *tempest.StationObservation) AlertLevel {
+	switch {
+	case obs.LightningCount3hr > 0 && obs.LightningStrikeLastDistance > 0 && obs.LightningStrikeLastDistance <= alertNearLightningKm,
+		obs.WindGust >= alertGustCritical,
+		obs.AirTemperature >= alertHeatCritical,
+		obs.AirTemperature <= alertColdCritical,
+		obs.UV >= alertUVCritical:
+		return AlertCritical
+	case obs.LightningCount3hr > 0,
+		obs.WindGust >= alertGustWarning,
+		obs.AirTemperature >= alertHeatWarning,
+		obs.AirTemperature <= alertColdWarning,
+		obs.UV >= alertUVWarning:
+		return AlertWarning
+	default:
+		return AlertNormal
+	}
+}
+
+// RenderBarText renders a compact single line suitable for tmux or a status bar.
+func RenderBarText(obs *tempest.StationObservation, u units.Set, l *i18n.Locale, noEmoji bool) string {
+	parts := []string{
+		FormatTemp(obs.AirTemperature, u, l),
+		FormatWind(obs.WindAvg, u, l) + " " + l.CompassPoint(obs.WindDirection),
+		fmt.Sprintf("%.0f%%", obs.RelativeHumidity),
+	}
+	if obs.PrecipAccumDay > 0 {
+		parts = append(parts, FormatPrecip(obs.PrecipAccumDay, u, l))
+	}
+	if obs.LightningCount3hr > 0 {
+		if noEmoji {
+			parts = append(parts, fmt.Sprintf("[ltg %d]", obs.LightningCount3hr))
+		} else {
+			parts = append(parts, fmt.Sprintf("⚡%d", obs.LightningCount3hr))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// RenderBarShortText renders the shortest useful summary (temperature only).
+func RenderBarShortText(obs *tempest.StationObservation, u units.Set, l *i18n.Locale) string {
+	return FormatTemp(obs.AirTemperature, u, l)
+}
+
+// RenderBarTooltip renders a plain multi-line summary for status bar tooltips.
+func RenderBarTooltip(obs *tempest.StationObservation, stationName string, u units.Set, l *i18n.Locale, fetchedAt time.Time) string {
+	lines := []string{
+		stationName,
+		l.T("tooltip_temperature", FormatTemp(obs.AirTemperature, u, l), FormatTemp(obs.FeelsLike, u, l)),
+		l.T("tooltip_humidity", fmt.Sprintf("%.0f%%", obs.RelativeHumidity), FormatTemp(obs.DewPoint, u, l)),
+		l.T("tooltip_wind", formatWindFull(obs.WindAvg, obs.WindDirection, u, l), FormatWind(obs.WindGust, u, l)),
+		l.T("tooltip_pressure", pressureRow(obs.SeaLevelPressure, u, l).text),
+		l.T("tooltip_uv", l.Number(obs.UV, 1), l.T(uvLevel(obs.UV))),
+		l.T("tooltip_rain", FormatPrecip(obs.PrecipAccumDay, u, l)),
+		l.T("tooltip_lightning", formatLightning(obs.LightningCount3hr, obs.LightningStrikeLastDistance, u, l)),
+		l.T("observed_ago", timeAgo(obs.Timestamp, l)),
+	}
+	if !fetchedAt.IsZero() {
+		lines = append(lines, l.T("fetched_ago", timeAgo(fetchedAt, l)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,75 @@
+package display
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
+	"github.com/chadmayfield/tempest-cli/internal/units"
+	tempest "github.com/chadmayfield/tempest-go"
+)
+
+func TestConditionsAlert(t *testing.T) {
+	tests := []struct {
+		name string
+		obs  tempest.StationObservation
+		want AlertLevel
+	}{
+		{"calm", tempest.StationObservation{AirTemperature: 20, WindGust: 3, UV: 4}, AlertNormal},
+		{"freezing", tempest.StationObservation{AirTemperature: -2}, AlertWarning},
+		{"distant lightning", tempest.StationObservation{AirTemperature: 20, LightningCount3hr: 2, LightningStrikeLastDistance: 30}, AlertWarning},
+		{"near lightning", tempest.StationObservation{AirTemperature: 20, LightningCount3hr: 2, LightningStrikeLastDistance: 5}, AlertCritical},
+		{"strong breeze", tempest.StationObservation{AirTemperature: 20, WindGust: 12}, AlertWarning},
+		{"gale", tempest.StationObservation{AirTemperature: 20, WindGust: 20}, AlertCritical},
+		{"extreme heat", tempest.StationObservation{AirTemperature: 43}, AlertCritical},
+		{"extreme UV", tempest.StationObservation{AirTemperature: 25, UV: 11.5}, AlertCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConditionsAlert(&tt.obs); got != tt.want {
+				t.Errorf("ConditionsAlert() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderBarText(t *testing.T) {
+	obs := &tempest.StationObservation{
+		AirTemperature:   22.5,
+		RelativeHumidity: 65,
+		WindAvg:          3.5,
+		WindDirection:    180,
+	}
+	got := RenderBarText(obs, units.MetricSet, i18n.English, false)
+	if got != "22.5°C · 3.5 m/s S · 65%" {
+		t.Errorf("RenderBarText() = %q", got)
+	}
+	if strings.Contains(got, "\n") {
+		t.Error("bar text must be a single line")
+	}
+
+	obs.PrecipAccumDay = 2.5
+	obs.LightningCount3hr = 4
+	got = RenderBarText(obs, units.ImperialSet, i18n.English, true)
+	if !strings.Contains(got, "°F") || !strings.Contains(got, "mph") {
+		t.Errorf("expected imperial units, got %q", got)
+	}
+	if !strings.Contains(got, "[ltg 4]") {
+		t.Errorf("expected text lightning marker with no-emoji, got %q", got)
+	}
+}
+
+func TestRenderBarTooltip(t *testing.T) {
+	obs := &tempest.StationObservation{
+		Timestamp:        time.Now().Add(-3 * time.Minute),
+		AirTemperature:   22.5,
+		SeaLevelPressure: 1013.2,
+	}
+	got := RenderBarTooltip(obs, "Home Station", units.MetricSet, i18n.English, time.Now())
+	for _, want := range []string{"Home Station", "22.5°C", "1013.2 hPa", "Observed 3m ago", "Fetched"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("tooltip missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -5,58 +5,34 @@ import (
 	"strings"
 	"time"
 
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	tempest "github.com/chadmayfield/tempest-go"
 	"github.com/charmbracelet/lipgloss"
 )
 
 // RenderCurrent renders a styled current conditions display.
-func RenderCurrent(theme *Theme, obs *tempest.StationObservation, stationName string, imperial bool, termWidth int) string {
+func RenderCurrent(theme *Theme, obs *tempest.StationObservation, stationName string, u units.Set, termWidth int) string {
 	var b strings.Builder
 
 	// Header
 	header := theme.Title.Render(stationName)
-	ago := timeAgo(obs.Timestamp)
-	updated := theme.Subtitle.Render(fmt.Sprintf("Updated %s ago", ago))
+	l := theme.Locale
+	updated := theme.Subtitle.Render(l.T("updated_ago", timeAgo(obs.Timestamp, l)))
 
 	b.WriteString(header + "  " + updated + "\n\n")
 
 	// Temperature block
-	tempStr := FormatTemp(obs.AirTemperature, imperial)
-	feelsStr := FormatTemp(obs.FeelsLike, imperial)
+	tempStr := FormatTemp(obs.AirTemperature, u, l)
+	feelsStr := FormatTemp(obs.FeelsLike, u, l)
 
 	bigTemp := theme.TempColor(obs.AirTemperature, tempStr)
 	b.WriteString(lipgloss.NewStyle().Bold(true).Render(bigTemp))
-	b.WriteString("  " + theme.Muted.Render("Feels like ") + theme.TempColor(obs.FeelsLike, feelsStr))
+	b.WriteString("  " + theme.Muted.Render(l.T("feels_like")+" ") + theme.TempColor(obs.FeelsLike, feelsStr))
 	b.WriteString("\n\n")
 
 	// Key-value grid — all values color-coded
-	humStr := fmt.Sprintf("%.0f%%", obs.RelativeHumidity)
-	windStr := formatWindFull(obs.WindAvg, obs.WindDirection, imperial)
-	gustStr := FormatWind(obs.WindGust, imperial)
-	pressureStr := FormatPressure(obs.SeaLevelPressure, imperial)
-	if obs.PressureTrend != "" {
-		pressureStr += " (" + obs.PressureTrend + ")"
-	}
-	rainStr := FormatPrecip(obs.PrecipAccumDay, imperial)
-	lightningStr := formatLightning(obs.LightningCount3hr, obs.LightningStrikeLastDistance, imperial)
-
-	rows := []struct {
-		label string
-		value string
-	}{
-		{"Humidity", theme.HumidityColor(obs.RelativeHumidity, humStr)},
-		{"Dew Point", theme.TempColor(obs.DewPoint, FormatTemp(obs.DewPoint, imperial))},
-		{"Wind", theme.WindColor(obs.WindAvg, windStr)},
-		{"Wind Gust", theme.WindColor(obs.WindGust, gustStr)},
-		{"Wind Lull", theme.WindColor(obs.WindLull, FormatWind(obs.WindLull, imperial))},
-		{"Pressure", theme.PressureColor(obs.SeaLevelPressure, pressureStr)},
-		{"UV Index", formatUV(theme, obs.UV)},
-		{"Solar Radiation", theme.Value.Render(fmt.Sprintf("%.0f W/m²", obs.SolarRadiation))},
-		{"Rain Today", theme.RainColor(obs.PrecipAccumDay, rainStr)},
-		{"Lightning (3hr)", theme.LightningColor(obs.LightningCount3hr, lightningStr)},
-		// Battery: not available in StationObservation (REST endpoint doesn't return it).
-		{"Battery", theme.Muted.Render("N/A")},
-	}
+	rows := currentRows(theme, obs, u)
 
 	// Two-column layout
 	mid := (len(rows) + 1) / 2
@@ -66,16 +42,16 @@ func RenderCurrent(theme *Theme, obs *tempest.StationObservation, stationName st
 	// Calculate per-column label widths for tight alignment.
 	leftLabelW := 0
 	for _, r := range leftCol {
-		if len(r.label) > leftLabelW {
-			leftLabelW = len(r.label)
+		if w := lipgloss.Width(r.label); w > leftLabelW {
+			leftLabelW = w
 		}
 	}
 	leftLabelW += 2 // padding after label
 
 	rightLabelW := 0
 	for _, r := range rightCol {
-		if len(r.label) > rightLabelW {
-			rightLabelW = len(r.label)
+		if w := lipgloss.Width(r.label); w > rightLabelW {
+			rightLabelW = w
 		}
 	}
 	rightLabelW += 2 // padding after label
@@ -85,10 +61,10 @@ func RenderCurrent(theme *Theme, obs *tempest.StationObservation, stationName st
 
 	for i := range leftCol {
 		leftLabel := theme.Label.Width(leftLabelW).Render(leftCol[i].label)
-		left := leftCell.Render(leftLabel + leftCol[i].value)
+		left := leftCell.Render(leftLabel + theme.currentValue(leftCol[i]))
 		if i < len(rightCol) {
 			rightLabel := theme.Label.Width(rightLabelW).Render(rightCol[i].label)
-			right := rightLabel + rightCol[i].value
+			right := rightLabel + theme.currentValue(rightCol[i])
 			b.WriteString(left + right + "\n")
 		} else {
 			b.WriteString(left + "\n")
@@ -108,45 +84,119 @@ func RenderCurrent(theme *Theme, obs *tempest.StationObservation, stationName st
 	return content
 }
 
-func formatWindFull(mps, degrees float64, imperial bool) string {
-	compass := tempest.WindDirectionToCompass(degrees)
-	arrow := WindArrow(degrees)
-	speed := FormatWind(mps, imperial)
-	return fmt.Sprintf("%s %s %s", speed, compass, arrow)
+// currentRow is a measurement in the current conditions grid.
+type currentRow struct {
+	label, text string
+	// metric is the threshold metric that colors the value, if any, for
+	// value in metric units.
+	metric string
+	value  float64
+	muted  bool
+}
+
+// currentRows returns the measurements shown below the temperature.
+func currentRows(theme *Theme, obs *tempest.StationObservation, u units.Set) []currentRow {
+	l := theme.Locale
+	press := pressureRow(obs.SeaLevelPressure, u, l)
+	if obs.PressureTrend != "" {
+		press.text += " (" + obs.PressureTrend + ")"
+	}
+	return []currentRow{
+		{label: l.T("humidity"), text: fmt.Sprintf("%.0f%%", obs.RelativeHumidity), metric: "humidity", value: obs.RelativeHumidity},
+		{label: l.T("dew_point"), text: FormatTemp(obs.DewPoint, u, l), metric: "temperature", value: obs.DewPoint},
+		{label: l.T("wind"), text: formatWindFull(obs.WindAvg, obs.WindDirection, u, l), metric: "wind", value: obs.WindAvg},
+		{label: l.T("wind_gust"), text: FormatWind(obs.WindGust, u, l), metric: "wind", value: obs.WindGust},
+		{label: l.T("wind_lull"), text: FormatWind(obs.WindLull, u, l), metric: "wind", value: obs.WindLull},
+		press,
+		{label: l.T("uv_index"), text: fmt.Sprintf("%s (%s)", l.Number(obs.UV, 1), l.T(uvLevel(obs.UV))), metric: "uv", value: obs.UV},
+		{label: l.T("solar_radiation"), text: fmt.Sprintf("%.0f W/m²", obs.SolarRadiation)},
+		{label: l.T("rain_today"), text: FormatPrecip(obs.PrecipAccumDay, u, l), metric: "rain", value: obs.PrecipAccumDay},
+		{label: l.T("lightning_3hr"), text: formatLightning(obs.LightningCount3hr, obs.LightningStrikeLastDistance, u, l),
+			metric: "lightning", value: float64(obs.LightningCount3hr)},
+		// Battery: not available in StationObservation (REST endpoint doesn't return it).
+		{label: l.T("battery"), text: l.T("not_available"), muted: true},
+	}
 }
 
-func formatUV(theme *Theme, uv float64) string {
-	label := UVLabel(uv)
-	formatted := fmt.Sprintf("%.1f (%s)", uv, label)
-	return theme.UVColor(uv, formatted)
+// pressureRow returns the sea-level pressure row. Observations that carry no
+// sea-level pressure, such as UDP broadcasts from a station of unknown
+// elevation, show it as not available.
+func pressureRow(hpa float64, u units.Set, l *i18n.Locale) currentRow {
+	if hpa == 0 {
+		return currentRow{label: l.T("pressure"), text: l.T("not_available"), muted: true}
+	}
+	return currentRow{label: l.T("pressure"), text: FormatPressure(hpa, u, l), metric: "pressure", value: hpa}
+}
+
+// currentValue styles a current conditions value for the terminal.
+func (t *Theme) currentValue(r currentRow) string {
+	switch {
+	case r.muted:
+		return t.Muted.Render(r.text)
+	case r.metric != "":
+		return t.thresholdColor(r.metric, r.value, r.text)
+	}
+	return t.Value.Render(r.text)
+}
+
+// CurrentDocument returns current conditions as a table for markdown and
+// HTML output.
+func CurrentDocument(theme *Theme, obs *tempest.StationObservation, stationName string, u units.Set) Document {
+	l := theme.Locale
+	rows := [][]Cell{
+		{{Text: l.T("temperature")}, {Text: FormatTemp(obs.AirTemperature, u, l), Color: theme.bandColor("temperature", obs.AirTemperature)}},
+		{{Text: l.T("feels_like")}, {Text: FormatTemp(obs.FeelsLike, u, l), Color: theme.bandColor("temperature", obs.FeelsLike)}},
+	}
+	for _, r := range currentRows(theme, obs, u) {
+		c := Cell{Text: r.text}
+		if r.metric != "" {
+			c.Color = theme.bandColor(r.metric, r.value)
+		}
+		rows = append(rows, []Cell{{Text: r.label}, c})
+	}
+	return Document{
+		Title: stationName,
+		Sections: []Section{{
+			Note:    l.T("observed_at", l.DayLabel(obs.Timestamp)+" "+l.Time(obs.Timestamp)),
+			Columns: []DocColumn{{Title: l.T("column_measurement")}, {Title: l.T("column_value"), Align: AlignRight}},
+			Rows:    rows,
+		}},
+	}
+}
+
+func formatWindFull(mps, degrees float64, u units.Set, l *i18n.Locale) string {
+	compass := l.CompassPoint(degrees)
+	arrow := WindArrow(degrees)
+	speed := FormatWind(mps, u, l)
+	return fmt.Sprintf("%s %s %s", speed, compass, arrow)
 }
 
-func formatLightning(count int, distKm float64, imperial bool) string {
+func formatLightning(count int, distKm float64, u units.Set, l *i18n.Locale) string {
 	if count == 0 {
-		return "none"
+		return l.T("none")
 	}
-	s := fmt.Sprintf("%d strikes", count)
+	s := l.T("lightning_strikes", count)
 	if distKm > 0 {
-		s += " " + FormatDistance(distKm, imperial) + " avg"
+		s += " " + l.T("lightning_avg_distance", FormatDistance(distKm, u, l))
 	}
 	return s
 }
 
-func timeAgo(t time.Time) string {
+func timeAgo(t time.Time, l *i18n.Locale) string {
 	d := time.Since(t)
 	switch {
 	case d < time.Minute:
-		return fmt.Sprintf("%ds", int(d.Seconds()))
+		return l.T("duration_seconds", int(d.Seconds()))
 	case d < time.Hour:
-		return fmt.Sprintf("%dm", int(d.Minutes()))
+		return l.T("duration_minutes", int(d.Minutes()))
 	case d < 24*time.Hour:
 		h := int(d.Hours())
 		m := int(d.Minutes()) % 60
 		if m > 0 {
-			return fmt.Sprintf("%dh %dm", h, m)
+			return l.T("duration_hours_minutes", h, m)
 		}
-		return fmt.Sprintf("%dh", h)
+		return l.T("duration_hours", h)
 	default:
-		return fmt.Sprintf("%dd", int(d.Hours()/24))
+		return l.T("duration_days", int(d.Hours()/24))
 	}
 }
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	tempest "github.com/chadmayfield/tempest-go"
 )
 
@@ -32,7 +34,7 @@ func TestRenderCurrent(t *testing.T) {
 	}
 
 	// Metric
-	output := RenderCurrent(theme, obs, "Home Station", false, 80)
+	output := RenderCurrent(theme, obs, "Home Station", units.MetricSet, 80)
 
 	if !strings.Contains(output, "Home Station") {
 		t.Error("missing station name")
@@ -54,7 +56,7 @@ func TestRenderCurrent(t *testing.T) {
 	}
 
 	// Imperial
-	output = RenderCurrent(theme, obs, "Home Station", true, 80)
+	output = RenderCurrent(theme, obs, "Home Station", units.ImperialSet, 80)
 	if !strings.Contains(output, "°F") {
 		t.Error("missing fahrenheit")
 	}
@@ -70,7 +72,7 @@ func TestRenderCurrentNoColor(t *testing.T) {
 		AirTemperature: 20.0,
 		FeelsLike:      19.0,
 	}
-	output := RenderCurrent(theme, obs, "Test", false, 80)
+	output := RenderCurrent(theme, obs, "Test", units.MetricSet, 80)
 	if output == "" {
 		t.Error("expected non-empty output")
 	}
@@ -115,7 +117,7 @@ func TestRenderCurrentWithColor(t *testing.T) {
 		PressureTrend:      "falling",
 	}
 
-	output := RenderCurrent(theme, obs, "Extreme Station", false, 120)
+	output := RenderCurrent(theme, obs, "Extreme Station", units.MetricSet, 120)
 	if !strings.Contains(output, "Extreme Station") {
 		t.Error("missing station name")
 	}
@@ -134,21 +136,35 @@ func TestRenderCurrentPressureTrend(t *testing.T) {
 		PressureTrend:    "rising",
 	}
 
-	output := RenderCurrent(theme, obs, "Test", false, 80)
+	output := RenderCurrent(theme, obs, "Test", units.MetricSet, 80)
 	if !strings.Contains(output, "(rising)") {
 		t.Error("missing pressure trend in output")
 	}
 }
 
+func TestRenderCurrentNoSeaLevelPressure(t *testing.T) {
+	theme := NewTheme(true)
+	obs := &tempest.StationObservation{
+		Timestamp:          time.Now().Add(-30 * time.Second),
+		AirTemperature:     20.0,
+		BarometricPressure: 850.0,
+	}
+
+	output := RenderCurrent(theme, obs, "Test", units.MetricSet, 80)
+	if !strings.Contains(output, "Pressure") || strings.Contains(output, "0 hPa") || strings.Contains(output, "850") {
+		t.Errorf("missing sea-level pressure not shown as N/A:\n%s", output)
+	}
+}
+
 func TestFormatLightningWithDistance(t *testing.T) {
 	// No lightning
-	got := formatLightning(0, 0, false)
+	got := formatLightning(0, 0, units.MetricSet, i18n.English)
 	if got != "none" {
 		t.Errorf("formatLightning(0) = %q, want %q", got, "none")
 	}
 
 	// With strikes, metric
-	got = formatLightning(5, 10.0, false)
+	got = formatLightning(5, 10.0, units.MetricSet, i18n.English)
 	if !strings.Contains(got, "5 strikes") {
 		t.Errorf("expected '5 strikes', got %q", got)
 	}
@@ -157,7 +173,7 @@ func TestFormatLightningWithDistance(t *testing.T) {
 	}
 
 	// With strikes, imperial
-	got = formatLightning(3, 8.0, true)
+	got = formatLightning(3, 8.0, units.ImperialSet, i18n.English)
 	if !strings.Contains(got, "3 strikes") {
 		t.Errorf("expected '3 strikes', got %q", got)
 	}
@@ -166,7 +182,7 @@ func TestFormatLightningWithDistance(t *testing.T) {
 	}
 
 	// With strikes but no distance
-	got = formatLightning(2, 0, false)
+	got = formatLightning(2, 0, units.MetricSet, i18n.English)
 	if !strings.Contains(got, "2 strikes") {
 		t.Errorf("expected '2 strikes', got %q", got)
 	}
@@ -186,7 +202,7 @@ func TestTimeAgo(t *testing.T) {
 		{48 * time.Hour, "2d"},
 	}
 	for _, tt := range tests {
-		got := timeAgo(time.Now().Add(-tt.d))
+		got := timeAgo(time.Now().Add(-tt.d), i18n.English)
 		if got != tt.want {
 			t.Errorf("timeAgo(%v) = %q, want %q", tt.d, got, tt.want)
 		}
@@ -0,0 +1,122 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/units"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StationCard is one station in the current conditions grid.
+type StationCard struct {
+	Name        string
+	Observation *tempest.StationObservation
+	Units       units.Set
+	// Online is false when the observation is older than the station's
+	// stale_after.
+	Online bool
+	// Source names where the data came from; Stale marks cached fallback data.
+	Source    string
+	FetchedAt time.Time
+	Stale     bool
+	// Reason explains why the station could not be fetched, e.g. "auth
+	// failed". The card shows it instead of conditions.
+	Reason string
+}
+
+// RenderCurrentGrid renders compact current conditions cards for several
+// stations, side by side and wrapped to the terminal width.
+func RenderCurrentGrid(theme *Theme, cards []StationCard, termWidth int) string {
+	if len(cards) == 0 {
+		return ""
+	}
+	contents := make([]string, len(cards))
+	maxHeight := 0
+	for i, c := range cards {
+		contents[i] = strings.TrimSuffix(stationCardContent(theme, c), "\n")
+		maxHeight = max(maxHeight, lipgloss.Height(contents[i]))
+	}
+
+	cardStyle := theme.Card.Width(30).Height(maxHeight)
+	rendered := make([]string, len(contents))
+	for i, c := range contents {
+		rendered[i] = cardStyle.Render(c)
+	}
+	return strings.TrimSuffix(layoutCards(rendered, termWidth), "\n")
+}
+
+func stationCardContent(theme *Theme, c StationCard) string {
+	var b strings.Builder
+	l := theme.Locale
+
+	b.WriteString(theme.Title.Render(c.Name))
+	b.WriteString("\n")
+	if c.Reason != "" {
+		b.WriteString(theme.Error.Render(l.T("unavailable", c.Reason)))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	obs, u := c.Observation, c.Units
+	updated := l.T("updated_ago", timeAgo(obs.Timestamp, l))
+	if c.Online {
+		b.WriteString(theme.Muted.Render(updated))
+	} else {
+		b.WriteString(theme.Warning.Render(l.T("offline") + " · " + updated))
+	}
+	b.WriteString("\n\n")
+
+	temp := FormatTemp(obs.AirTemperature, u, l)
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render(theme.TempColor(obs.AirTemperature, temp)))
+	b.WriteString("  " + theme.Muted.Render(l.T("feels_like")+" ") + theme.TempColor(obs.FeelsLike, FormatTemp(obs.FeelsLike, u, l)))
+	b.WriteString("\n")
+
+	rows := []currentRow{
+		{label: l.T("humidity"), text: fmt.Sprintf("%.0f%%", obs.RelativeHumidity), metric: "humidity", value: obs.RelativeHumidity},
+		{label: l.T("wind"), text: formatWindFull(obs.WindAvg, obs.WindDirection, u, l), metric: "wind", value: obs.WindAvg},
+		{label: l.T("wind_gust"), text: FormatWind(obs.WindGust, u, l), metric: "wind", value: obs.WindGust},
+		pressureRow(obs.SeaLevelPressure, u, l),
+		{label: l.T("rain_today"), text: FormatPrecip(obs.PrecipAccumDay, u, l), metric: "rain", value: obs.PrecipAccumDay},
+	}
+	labelW := 0
+	for _, r := range rows {
+		labelW = max(labelW, lipgloss.Width(r.label))
+	}
+	for _, r := range rows {
+		b.WriteString(theme.Label.Width(labelW+1).Render(r.label) + theme.currentValue(r) + "\n")
+	}
+
+	source := sourceLabel(c.Source, c.Stale, l)
+	if c.Stale {
+		b.WriteString("\n" + theme.Warning.Render(source) + "\n")
+	} else if c.Source != "" {
+		b.WriteString("\n" + theme.Muted.Render(source) + "\n")
+	}
+	return b.String()
+}
+
+// CurrentGridDocument returns current conditions for several stations as a
+// table each, for markdown and HTML output.
+func CurrentGridDocument(theme *Theme, cards []StationCard) Document {
+	l := theme.Locale
+	doc := Document{Title: l.T("current_conditions")}
+	for _, c := range cards {
+		if c.Reason != "" {
+			doc.Sections = append(doc.Sections, Section{Title: c.Name, Note: l.T("unavailable", c.Reason)})
+			continue
+		}
+		sec := CurrentDocument(theme, c.Observation, c.Name, c.Units).Sections[0]
+		sec.Title = c.Name
+		if !c.Online {
+			sec.Note = l.T("offline") + " · " + sec.Note
+		}
+		if c.Source != "" {
+			sec.Note += " · " + sourceLabel(c.Source, c.Stale, l)
+		}
+		doc.Sections = append(doc.Sections, sec)
+	}
+	return doc
+}
@@ -0,0 +1,59 @@
+package display
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/units"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func gridCards() []StationCard {
+	obs := &tempest.StationObservation{Timestamp: time.Now().Add(-3 * time.Minute), AirTemperature: 21.5, RelativeHumidity: 40, WindAvg: 2, SeaLevelPressure: 1012}
+	old := &tempest.StationObservation{Timestamp: time.Now().Add(-2 * time.Hour), AirTemperature: -4}
+	return []StationCard{
+		{Name: "Home", Observation: obs, Units: units.MetricSet, Online: true, Source: "tempestd", FetchedAt: time.Now()},
+		{Name: "Cabin", Observation: old, Units: units.ImperialSet, Source: "cloud", FetchedAt: time.Now(), Stale: true},
+		{Name: "Office", Reason: "auth failed"},
+	}
+}
+
+func TestRenderCurrentGrid(t *testing.T) {
+	out := RenderCurrentGrid(NewTheme(true), gridCards(), 200)
+	for _, want := range []string{"Home", "Updated 3m ago", "21.5°C", "tempestd", "Offline · Updated 2h ago", "24.8°F", "cloud (stale)", "Unavailable: auth failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("grid missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderCurrentGridWraps(t *testing.T) {
+	theme := NewTheme(false)
+	wide := RenderCurrentGrid(theme, gridCards(), 200)
+	narrow := RenderCurrentGrid(theme, gridCards(), 70)
+	if lipgloss.Height(narrow) <= lipgloss.Height(wide) {
+		t.Errorf("narrow grid is %d lines, wide %d; cards should wrap", lipgloss.Height(narrow), lipgloss.Height(wide))
+	}
+	if w := lipgloss.Width(narrow); w > 70 {
+		t.Errorf("narrow grid is %d wide, want at most 70", w)
+	}
+	// Cards in a row line up, so the wide grid is a single row of cards.
+	if strings.Count(wide, "Home") != 1 || lipgloss.Width(wide) < 3*30 {
+		t.Errorf("wide grid should hold all three cards in one row:\n%s", wide)
+	}
+}
+
+func TestCurrentGridDocument(t *testing.T) {
+	doc := CurrentGridDocument(NewTheme(true), gridCards())
+	if doc.Title != "Current Conditions" || len(doc.Sections) != 3 {
+		t.Fatalf("doc = %q with %d sections", doc.Title, len(doc.Sections))
+	}
+	if s := doc.Sections[1]; s.Title != "Cabin" || !strings.HasPrefix(s.Note, "Offline · ") || !strings.HasSuffix(s.Note, "cloud (stale)") {
+		t.Errorf("offline section = %q, %q", s.Title, s.Note)
+	}
+	if s := doc.Sections[2]; s.Note != "Unavailable: auth failed" || len(s.Rows) != 0 {
+		t.Errorf("failed section = %+v", s)
+	}
+}
@@ -0,0 +1,85 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/charmbracelet/bubbles/table"
+)
+
+// DeviceTypeName describes a WeatherFlow device type code.
+func DeviceTypeName(t string) string {
+	switch t {
+	case "ST":
+		return "Tempest"
+	case "HB":
+		return "Hub"
+	case "AR":
+		return "Air"
+	case "SK":
+		return "Sky"
+	default:
+		return "Unknown"
+	}
+}
+
+// RenderDiscovery renders the stations visible to a token, one row per
+// device. configured maps station IDs to their name in the config file.
+func RenderDiscovery(theme *Theme, stations []tempest.Station, configured map[int]string) string {
+	var b strings.Builder
+	l := theme.Locale
+
+	b.WriteString(theme.Title.Render(l.T("discovered_stations")))
+	b.WriteString("\n\n")
+
+	if len(stations) == 0 {
+		b.WriteString(theme.Muted.Render(l.T("no_stations_visible")))
+		return b.String()
+	}
+
+	columns := []table.Column{
+		{Title: l.T("column_sid"), Width: 8},
+		{Title: l.T("column_station"), Width: 20},
+		{Title: l.T("column_did"), Width: 8},
+		{Title: l.T("column_type"), Width: 12},
+		{Title: l.T("column_serial"), Width: 12},
+		{Title: l.T("column_config"), Width: 12},
+	}
+
+	var rows []table.Row
+	for _, s := range stations {
+		name := []rune(s.Name)
+		if len(name) > 20 {
+			name = append(name[:19], '…')
+		}
+		sid, station, config := fmt.Sprintf("%d", s.StationID), string(name), configured[s.StationID]
+		if len(s.Devices) == 0 {
+			rows = append(rows, table.Row{sid, station, "", l.T("no_devices"), "", config})
+			continue
+		}
+		for _, d := range s.Devices {
+			rows = append(rows, table.Row{
+				sid, station,
+				fmt.Sprintf("%d", d.DeviceID),
+				fmt.Sprintf("%s (%s)", DeviceTypeName(d.DeviceType), d.DeviceType),
+				d.SerialNum, config,
+			})
+			// Only the first device row repeats the station details.
+			sid, station, config = "", "", ""
+		}
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithHeight(len(rows)+1),
+	)
+	t.SetStyles(stationTableStyles(theme))
+
+	b.WriteString(t.View())
+	b.WriteString("\n\n")
+	b.WriteString(theme.Muted.Render(l.T("discover_hint")))
+
+	return b.String()
+}
@@ -0,0 +1,34 @@
+package display
+
+import (
+	"strings"
+	"testing"
+
+	tempest "github.com/chadmayfield/tempest-go"
+)
+
+func TestRenderDiscovery(t *testing.T) {
+	theme := NewTheme(true)
+	stations := []tempest.Station{{
+		StationID: 100,
+		Name:      "Home",
+		Devices: []tempest.Device{
+			{DeviceID: 1, SerialNum: "HB-00000001", DeviceType: "HB"},
+			{DeviceID: 2, SerialNum: "ST-00000002", DeviceType: "ST"},
+		},
+	}}
+
+	output := RenderDiscovery(theme, stations, map[int]string{100: "home"})
+	for _, want := range []string{"Discovered Stations", "Hub (HB)", "Tempest (ST)", "ST-00000002", "home"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("missing %q in:\n%s", want, output)
+		}
+	}
+	if strings.Count(output, "100") != 1 {
+		t.Errorf("station ID should only appear on its first device row:\n%s", output)
+	}
+
+	if out := RenderDiscovery(theme, nil, nil); !strings.Contains(out, "No stations") {
+		t.Errorf("empty output = %q", out)
+	}
+}
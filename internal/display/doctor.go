@@ -0,0 +1,93 @@
+package display
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CheckStatus is the outcome of a single diagnostic check.
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+// DoctorCheck is one line of 'tempest doctor' output.
+type DoctorCheck struct {
+	Name    string      `json:"name"`
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message"`
+	Fix     string      `json:"fix,omitempty"`
+}
+
+// RenderDoctor renders diagnostic results as pass/warn/fail lines, each
+// followed by its suggested fix, and a summary line.
+func RenderDoctor(theme *Theme, checks []DoctorCheck) string {
+	var b strings.Builder
+	l := theme.Locale
+
+	b.WriteString(theme.Title.Render(l.T("doctor")))
+	b.WriteString("\n\n")
+
+	// Pad badges to the widest label, and at least six columns, so messages
+	// line up in every locale.
+	width := 6
+	for _, s := range []CheckStatus{CheckPass, CheckWarn, CheckFail} {
+		width = max(width, lipgloss.Width(badgeLabel(theme, s)))
+	}
+	indent := strings.Repeat(" ", width+1)
+
+	counts := map[CheckStatus]int{}
+	for _, c := range checks {
+		counts[c.Status]++
+		b.WriteString(checkBadge(theme, c.Status, width))
+		b.WriteString(" ")
+		b.WriteString(theme.Label.Render(c.Name + ":"))
+		b.WriteString(" ")
+		b.WriteString(theme.Value.Render(c.Message))
+		b.WriteString("\n")
+		if c.Fix != "" {
+			b.WriteString(indent)
+			b.WriteString(theme.Muted.Render("→ " + c.Fix))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(theme.Subtitle.Render(l.T("doctor_summary",
+		counts[CheckPass], counts[CheckWarn], counts[CheckFail])))
+
+	return b.String()
+}
+
+// badgeLabel returns the text of a status badge, e.g. "✓ PASS".
+func badgeLabel(theme *Theme, s CheckStatus) string {
+	key, symbol := "check_fail", "✗ "
+	switch s {
+	case CheckPass:
+		key, symbol = "check_pass", "✓ "
+	case CheckWarn:
+		key, symbol = "check_warn", "! "
+	}
+	label := theme.Locale.T(key)
+	if !theme.NoEmoji {
+		label = symbol + label
+	}
+	return label
+}
+
+func checkBadge(theme *Theme, s CheckStatus, width int) string {
+	label := badgeLabel(theme, s)
+	label += strings.Repeat(" ", width-lipgloss.Width(label))
+	switch s {
+	case CheckPass:
+		return theme.Success.Render(label)
+	case CheckWarn:
+		return theme.Warning.Render(label)
+	default:
+		return theme.Error.Render(label)
+	}
+}
@@ -0,0 +1,35 @@
+package display
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderDoctor(t *testing.T) {
+	theme := NewTheme(true, WithNoEmoji(true))
+
+	checks := []DoctorCheck{
+		{Name: "config file", Status: CheckPass, Message: "/home/u/.config/tempest/config.yaml"},
+		{Name: "config permissions", Status: CheckWarn, Message: "readable by other users", Fix: "chmod 600 config.yaml"},
+		{Name: "station home: device_id", Status: CheckFail, Message: "1 is the hub", Fix: "set device_id: 2"},
+	}
+
+	output := RenderDoctor(theme, checks)
+
+	for _, want := range []string{
+		"Tempest Doctor",
+		"PASS   config file:",
+		"WARN   config permissions:",
+		"FAIL   station home: device_id: 1 is the hub",
+		"→ chmod 600 config.yaml",
+		"→ set device_id: 2",
+		"1 passed, 1 warnings, 1 failed",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("missing %q in:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "✓") {
+		t.Error("emoji badge rendered with NoEmoji")
+	}
+}
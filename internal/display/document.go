@@ -0,0 +1,265 @@
+package display
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Format is an output format for the commands that render tables and cards.
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat parses a --format value; empty means text.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "text":
+		return FormatText, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	}
+	return "", fmt.Errorf("unknown format %q; use text, markdown or html", s)
+}
+
+// Document is a command's output as plain tables, for pasting into issues,
+// wikis and newsletters rather than showing in a terminal.
+type Document struct {
+	Title    string
+	Sections []Section
+	// Footer names the data source; FooterWarning marks it as stale.
+	Footer        string
+	FooterWarning bool
+}
+
+// Section is a table with an optional heading and a note above it.
+type Section struct {
+	Title   string
+	Note    string
+	Columns []DocColumn
+	Rows    [][]Cell
+}
+
+// DocColumn is a table column heading and how its cells are aligned.
+type DocColumn struct {
+	Title string
+	Align Align
+}
+
+// Align is a table column's alignment.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// Cell is a table cell. Color, if set, is the theme's threshold color for
+// its value; only HTML shows it.
+type Cell struct {
+	Text  string
+	Color *Color
+}
+
+// Markdown renders d as GitHub-flavored markdown.
+func (d Document) Markdown() string {
+	var b strings.Builder
+	b.WriteString("## " + markdownEscape(d.Title) + "\n")
+	for _, s := range d.Sections {
+		b.WriteString("\n")
+		if s.Title != "" {
+			b.WriteString("### " + markdownEscape(s.Title) + "\n\n")
+		}
+		if s.Note != "" {
+			b.WriteString(markdownEscape(s.Note) + "\n\n")
+		}
+		if len(s.Columns) == 0 {
+			continue
+		}
+		b.WriteString("|")
+		for _, c := range s.Columns {
+			b.WriteString(" " + markdownEscape(c.Title) + " |")
+		}
+		b.WriteString("\n|")
+		for _, c := range s.Columns {
+			switch c.Align {
+			case AlignRight:
+				b.WriteString(" ---: |")
+			case AlignCenter:
+				b.WriteString(" :---: |")
+			default:
+				b.WriteString(" :--- |")
+			}
+		}
+		b.WriteString("\n")
+		for _, row := range s.Rows {
+			b.WriteString("|")
+			for _, c := range row {
+				b.WriteString(" " + markdownEscape(c.Text) + " |")
+			}
+			b.WriteString("\n")
+		}
+	}
+	if d.Footer != "" {
+		b.WriteString("\n_" + markdownEscape(d.Footer) + "_\n")
+	}
+	return b.String()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`",
+	"<", "&lt;", ">", "&gt;", "\n", "<br>",
+)
+
+// markdownEscape escapes s for a markdown table cell or heading.
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// HTML renders d as a standalone page, styled inline with the theme's
+// palette. Dark colors are used when the reader's system prefers them.
+func (d Document) HTML(theme *Theme) string {
+	var b strings.Builder
+	esc := html.EscapeString
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"" + esc(theme.Locale.Tag) + "\">\n<head>\n")
+	b.WriteString("<meta charset=\"utf-8\">\n<title>" + esc(d.Title) + "</title>\n")
+	b.WriteString("<style>\n" + documentCSS(theme) + "</style>\n</head>\n<body>\n")
+	b.WriteString("<h1>" + esc(d.Title) + "</h1>\n")
+	for _, s := range d.Sections {
+		b.WriteString("<section>\n")
+		if s.Title != "" {
+			b.WriteString("<h2>" + esc(s.Title) + "</h2>\n")
+		}
+		if s.Note != "" {
+			b.WriteString("<p class=\"note\">" + esc(s.Note) + "</p>\n")
+		}
+		if len(s.Columns) > 0 {
+			b.WriteString("<table>\n<thead><tr>")
+			for _, c := range s.Columns {
+				b.WriteString("<th" + alignClass(c.Align) + ">" + esc(c.Title) + "</th>")
+			}
+			b.WriteString("</tr></thead>\n<tbody>\n")
+			for _, row := range s.Rows {
+				b.WriteString("<tr>")
+				for i, c := range row {
+					attrs := ""
+					if i < len(s.Columns) {
+						attrs = alignClass(s.Columns[i].Align)
+					}
+					if c.Color != nil && !theme.NoColor {
+						attrs += fmt.Sprintf(` style="--light:%s;--dark:%s"`, cssColor(c.Color.Light), cssColor(c.Color.Dark))
+					}
+					b.WriteString("<td" + attrs + ">" + esc(c.Text) + "</td>")
+				}
+				b.WriteString("</tr>\n")
+			}
+			b.WriteString("</tbody>\n</table>\n")
+		}
+		b.WriteString("</section>\n")
+	}
+	if d.Footer != "" {
+		class := "footer"
+		if d.FooterWarning {
+			class += " warning"
+		}
+		b.WriteString("<p class=\"" + class + "\">" + esc(d.Footer) + "</p>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+func alignClass(a Align) string {
+	switch a {
+	case AlignRight:
+		return ` class="right"`
+	case AlignCenter:
+		return ` class="center"`
+	}
+	return ""
+}
+
+// documentCSS returns the page's style sheet: the theme's palette as CSS
+// variables for light and dark color schemes, and rules that use them.
+func documentCSS(theme *Theme) string {
+	var b strings.Builder
+	rules := `body { font-family: system-ui, -apple-system, "Segoe UI", sans-serif; margin: 1.5rem; }
+h1 { color: var(--title); font-size: 1.4rem; }
+h2 { color: var(--subtitle); font-size: 1.1rem; }
+table { border-collapse: collapse; border: 1px solid var(--card); margin-bottom: 1rem; }
+th { color: var(--label); text-align: left; border-bottom: 1px solid var(--border); padding: 0.3rem 0.7rem; }
+td { color: var(--value); padding: 0.3rem 0.7rem; }
+tbody tr:nth-child(even) { background: color-mix(in srgb, var(--card) 25%, transparent); }
+.right { text-align: right; }
+.center { text-align: center; }
+td[style] { color: var(--light); }
+.note, .footer { color: var(--muted); }
+.warning { color: var(--warning); }
+`
+	if theme.NoColor {
+		// Without color, borders are gray and text keeps the page's color.
+		rules = strings.NewReplacer("var(--card)", "#ccc", "var(--border)", "#ccc").Replace(rules)
+		return cssVar.ReplaceAllString(rules, "inherit")
+	}
+	palette := func(dark bool) string {
+		var p strings.Builder
+		p.WriteString(":root {")
+		for _, key := range paletteKeys {
+			c := theme.spec.Palette[key]
+			v := c.Light
+			if dark {
+				v = c.Dark
+			}
+			p.WriteString(" --" + key + ": " + cssColor(v) + ";")
+		}
+		p.WriteString(" }\n")
+		return p.String()
+	}
+	// The dark scheme comes last so that it overrides the light rules.
+	b.WriteString(palette(false))
+	b.WriteString(rules)
+	b.WriteString("@media (prefers-color-scheme: dark) {\n")
+	b.WriteString(palette(true))
+	b.WriteString("body { background: #1e1e1e; }\ntd[style] { color: var(--dark); }\n}\n")
+	return b.String()
+}
+
+var cssVar = regexp.MustCompile(`var\(--[a-z]+\)`)
+
+// cssColor converts a theme color, "#rrggbb", "#rgb" or an ANSI color
+// number, to a CSS color using the standard xterm palette.
+func cssColor(s string) string {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > 255 {
+		return s
+	}
+	switch {
+	case n < 16:
+		return ansiBasic[n]
+	case n < 232:
+		n -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + 40*v
+		}
+		return fmt.Sprintf("#%02x%02x%02x", level(n/36), level(n/6%6), level(n%6))
+	}
+	g := 8 + 10*(n-232)
+	return fmt.Sprintf("#%02x%02x%02x", g, g, g)
+}
+
+// ansiBasic is xterm's palette for the 16 basic ANSI colors.
+var ansiBasic = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
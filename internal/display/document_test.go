@@ -0,0 +1,161 @@
+package display
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/pressure"
+	"github.com/chadmayfield/tempest-cli/internal/units"
+	tempest "github.com/chadmayfield/tempest-go"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := map[string]Format{
+		"":         FormatText,
+		"text":     FormatText,
+		"Markdown": FormatMarkdown,
+		"md":       FormatMarkdown,
+		" html ":   FormatHTML,
+	}
+	for in, want := range tests {
+		got, err := ParseFormat(in)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("ParseFormat(pdf) should fail")
+	}
+}
+
+func testDocument() Document {
+	red := Color{Light: "160", Dark: "#ff5555"}
+	return Document{
+		Title: "Back <yard>",
+		Sections: []Section{{
+			Note: "a_b",
+			Columns: []DocColumn{
+				{Title: "Name"},
+				{Title: "Value", Align: AlignRight},
+				{Title: "OK", Align: AlignCenter},
+			},
+			Rows: [][]Cell{
+				{{Text: "a|b"}, {Text: "30.0°C", Color: &red}, {Text: "✓"}},
+			},
+		}},
+		Footer:        "Source: tempestd",
+		FooterWarning: true,
+	}
+}
+
+func TestDocumentMarkdown(t *testing.T) {
+	out := testDocument().Markdown()
+	for _, want := range []string{
+		"## Back &lt;yard&gt;\n",
+		"a\\_b\n",
+		"| Name | Value | OK |\n",
+		"| :--- | ---: | :---: |\n",
+		"| a\\|b | 30.0°C | ✓ |\n",
+		"_Source: tempestd_\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\x1b") {
+		t.Errorf("markdown contains escape sequences:\n%s", out)
+	}
+}
+
+func TestDocumentHTML(t *testing.T) {
+	out := testDocument().HTML(NewTheme(false))
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`<html lang="en">`,
+		"<title>Back &lt;yard&gt;</title>",
+		"--title: ",
+		"@media (prefers-color-scheme: dark)",
+		`<th class="right">Value</th>`,
+		`<td class="right" style="--light:#d70000;--dark:#ff5555">30.0°C</td>`,
+		`<td class="center">✓</td>`,
+		`<p class="footer warning">Source: tempestd</p>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<link") || strings.Contains(out, "<script") {
+		t.Error("HTML should be self-contained")
+	}
+}
+
+func TestDocumentHTMLNoColor(t *testing.T) {
+	out := testDocument().HTML(NewTheme(true))
+	if strings.Contains(out, "var(--") || strings.Contains(out, "style=\"--light") {
+		t.Errorf("no-color HTML should not use palette colors:\n%s", out)
+	}
+	if !strings.Contains(out, "#ccc") {
+		t.Error("no-color HTML should keep gray borders")
+	}
+}
+
+func TestCSSColor(t *testing.T) {
+	tests := map[string]string{
+		"1":       "#cd0000",
+		"196":     "#ff0000",
+		"16":      "#000000",
+		"244":     "#808080",
+		"#abc":    "#abc",
+		"#a0b1c2": "#a0b1c2",
+		"300":     "300",
+	}
+	for in, want := range tests {
+		if got := cssColor(in); got != want {
+			t.Errorf("cssColor(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCommandDocuments(t *testing.T) {
+	theme := NewTheme(false)
+	ts := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+
+	cur := CurrentDocument(theme, &tempest.StationObservation{Timestamp: ts, AirTemperature: 35, RelativeHumidity: 40}, "Backyard", units.MetricSet)
+	if cur.Title != "Backyard" || len(cur.Sections) != 1 {
+		t.Fatalf("CurrentDocument = %+v", cur)
+	}
+	if r := cur.Sections[0].Rows[0]; r[0].Text != "Temperature" || r[1].Text != "35.0°C" || r[1].Color == nil {
+		t.Errorf("temperature row = %+v", r)
+	}
+
+	fc := ForecastDocument(theme, &tempest.Forecast{Daily: []tempest.DailyForecast{
+		{Date: ts, HighTemp: 20, LowTemp: 10, Icon: "clear-day", Conditions: "Clear", PrecipChance: 5},
+		{Date: ts.AddDate(0, 0, 1), HighTemp: 21, LowTemp: 11},
+	}}, 1, units.MetricSet)
+	if rows := fc.Sections[0].Rows; len(rows) != 1 || rows[0][2].Text != "20.0°C" || rows[0][4].Text != "5%" {
+		t.Errorf("ForecastDocument rows = %+v", rows)
+	}
+	if empty := ForecastDocument(theme, &tempest.Forecast{}, 5, units.MetricSet); empty.Sections[0].Note == "" {
+		t.Error("empty forecast should have a note")
+	}
+
+	cols, err := ParseHistoryColumns([]string{"time", "temp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hist := HistoryDocument(theme, []tempest.Observation{{Timestamp: ts, AirTemperature: 12.5}}, cols, units.MetricSet, pressure.Reducer{})
+	sec := hist.Sections[0]
+	if len(sec.Columns) != 2 || sec.Columns[0].Align != AlignLeft || sec.Columns[1].Align != AlignRight {
+		t.Errorf("HistoryDocument columns = %+v", sec.Columns)
+	}
+	if len(sec.Rows) != 1 || !strings.Contains(sec.Rows[0][1].Text, "12.5") {
+		t.Errorf("HistoryDocument rows = %+v", sec.Rows)
+	}
+
+	st := StationsDocument(theme, []StationRow{{ConfigName: "home", StationID: 42, IsDefault: true, Online: false}})
+	row := st.Sections[0].Rows[0]
+	if row[0].Text != "✓" || row[3].Text != "42" || row[5].Text != "Offline" || row[5].Color == nil {
+		t.Errorf("StationsDocument row = %+v", row)
+	}
+}
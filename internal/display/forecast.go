@@ -4,15 +4,17 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	tempest "github.com/chadmayfield/tempest-go"
 	"github.com/charmbracelet/lipgloss"
 )
 
 // RenderForecast renders forecast day cards.
-func RenderForecast(theme *Theme, forecast *tempest.Forecast, days int, imperial bool, termWidth int) string {
+func RenderForecast(theme *Theme, forecast *tempest.Forecast, days int, u units.Set, termWidth int) string {
 	var b strings.Builder
+	l := theme.Locale
 
-	b.WriteString(theme.Title.Render("Forecast"))
+	b.WriteString(theme.Title.Render(l.T("forecast")))
 	b.WriteString("\n\n")
 
 	daily := forecast.Daily
@@ -21,7 +23,7 @@ func RenderForecast(theme *Theme, forecast *tempest.Forecast, days int, imperial
 	}
 
 	if len(daily) == 0 {
-		b.WriteString(theme.Muted.Render("No forecast data available"))
+		b.WriteString(theme.Muted.Render(l.T("no_forecast")))
 		return b.String()
 	}
 
@@ -29,7 +31,7 @@ func RenderForecast(theme *Theme, forecast *tempest.Forecast, days int, imperial
 	contents := make([]string, len(daily))
 	maxHeight := 0
 	for i, day := range daily {
-		contents[i] = forecastCardContent(theme, day, imperial)
+		contents[i] = forecastCardContent(theme, day, u)
 		h := lipgloss.Height(contents[i])
 		if h > maxHeight {
 			maxHeight = h
@@ -43,8 +45,18 @@ func RenderForecast(theme *Theme, forecast *tempest.Forecast, days int, imperial
 		cards[i] = cardStyle.Render(c)
 	}
 
-	// Determine layout: side-by-side if enough width, else vertical
-	cardWidth := lipgloss.Width(cards[0])
+	b.WriteString(layoutCards(cards, termWidth))
+	return b.String()
+}
+
+// layoutCards places cards side by side, wrapping into as many rows as the
+// terminal width needs.
+func layoutCards(cards []string, termWidth int) string {
+	var b strings.Builder
+	cardWidth := 0
+	for _, c := range cards {
+		cardWidth = max(cardWidth, lipgloss.Width(c))
+	}
 	cardsPerRow := termWidth / (cardWidth + 1)
 	if cardsPerRow < 1 {
 		cardsPerRow = 1
@@ -59,22 +71,22 @@ func RenderForecast(theme *Theme, forecast *tempest.Forecast, days int, imperial
 		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, row...))
 		b.WriteString("\n")
 	}
-
 	return b.String()
 }
 
-func forecastCardContent(theme *Theme, day tempest.DailyForecast, imperial bool) string {
+func forecastCardContent(theme *Theme, day tempest.DailyForecast, u units.Set) string {
 	var b strings.Builder
+	l := theme.Locale
 
 	// Date
-	dateStr := day.Date.Format("Mon Jan 2")
+	dateStr := l.DayLabel(day.Date)
 	b.WriteString(theme.Title.Render(dateStr))
 	b.WriteString("\n")
 
 	// Icon + conditions
 	var icon string
 	if theme.NoEmoji {
-		icon = ConditionLabel(day.Icon)
+		icon = ConditionLabel(day.Icon, l)
 	} else {
 		icon = ConditionIcon(day.Icon)
 	}
@@ -82,24 +94,66 @@ func forecastCardContent(theme *Theme, day tempest.DailyForecast, imperial bool)
 	b.WriteString("\n")
 
 	// High / Low
-	high := FormatTemp(day.HighTemp, imperial)
-	low := FormatTemp(day.LowTemp, imperial)
+	high := FormatTemp(day.HighTemp, u, l)
+	low := FormatTemp(day.LowTemp, u, l)
 	b.WriteString(theme.TempColor(day.HighTemp, high) + " / " + theme.TempColor(day.LowTemp, low))
 	b.WriteString("\n")
 
 	// Precip chance
 	if day.PrecipChance > 0 {
-		b.WriteString(fmt.Sprintf("Precip: %d%%", day.PrecipChance))
+		b.WriteString(l.T("precip_chance", day.PrecipChance))
 		b.WriteString("\n")
 	}
 
 	// Sunrise / Sunset
 	if !day.Sunrise.IsZero() {
-		b.WriteString(fmt.Sprintf("↑%s ↓%s",
-			day.Sunrise.Format("3:04pm"),
-			day.Sunset.Format("3:04pm")))
+		b.WriteString(fmt.Sprintf("↑%s ↓%s", l.Time(day.Sunrise), l.Time(day.Sunset)))
 		b.WriteString("\n")
 	}
 
 	return b.String()
 }
+
+// ForecastDocument returns the forecast as a table for markdown and HTML
+// output.
+func ForecastDocument(theme *Theme, forecast *tempest.Forecast, days int, u units.Set) Document {
+	l := theme.Locale
+	daily := forecast.Daily
+	if len(daily) > days {
+		daily = daily[:days]
+	}
+	sec := Section{
+		Columns: []DocColumn{
+			{Title: l.T("column_day")},
+			{Title: l.T("column_conditions")},
+			{Title: l.T("column_high"), Align: AlignRight},
+			{Title: l.T("column_low"), Align: AlignRight},
+			{Title: l.T("column_precip_chance"), Align: AlignRight},
+			{Title: l.T("column_sunrise"), Align: AlignRight},
+			{Title: l.T("column_sunset"), Align: AlignRight},
+		},
+	}
+	if len(daily) == 0 {
+		sec = Section{Note: l.T("no_forecast")}
+	}
+	for _, day := range daily {
+		icon := ConditionIcon(day.Icon)
+		if theme.NoEmoji {
+			icon = ConditionLabel(day.Icon, l)
+		}
+		sunrise, sunset := "", ""
+		if !day.Sunrise.IsZero() {
+			sunrise, sunset = l.Time(day.Sunrise), l.Time(day.Sunset)
+		}
+		sec.Rows = append(sec.Rows, []Cell{
+			{Text: l.DayLabel(day.Date)},
+			{Text: icon + " " + day.Conditions},
+			{Text: FormatTemp(day.HighTemp, u, l), Color: theme.bandColor("temperature", day.HighTemp)},
+			{Text: FormatTemp(day.LowTemp, u, l), Color: theme.bandColor("temperature", day.LowTemp)},
+			{Text: fmt.Sprintf("%d%%", day.PrecipChance)},
+			{Text: sunrise},
+			{Text: sunset},
+		})
+	}
+	return Document{Title: l.T("forecast"), Sections: []Section{sec}}
+}
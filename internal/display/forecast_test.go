@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	tempest "github.com/chadmayfield/tempest-go"
 )
 
@@ -36,7 +37,7 @@ func TestRenderForecast(t *testing.T) {
 		},
 	}
 
-	output := RenderForecast(theme, forecast, 5, false, 80)
+	output := RenderForecast(theme, forecast, 5, units.MetricSet, 80)
 
 	if !strings.Contains(output, "Forecast") {
 		t.Error("missing title")
@@ -52,7 +53,7 @@ func TestRenderForecast(t *testing.T) {
 	}
 
 	// Imperial
-	output = RenderForecast(theme, forecast, 5, true, 80)
+	output = RenderForecast(theme, forecast, 5, units.ImperialSet, 80)
 	if !strings.Contains(output, "°F") {
 		t.Error("missing fahrenheit")
 	}
@@ -73,7 +74,7 @@ func TestRenderForecastSingleDay(t *testing.T) {
 		},
 	}
 
-	output := RenderForecast(theme, forecast, 1, false, 80)
+	output := RenderForecast(theme, forecast, 1, units.MetricSet, 80)
 	if !strings.Contains(output, "Clear") {
 		t.Error("missing conditions for single day")
 	}
@@ -94,7 +95,7 @@ func TestRenderForecastTenDays(t *testing.T) {
 	}
 
 	forecast := &tempest.Forecast{Daily: days}
-	output := RenderForecast(theme, forecast, 10, false, 120)
+	output := RenderForecast(theme, forecast, 10, units.MetricSet, 120)
 	if !strings.Contains(output, "Forecast") {
 		t.Error("missing title for 10-day forecast")
 	}
@@ -111,7 +112,7 @@ func TestRenderForecastEmpty(t *testing.T) {
 	theme := NewTheme(true)
 	forecast := &tempest.Forecast{}
 
-	output := RenderForecast(theme, forecast, 5, false, 80)
+	output := RenderForecast(theme, forecast, 5, units.MetricSet, 80)
 	if !strings.Contains(output, "No forecast data") {
 		t.Error("missing empty message")
 	}
@@ -127,7 +128,7 @@ func TestRenderForecastNarrowTerminal(t *testing.T) {
 	}
 
 	// Very narrow terminal should still render
-	output := RenderForecast(theme, forecast, 5, false, 20)
+	output := RenderForecast(theme, forecast, 5, units.MetricSet, 20)
 	if output == "" {
 		t.Error("expected non-empty output for narrow terminal")
 	}
@@ -148,7 +149,7 @@ func TestRenderForecastNoEmoji(t *testing.T) {
 		},
 	}
 
-	output := RenderForecast(theme, forecast, 5, false, 80)
+	output := RenderForecast(theme, forecast, 5, units.MetricSet, 80)
 	if !strings.Contains(output, "[clear]") {
 		t.Error("expected [clear] text label in no-emoji mode")
 	}
@@ -0,0 +1,153 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
+	"github.com/charmbracelet/bubbles/table"
+)
+
+// StationHealth holds the health summary for a single station.
+type StationHealth struct {
+	StationRow
+	StaleAfter time.Duration
+	// Battery is the sensor battery voltage; zero if unknown.
+	Battery       float64
+	LastRain      time.Time
+	LastLightning time.Time
+	// SensorRSSI is how well the sensor hears the hub and HubRSSI how well
+	// the hub hears the sensor, in dBm; zero if unknown.
+	SensorRSSI, HubRSSI int
+	// Problems lists everything that makes the station degraded.
+	Problems []string
+}
+
+// Degraded reports whether the station has any health problems.
+func (h StationHealth) Degraded() bool {
+	return len(h.Problems) > 0
+}
+
+// PowerMode infers the Tempest sensor's power-save mode from its battery
+// voltage. Mode 0 samples at full rate; modes 1-3 progressively reduce the
+// wind and observation intervals to save power. It returns -1 if the voltage
+// is unknown.
+func PowerMode(volts float64) int {
+	switch {
+	case volts <= 0:
+		return -1
+	case volts >= 2.455:
+		return 0
+	case volts >= 2.41:
+		return 1
+	case volts >= 2.375:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// PowerModeLabel returns a short description of a power-save mode.
+func PowerModeLabel(mode int, l *i18n.Locale) string {
+	switch mode {
+	case -1:
+		return l.T("unknown")
+	case 0:
+		return l.T("power_full")
+	default:
+		return l.T("power_save", mode)
+	}
+}
+
+// RenderStationHealth renders the health view of the stations table.
+func RenderStationHealth(theme *Theme, rows []StationHealth) string {
+	var b strings.Builder
+	l := theme.Locale
+
+	b.WriteString(theme.Title.Render(l.T("station_health")))
+	b.WriteString("\n\n")
+
+	columns := []table.Column{
+		{Title: "", Width: 1},
+		{Title: l.T("column_name"), Width: 12},
+		{Title: l.T("column_health"), Width: 9},
+		{Title: l.T("column_last_seen"), Width: 12},
+		{Title: l.T("column_stale"), Width: 6},
+		{Title: l.T("column_battery"), Width: 12},
+		{Title: l.T("column_power"), Width: 7},
+		{Title: l.T("column_signal"), Width: 13},
+		{Title: l.T("column_last_rain"), Width: 10},
+		{Title: l.T("column_lightning"), Width: 10},
+		{Title: l.T("column_problems"), Width: 30},
+	}
+
+	var tableRows []table.Row
+	degraded := 0
+	for _, r := range rows {
+		def := " "
+		if r.IsDefault {
+			def = "*"
+		}
+
+		health := l.T("health_ok")
+		if r.Degraded() {
+			health = l.T("health_degraded")
+			degraded++
+		}
+
+		battery := l.T("unknown")
+		if r.Battery > 0 {
+			battery = fmt.Sprintf("%sV %s", l.Number(r.Battery, 2), l.T(batteryLevel(r.Battery)))
+		}
+
+		signal := l.T("unknown")
+		if r.SensorRSSI != 0 || r.HubRSSI != 0 {
+			signal = fmt.Sprintf("%d/%d dBm", r.SensorRSSI, r.HubRSSI)
+		}
+
+		tableRows = append(tableRows, table.Row{
+			def, r.ConfigName, health,
+			agoOr(r.LastObserved, l.T("never"), l), shortDuration(r.StaleAfter),
+			battery, PowerModeLabel(PowerMode(r.Battery), l), signal,
+			agoOr(r.LastRain, l.T("none"), l), agoOr(r.LastLightning, l.T("none"), l),
+			strings.Join(r.Problems, ", "),
+		})
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(tableRows),
+		table.WithHeight(len(tableRows)+1),
+	)
+	t.SetStyles(stationTableStyles(theme))
+
+	b.WriteString(t.View())
+	b.WriteString("\n\n")
+	if degraded == 0 {
+		b.WriteString(theme.Label.Render(l.T("all_stations_healthy")))
+	} else {
+		b.WriteString(theme.Label.Render(l.T("stations_degraded", degraded, len(rows))))
+	}
+
+	return b.String()
+}
+
+func agoOr(t time.Time, zero string, l *i18n.Locale) string {
+	if t.IsZero() {
+		return zero
+	}
+	return l.T("ago", timeAgo(t, l))
+}
+
+// shortDuration formats d without trailing zero units, e.g. "30m" or "6h".
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
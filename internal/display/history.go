@@ -4,59 +4,53 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/chadmayfield/tempest-cli/internal/pressure"
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	tempest "github.com/chadmayfield/tempest-go"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/lipgloss"
 )
 
 // RenderHistory renders a table of historical observations using bubbles/table.
-func RenderHistory(theme *Theme, observations []tempest.Observation, imperial bool, termWidth int) string {
+// Pressures are shown relative to p's reference. When the columns do not fit
+// termWidth the lowest-priority ones are hidden and listed below the table.
+func RenderHistory(theme *Theme, observations []tempest.Observation, cols []HistoryColumn, u units.Set, p pressure.Reducer, termWidth int) string {
 	var b strings.Builder
+	l := theme.Locale
 
-	b.WriteString(theme.Title.Render("History"))
-	b.WriteString(fmt.Sprintf("  %s\n\n", theme.Muted.Render(fmt.Sprintf("%d observations", len(observations)))))
+	b.WriteString(theme.Title.Render(l.T("history")))
+	b.WriteString(fmt.Sprintf("  %s\n\n", theme.Muted.Render(l.T("observation_count", len(observations)))))
 
 	if len(observations) == 0 {
-		b.WriteString(theme.Muted.Render("No observations in this time range"))
+		b.WriteString(theme.Muted.Render(l.T("no_observations")))
 		return b.String()
 	}
 
-	columns := []table.Column{
-		{Title: "Time", Width: 16},
-		{Title: "Temp", Width: 10},
-		{Title: "Feels Like", Width: 12},
-		{Title: "Hum%", Width: 7},
-		{Title: "Wind", Width: 14},
-		{Title: "Pressure", Width: 12},
-		{Title: "Rain", Width: 9},
-		{Title: "UV", Width: 5},
+	titles := make([]string, len(cols))
+	widths := make([]int, len(cols))
+	for i, c := range cols {
+		titles[i], widths[i] = historyColumnWidth(c, theme, p)
 	}
-
-	// Adjust column widths to fit terminal
-	totalWidth := 0
-	for _, c := range columns {
-		totalWidth += c.Width + 1 // +1 for separator
-	}
-	if termWidth > 0 && totalWidth > termWidth {
-		scale := float64(termWidth) / float64(totalWidth)
-		for i := range columns {
-			columns[i].Width = max(int(float64(columns[i].Width)*scale), 4)
-		}
+	// The colored table pads every header and cell by one on each side.
+	pad := 2
+	if theme.NoColor {
+		pad = 0
 	}
+	kept, dropped := fitHistoryColumns(cols, widths, pad, termWidth)
 
-	var rows []table.Row
-	for _, obs := range observations {
-		ts := obs.Timestamp.Format("01-02 15:04")
-		temp := FormatTemp(obs.AirTemperature, imperial)
-		feels := FormatTemp(obs.FeelsLike, imperial)
-		hum := fmt.Sprintf("%.0f%%", obs.RelativeHumidity)
-		compass := tempest.WindDirectionToCompass(obs.WindDirection)
-		wind := fmt.Sprintf("%s %s", FormatWind(obs.WindAvg, imperial), compass)
-		pressure := FormatPressure(obs.StationPressure, imperial)
-		rain := FormatPrecip(obs.RainAccumulation, imperial)
-		uv := fmt.Sprintf("%.1f", obs.UVIndex)
+	columns := make([]table.Column, len(kept))
+	for k, i := range kept {
+		columns[k] = table.Column{Title: titles[i], Width: widths[i]}
+	}
 
-		rows = append(rows, table.Row{ts, temp, feels, hum, wind, pressure, rain, uv})
+	ctx := historyCellContext{theme: theme, u: u, p: p}
+	rows := make([]table.Row, len(observations))
+	for r := range observations {
+		row := make(table.Row, len(kept))
+		for k, i := range kept {
+			row[k] = cols[i].cell(&observations[r], ctx)
+		}
+		rows[r] = row
 	}
 
 	t := table.New(
@@ -74,15 +68,58 @@ func RenderHistory(theme *Theme, observations []tempest.Observation, imperial bo
 		s.Header = lipgloss.NewStyle().
 			Bold(true).
 			Padding(0, 1).
-			Foreground(lipgloss.AdaptiveColor{Light: "#666666", Dark: "#999999"}).
+			Foreground(theme.Label.GetForeground()).
 			BorderBottom(true).
 			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.AdaptiveColor{Light: "#cccccc", Dark: "#444444"})
+			BorderForeground(theme.Border.GetBorderTopForeground())
 		s.Selected = lipgloss.NewStyle()
 	}
 	t.SetStyles(s)
 
 	b.WriteString(t.View())
+	if len(dropped) > 0 {
+		b.WriteString("\n")
+		b.WriteString(theme.Muted.Render(l.T("columns_hidden", strings.Join(dropped, ", "))))
+	}
 
 	return b.String()
 }
+
+// HistoryDocument returns observations as a table of cols for markdown and
+// HTML output. Every column is kept; there is no terminal to fit.
+func HistoryDocument(theme *Theme, observations []tempest.Observation, cols []HistoryColumn, u units.Set, p pressure.Reducer) Document {
+	l := theme.Locale
+	sec := Section{Note: l.T("observation_count", len(observations))}
+	if len(observations) == 0 {
+		sec.Note = l.T("no_observations")
+	}
+	for _, c := range cols {
+		title, _ := historyColumnWidth(c, theme, p)
+		align := AlignRight
+		if c.Key == "time" || c.Key == "precip_type" {
+			align = AlignLeft
+		}
+		sec.Columns = append(sec.Columns, DocColumn{Title: title, Align: align})
+	}
+	ctx := historyCellContext{theme: theme, u: u, p: p}
+	for i := range observations {
+		row := make([]Cell, len(cols))
+		for k, c := range cols {
+			row[k] = Cell{Text: c.cell(&observations[i], ctx)}
+		}
+		sec.Rows = append(sec.Rows, row)
+	}
+	return Document{Title: l.T("history"), Sections: []Section{sec}}
+}
+
+// pressureTitle returns the message key naming the pressure column after its
+// reference.
+func pressureTitle(ref pressure.Reference) string {
+	switch ref {
+	case pressure.Station:
+		return "column_station_pressure"
+	case pressure.Altimeter:
+		return "column_altimeter"
+	}
+	return "column_pressure"
+}
@@ -5,6 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
+	"github.com/chadmayfield/tempest-cli/internal/pressure"
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	tempest "github.com/chadmayfield/tempest-go"
 )
 
@@ -36,7 +39,7 @@ func TestRenderHistory(t *testing.T) {
 		},
 	}
 
-	output := RenderHistory(theme, obs, false, 120)
+	output := RenderHistory(theme, obs, defaultHistoryColumns(t), units.MetricSet, pressure.Reducer{Reference: pressure.Station}, 120)
 
 	if !strings.Contains(output, "History") {
 		t.Error("missing title")
@@ -44,7 +47,7 @@ func TestRenderHistory(t *testing.T) {
 	if !strings.Contains(output, "2 observations") {
 		t.Error("missing observation count")
 	}
-	if !strings.Contains(output, "01-15") {
+	if !strings.Contains(output, "01/15 10:00am") {
 		t.Error("missing date")
 	}
 	if !strings.Contains(output, "°C") {
@@ -55,7 +58,7 @@ func TestRenderHistory(t *testing.T) {
 func TestRenderHistoryEmpty(t *testing.T) {
 	theme := NewTheme(true)
 
-	output := RenderHistory(theme, nil, false, 80)
+	output := RenderHistory(theme, nil, defaultHistoryColumns(t), units.MetricSet, pressure.Reducer{Reference: pressure.Station}, 80)
 	if !strings.Contains(output, "No observations") {
 		t.Error("missing empty message")
 	}
@@ -75,7 +78,7 @@ func TestRenderHistoryImperial(t *testing.T) {
 		},
 	}
 
-	output := RenderHistory(theme, obs, true, 120)
+	output := RenderHistory(theme, obs, defaultHistoryColumns(t), units.ImperialSet, pressure.Reducer{Reference: pressure.Station}, 120)
 	if !strings.Contains(output, "°F") {
 		t.Error("missing fahrenheit")
 	}
@@ -92,8 +95,107 @@ func TestRenderHistoryNarrowTerminal(t *testing.T) {
 		},
 	}
 
-	output := RenderHistory(theme, obs, false, 40)
+	output := RenderHistory(theme, obs, defaultHistoryColumns(t), units.MetricSet, pressure.Reducer{Reference: pressure.Station}, 40)
 	if output == "" {
 		t.Error("expected non-empty output for narrow terminal")
 	}
 }
+
+func TestRenderHistoryLocalized(t *testing.T) {
+	de, err := i18n.Lookup("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	theme := NewTheme(true, WithLocale(de))
+	obs := []tempest.Observation{{
+		Timestamp:      time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC),
+		AirTemperature: 22.5,
+		WindAvg:        3.5,
+		WindDirection:  90,
+	}}
+
+	output := RenderHistory(theme, obs, defaultHistoryColumns(t), units.MetricSet, pressure.Reducer{Reference: pressure.Station}, 120)
+	for _, want := range []string{"Verlauf", "1 Messungen", "15.01 14:30", "22,5°C", "3,5 m/s O", "Stn.-Druck"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("missing %q in:\n%s", want, output)
+		}
+	}
+}
+
+func defaultHistoryColumns(t *testing.T) []HistoryColumn {
+	t.Helper()
+	cols, err := ParseHistoryColumns(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cols
+}
+
+func TestParseHistoryColumns(t *testing.T) {
+	cols, err := ParseHistoryColumns([]string{"Gust", "temperature", "dir", "gust", " solar "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, c := range cols {
+		keys = append(keys, c.Key)
+	}
+	if got := strings.Join(keys, ","); got != "time,gust,temp,dir,solar" {
+		t.Errorf("columns = %s, want time first, aliases resolved and duplicates dropped", got)
+	}
+
+	if _, err := ParseHistoryColumns([]string{"temp", "humidex"}); err == nil || !strings.Contains(err.Error(), `unknown column "humidex"`) {
+		t.Errorf("unknown column: err = %v", err)
+	}
+}
+
+func TestSortHistory(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	obs := []tempest.Observation{
+		{Timestamp: base, WindGust: 5},
+		{Timestamp: base.Add(time.Hour), WindGust: 9},
+		{Timestamp: base.Add(2 * time.Hour), WindGust: 5},
+	}
+	col, desc, err := ParseHistorySort("gust:desc")
+	if err != nil || !desc {
+		t.Fatalf("ParseHistorySort = %v, %v", desc, err)
+	}
+	SortHistory(obs, col, desc, pressure.Reducer{})
+	if obs[0].WindGust != 9 || !obs[1].Timestamp.Equal(base) {
+		t.Errorf("sorted = %+v, want the 9 gust first and ties in time order", obs)
+	}
+
+	// At altitude a cold reading can be lower at the station but higher
+	// once reduced to sea level.
+	obs = []tempest.Observation{
+		{Timestamp: base, StationPressure: 850.5, AirTemperature: 35},
+		{Timestamp: base.Add(time.Hour), StationPressure: 850, AirTemperature: -10},
+	}
+	col, _ = LookupHistoryColumn("pressure")
+	reducer := pressure.Reducer{Reference: pressure.SeaLevel, ElevationM: 1500}
+	SortHistory(obs, col, false, reducer)
+	if col.Value(&obs[0], units.MetricSet, reducer) > col.Value(&obs[1], units.MetricSet, reducer) {
+		t.Errorf("sorted = %+v, want ascending sea-level pressure", obs)
+	}
+	if _, _, err := ParseHistorySort("gust:up"); err == nil {
+		t.Error("expected an error for an unknown sort order")
+	}
+}
+
+func TestRenderHistoryDropsColumns(t *testing.T) {
+	theme := NewTheme(true)
+	obs := []tempest.Observation{{Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), AirTemperature: 22.5}}
+	cols, err := ParseHistoryColumns([]string{"temp", "lux", "wind", "interval"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// time(16) + temp(10) + wind(14) fit in 45; lux and interval do not.
+	output := RenderHistory(theme, obs, cols, units.MetricSet, pressure.Reducer{}, 45)
+	if !strings.Contains(output, "22.5°C") || !strings.Contains(output, "Wind") {
+		t.Errorf("high-priority columns missing:\n%s", output)
+	}
+	if strings.Contains(output, "Lux") || !strings.Contains(output, "Hidden to fit the terminal: interval, lux") {
+		t.Errorf("expected lux and interval to be hidden:\n%s", output)
+	}
+}
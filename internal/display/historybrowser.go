@@ -0,0 +1,580 @@
+package display
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/pressure"
+	"github.com/chadmayfield/tempest-cli/internal/units"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// HistoryLoader fetches the observations between start and end, prepared the
+// same way as the ones the browser started with.
+type HistoryLoader func(start, end time.Time) ([]tempest.Observation, error)
+
+// HistoryBrowserOptions configures NewHistoryBrowser.
+type HistoryBrowserOptions struct {
+	Columns  []HistoryColumn
+	Units    units.Set
+	Pressure pressure.Reducer
+	// Sort, if set, orders rows by a column like --sort.
+	Sort     *HistoryColumn
+	SortDesc bool
+	// Start and End are the window the observations were fetched for.
+	Start, End time.Time
+	// Load fetches another window when stepping days or jumping outside the
+	// current one.
+	Load HistoryLoader
+	// RowJSON encodes the selected row for copying, and Clipboard receives
+	// the OSC 52 sequence that puts it on the terminal's clipboard. Clipboard
+	// must be a terminal; if it is nil, copying reports that it is unavailable.
+	RowJSON   func(o *tempest.Observation) ([]byte, error)
+	Clipboard io.Writer
+}
+
+// browserPrompt is the line the browser is reading input for, if any.
+type browserPrompt int
+
+const (
+	promptNone browserPrompt = iota
+	promptGoto
+	promptFilter
+)
+
+// HistoryBrowser is the interactive history table: a bubbletea model that
+// scrolls, filters and charts observations and steps through days.
+type HistoryBrowser struct {
+	theme *Theme
+	opts  HistoryBrowserOptions
+
+	start, end time.Time
+	obs        []tempest.Observation
+	shown      []int // indexes into obs that pass the filter
+	filter     historyFilter
+	kept       []int    // indexes into opts.Columns that fit the terminal
+	dropped    []string // keys of the columns that do not
+	table      table.Model
+
+	col    int // column charted, an index into opts.Columns
+	chart  bool
+	prompt browserPrompt
+	input  string
+	status string
+	err    error
+	// pendingGoto is where to put the cursor once a load finishes.
+	pendingGoto *time.Time
+	loading     bool
+
+	width, height int
+}
+
+type historyLoadedMsg struct {
+	start, end time.Time
+	obs        []tempest.Observation
+	err        error
+}
+
+type browserStatusMsg struct {
+	status string
+	err    error
+}
+
+// NewHistoryBrowser starts a browser on obs.
+func NewHistoryBrowser(theme *Theme, obs []tempest.Observation, opts HistoryBrowserOptions) HistoryBrowser {
+	m := HistoryBrowser{
+		theme:  theme,
+		opts:   opts,
+		start:  opts.Start,
+		end:    opts.End,
+		width:  80,
+		height: 24,
+		table:  table.New(table.WithFocused(true)),
+	}
+	// Chart the first column after the time, if any.
+	for i, c := range opts.Columns {
+		if c.Key != "time" {
+			m.col = i
+			break
+		}
+	}
+	m.setObservations(obs)
+	return m
+}
+
+func (m HistoryBrowser) Init() tea.Cmd {
+	return nil
+}
+
+func (m HistoryBrowser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.rebuild()
+
+	case historyLoadedMsg:
+		m.loading = false
+		m.status = ""
+		if msg.err != nil {
+			m.err = msg.err
+			m.pendingGoto = nil
+			return m, nil
+		}
+		m.err = nil
+		m.start, m.end = msg.start, msg.end
+		m.setObservations(msg.obs)
+		if m.pendingGoto != nil {
+			m.selectNearest(*m.pendingGoto)
+			m.pendingGoto = nil
+		}
+
+	case browserStatusMsg:
+		m.status, m.err = msg.status, msg.err
+
+	case tea.KeyMsg:
+		if m.prompt != promptNone {
+			return m.promptKey(msg)
+		}
+		m.status, m.err = "", nil
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if m.filter.spec != "" {
+				m.filter = historyFilter{}
+				m.rebuild()
+			}
+		case "left", "h":
+			m.stepColumn(-1)
+		case "right", "l":
+			m.stepColumn(1)
+		case "[":
+			return m.stepDay(-1)
+		case "]":
+			return m.stepDay(1)
+		case "t":
+			m.prompt, m.input = promptGoto, ""
+		case "/":
+			m.prompt, m.input = promptFilter, m.filter.spec
+		case "c":
+			m.chart = !m.chart
+			m.rebuild()
+		case "y":
+			return m, m.copyRow()
+		default:
+			var cmd tea.Cmd
+			m.table, cmd = m.table.Update(msg)
+			return m, cmd
+		}
+	}
+	return m, nil
+}
+
+// promptKey edits the prompt line, applying it on Enter.
+func (m HistoryBrowser) promptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.prompt = promptNone
+	case "enter":
+		prompt, input := m.prompt, strings.TrimSpace(m.input)
+		m.prompt = promptNone
+		if prompt == promptGoto {
+			return m.gotoInput(input)
+		}
+		f, err := parseHistoryFilter(input)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.filter = f
+		m.rebuild()
+		m.table.GotoTop()
+	case "backspace":
+		if r := []rune(m.input); len(r) > 0 {
+			m.input = string(r[:len(r)-1])
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.input += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+func (m *HistoryBrowser) setObservations(obs []tempest.Observation) {
+	m.obs = obs
+	if m.opts.Sort != nil {
+		SortHistory(m.obs, *m.opts.Sort, m.opts.SortDesc, m.opts.Pressure)
+	}
+	m.rebuild()
+	m.table.GotoTop()
+}
+
+// rebuild refilters the rows and refits the table to the terminal.
+func (m *HistoryBrowser) rebuild() {
+	cols := m.opts.Columns
+	ctx := historyCellContext{theme: m.theme, u: m.opts.Units, p: m.opts.Pressure}
+
+	m.shown = m.shown[:0]
+	for i := range m.obs {
+		if m.filter.match(&m.obs[i], cols, ctx) {
+			m.shown = append(m.shown, i)
+		}
+	}
+
+	titles := make([]string, len(cols))
+	widths := make([]int, len(cols))
+	for i, c := range cols {
+		titles[i], widths[i] = historyColumnWidth(c, m.theme, m.opts.Pressure)
+	}
+	pad := 2
+	if m.theme.NoColor {
+		pad = 0
+	}
+	m.kept, m.dropped = fitHistoryColumns(cols, widths, pad, m.width)
+
+	columns := make([]table.Column, len(m.kept))
+	for k, i := range m.kept {
+		columns[k] = table.Column{Title: titles[i], Width: widths[i]}
+	}
+	rows := make([]table.Row, len(m.shown))
+	for r, o := range m.shown {
+		row := make(table.Row, len(m.kept))
+		for k, i := range m.kept {
+			row[k] = cols[i].cell(&m.obs[o], ctx)
+		}
+		rows[r] = row
+	}
+
+	// Clear the rows first: the table redraws them against the new columns.
+	m.table.SetRows(nil)
+	m.table.SetColumns(columns)
+	m.table.SetRows(rows)
+	m.table.SetStyles(m.tableStyles())
+	m.table.SetHeight(max(3, m.height-m.chrome()))
+	m.table.SetCursor(min(m.table.Cursor(), max(0, len(rows)-1)))
+}
+
+// chrome is the number of lines the view uses around the table.
+func (m *HistoryBrowser) chrome() int {
+	n := 5 // title, blank, blank, status and help
+	if len(m.dropped) > 0 {
+		n++
+	}
+	if m.chart {
+		n += 4
+	}
+	return n
+}
+
+func (m *HistoryBrowser) tableStyles() table.Styles {
+	s := table.DefaultStyles()
+	// Reverse video marks the cursor even without color.
+	s.Selected = lipgloss.NewStyle().Reverse(true)
+	if m.theme.NoColor {
+		s.Header = lipgloss.NewStyle().Bold(true)
+		s.Cell = lipgloss.NewStyle()
+		return s
+	}
+	s.Header = lipgloss.NewStyle().
+		Bold(true).
+		Padding(0, 1).
+		Foreground(m.theme.Label.GetForeground()).
+		BorderBottom(true).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(m.theme.Border.GetBorderTopForeground())
+	return s
+}
+
+// selected returns the observation under the cursor, or nil.
+func (m *HistoryBrowser) selected() *tempest.Observation {
+	c := m.table.Cursor()
+	if c < 0 || c >= len(m.shown) {
+		return nil
+	}
+	return &m.obs[m.shown[c]]
+}
+
+// stepColumn moves the charted column, skipping the time column.
+func (m *HistoryBrowser) stepColumn(d int) {
+	n := len(m.opts.Columns)
+	for i := 1; i < n; i++ {
+		c := ((m.col+d*i)%n + n) % n
+		if m.opts.Columns[c].Key != "time" {
+			m.col = c
+			return
+		}
+	}
+}
+
+// stepDay moves the window a day earlier or later and fetches it.
+func (m HistoryBrowser) stepDay(d int) (tea.Model, tea.Cmd) {
+	start, end := m.start.AddDate(0, 0, d), m.end.AddDate(0, 0, d)
+	if d > 0 && start.After(time.Now()) {
+		m.status = m.theme.Locale.T("browse_no_later")
+		return m, nil
+	}
+	return m.load(start, end)
+}
+
+func (m HistoryBrowser) load(start, end time.Time) (tea.Model, tea.Cmd) {
+	if m.loading || m.opts.Load == nil {
+		return m, nil
+	}
+	m.loading = true
+	m.status = m.theme.Locale.T("browse_loading", m.theme.Locale.DayLabel(start))
+	load := m.opts.Load
+	return m, func() tea.Msg {
+		obs, err := load(start, end)
+		return historyLoadedMsg{start: start, end: end, obs: obs, err: err}
+	}
+}
+
+// Layouts accepted by the go-to prompt, in local time.
+var gotoLayouts = []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+// gotoInput moves the cursor to the row nearest a date, time or both,
+// loading the day it falls on if it is outside the window. A time alone is
+// on the selected row's day.
+func (m HistoryBrowser) gotoInput(input string) (tea.Model, tea.Cmd) {
+	target, ok := time.Time{}, false
+	for _, layout := range gotoLayouts {
+		if t, err := time.ParseInLocation(layout, input, time.Local); err == nil {
+			target, ok = t, true
+			break
+		}
+	}
+	if !ok {
+		clock, err := time.ParseInLocation("15:04", input, time.Local)
+		if err != nil {
+			m.err = fmt.Errorf("%s", m.theme.Locale.T("browse_bad_time", input))
+			return m, nil
+		}
+		day := m.start
+		if o := m.selected(); o != nil {
+			day = o.Timestamp
+		}
+		day = day.In(time.Local)
+		target = time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+	}
+
+	if !target.Before(m.start) && target.Before(m.end) {
+		m.selectNearest(target)
+		return m, nil
+	}
+	day := time.Date(target.Year(), target.Month(), target.Day(), 0, 0, 0, 0, time.Local)
+	m.pendingGoto = &target
+	return m.load(day, day.Add(m.end.Sub(m.start)))
+}
+
+// selectNearest puts the cursor on the shown row closest to t.
+func (m *HistoryBrowser) selectNearest(t time.Time) {
+	best, bestDiff := -1, time.Duration(math.MaxInt64)
+	for r, i := range m.shown {
+		d := m.obs[i].Timestamp.Sub(t)
+		if d < 0 {
+			d = -d
+		}
+		if d < bestDiff {
+			best, bestDiff = r, d
+		}
+	}
+	if best >= 0 {
+		m.table.SetCursor(best)
+	}
+}
+
+// copyRow puts the selected row on the clipboard as JSON.
+func (m *HistoryBrowser) copyRow() tea.Cmd {
+	o, l := m.selected(), m.theme.Locale
+	if o == nil || m.opts.RowJSON == nil {
+		return nil
+	}
+	if m.opts.Clipboard == nil {
+		return func() tea.Msg {
+			return browserStatusMsg{err: errors.New(l.T("browse_no_clipboard"))}
+		}
+	}
+	row := *o
+	encode, w := m.opts.RowJSON, m.opts.Clipboard
+	return func() tea.Msg {
+		data, err := encode(&row)
+		if err == nil {
+			_, err = io.WriteString(w, ansi.SetSystemClipboard(string(data)))
+		}
+		if err != nil {
+			return browserStatusMsg{err: err}
+		}
+		return browserStatusMsg{status: l.T("browse_copied")}
+	}
+}
+
+func (m HistoryBrowser) View() string {
+	var b strings.Builder
+	theme, l := m.theme, m.theme.Locale
+
+	b.WriteString(theme.Title.Render(l.T("history")))
+	b.WriteString("  " + theme.Muted.Render(fmt.Sprintf("%s – %s · %s",
+		l.DateTime(m.start), l.DateTime(m.end), l.T("observation_count", len(m.shown)))))
+	if m.filter.spec != "" {
+		b.WriteString("  " + theme.Warning.Render(l.T("browse_filtered", m.filter.spec)))
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case len(m.obs) == 0:
+		b.WriteString(theme.Muted.Render(l.T("no_observations")))
+	case len(m.shown) == 0:
+		b.WriteString(theme.Muted.Render(l.T("browse_no_match")))
+	default:
+		b.WriteString(m.table.View())
+	}
+	if len(m.dropped) > 0 {
+		b.WriteString("\n" + theme.Muted.Render(l.T("columns_hidden", strings.Join(m.dropped, ", "))))
+	}
+	if m.chart {
+		b.WriteString("\n\n" + m.chartView())
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case m.prompt == promptGoto:
+		b.WriteString(l.T("browse_goto_prompt") + " " + m.input + "█")
+	case m.prompt == promptFilter:
+		b.WriteString(l.T("browse_filter_prompt") + " " + m.input + "█")
+	case m.err != nil:
+		b.WriteString(theme.Error.Render(m.err.Error()))
+	case m.status != "":
+		b.WriteString(theme.Muted.Render(m.status))
+	}
+	b.WriteString("\n" + theme.Muted.Render(l.T("browse_help")))
+	return b.String()
+}
+
+// sparkLevels are the bar heights of the chart, lowest first.
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// chartView charts the selected column over the shown rows, with a caret
+// under the cursor's row.
+func (m *HistoryBrowser) chartView() string {
+	l := m.theme.Locale
+	if len(m.opts.Columns) == 0 || m.opts.Columns[m.col].Key == "time" || len(m.shown) == 0 {
+		return m.theme.Muted.Render(l.T("browse_chart_none")) + "\n\n"
+	}
+	c := m.opts.Columns[m.col]
+	values := make([]float64, len(m.shown))
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for r, i := range m.shown {
+		v := c.Value(&m.obs[i], m.opts.Units, m.opts.Pressure)
+		values[r] = v
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	line, mark := sparkline(values, max(10, m.width-1), m.table.Cursor())
+
+	title, _ := historyColumnWidth(c, m.theme, m.opts.Pressure)
+	current := values[min(m.table.Cursor(), len(values)-1)]
+	summary := l.T("browse_chart", l.Number(lo, 1), l.Number(hi, 1), l.Number(current, 1))
+	return m.theme.Label.Render(title) + "  " + m.theme.Muted.Render(summary) + "\n" +
+		m.theme.Value.Render(line) + "\n" + strings.Repeat(" ", mark) + "^"
+}
+
+// sparkline draws values in at most width cells, averaging values that share
+// a cell. It returns the line and the cell holding value cursor.
+func sparkline(values []float64, width, cursor int) (string, int) {
+	n := len(values)
+	cells := min(n, width)
+	if cells == 0 {
+		return "", 0
+	}
+	avg := make([]float64, cells)
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for c := range cells {
+		from, to := c*n/cells, (c+1)*n/cells
+		sum := 0.0
+		for _, v := range values[from:to] {
+			sum += v
+		}
+		avg[c] = sum / float64(to-from)
+		lo, hi = min(lo, avg[c]), max(hi, avg[c])
+	}
+	var b strings.Builder
+	for _, v := range avg {
+		level := 0
+		if hi > lo {
+			level = int(math.Round((v - lo) / (hi - lo) * float64(len(sparkLevels)-1)))
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String(), cursor * cells / n
+}
+
+// historyFilter keeps rows whose cells contain some text, or whose value in
+// a column compares to a number, e.g. "gust>10" in the displayed units.
+type historyFilter struct {
+	spec string
+	text string
+	col  *HistoryColumn
+	op   string
+	v    float64
+}
+
+var comparisonFilter = regexp.MustCompile(`^([a-z_]+)\s*(<=|>=|!=|<|>|=)\s*(-?[0-9]+(?:[.,][0-9]+)?)$`)
+
+func parseHistoryFilter(spec string) (historyFilter, error) {
+	f := historyFilter{spec: spec}
+	m := comparisonFilter.FindStringSubmatch(strings.ToLower(spec))
+	if m == nil {
+		f.text = strings.ToLower(spec)
+		return f, nil
+	}
+	col, err := LookupHistoryColumn(m[1])
+	if err != nil {
+		return historyFilter{}, err
+	}
+	v, err := strconv.ParseFloat(strings.Replace(m[3], ",", ".", 1), 64)
+	if err != nil {
+		return historyFilter{}, err
+	}
+	f.col, f.op, f.v = &col, m[2], v
+	return f, nil
+}
+
+func (f historyFilter) match(o *tempest.Observation, cols []HistoryColumn, c historyCellContext) bool {
+	if f.col != nil {
+		v := f.col.Value(o, c.u, c.p)
+		switch f.op {
+		case "<":
+			return v < f.v
+		case "<=":
+			return v <= f.v
+		case ">":
+			return v > f.v
+		case ">=":
+			return v >= f.v
+		case "!=":
+			return v != f.v
+		}
+		return v == f.v
+	}
+	if f.text == "" {
+		return true
+	}
+	for _, col := range cols {
+		if strings.Contains(strings.ToLower(col.cell(o, c)), f.text) {
+			return true
+		}
+	}
+	return false
+}
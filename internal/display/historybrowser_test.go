@@ -0,0 +1,213 @@
+package display
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/pressure"
+	"github.com/chadmayfield/tempest-cli/internal/units"
+	tempest "github.com/chadmayfield/tempest-go"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// browserDay returns hourly observations for the local day starting at day,
+// with the gust rising by 1 m/s an hour.
+func browserDay(day time.Time) []tempest.Observation {
+	obs := make([]tempest.Observation, 24)
+	for h := range obs {
+		obs[h] = tempest.Observation{
+			Timestamp:      day.Add(time.Duration(h) * time.Hour),
+			AirTemperature: 10 + float64(h%12),
+			WindGust:       float64(h),
+		}
+	}
+	return obs
+}
+
+func newTestBrowser(t *testing.T, clip *strings.Builder) (HistoryBrowser, *[]time.Time) {
+	t.Helper()
+	cols, err := ParseHistoryColumns([]string{"temp", "gust"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 6, 15, 0, 0, 0, 0, time.Local)
+	var loads []time.Time
+	m := NewHistoryBrowser(NewTheme(true), browserDay(day), HistoryBrowserOptions{
+		Columns:  cols,
+		Units:    units.MetricSet,
+		Pressure: pressure.Reducer{Reference: pressure.Station},
+		Start:    day,
+		End:      day.AddDate(0, 0, 1),
+		Load: func(start, end time.Time) ([]tempest.Observation, error) {
+			loads = append(loads, start)
+			return browserDay(start), nil
+		},
+		RowJSON: func(o *tempest.Observation) ([]byte, error) {
+			return json.Marshal(map[string]float64{"wind_gust": o.WindGust})
+		},
+		Clipboard: clip,
+	})
+	return m, &loads
+}
+
+// browserKeys sends keystrokes, running any command that loads or copies
+// and feeding its result back; "\n" is Enter and "\x1b" is Esc.
+func browserKeys(m HistoryBrowser, keys string) HistoryBrowser {
+	for _, r := range keys {
+		var msg tea.KeyMsg
+		switch r {
+		case '\n':
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case '\x1b':
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case '↓':
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		}
+		next, cmd := m.Update(msg)
+		m = next.(HistoryBrowser)
+		if cmd != nil {
+			switch res := cmd().(type) {
+			case historyLoadedMsg, browserStatusMsg:
+				next, _ = m.Update(res)
+				m = next.(HistoryBrowser)
+			}
+		}
+	}
+	return m
+}
+
+func TestHistoryBrowserFilter(t *testing.T) {
+	m, _ := newTestBrowser(t, nil)
+	m = browserKeys(m, "/gust>=20\n")
+	if len(m.shown) != 4 {
+		t.Fatalf("gust>=20 kept %d rows, want 4", len(m.shown))
+	}
+	if !strings.Contains(m.View(), "filter: gust>=20") {
+		t.Error("view does not show the filter")
+	}
+
+	// Esc clears the filter; text matches any cell.
+	m = browserKeys(m, "\x1b/21.0°c\n")
+	if len(m.shown) != 2 {
+		t.Errorf("text filter kept %d rows, want 2", len(m.shown))
+	}
+	m = browserKeys(m, "\x1b")
+	if len(m.shown) != 24 {
+		t.Errorf("after Esc %d rows shown, want 24", len(m.shown))
+	}
+
+	m = browserKeys(m, "/nope<1\n")
+	if m.err == nil || len(m.shown) != 24 {
+		t.Errorf("unknown column: err = %v, %d rows", m.err, len(m.shown))
+	}
+}
+
+func TestHistoryBrowserGoto(t *testing.T) {
+	m, loads := newTestBrowser(t, nil)
+	m = browserKeys(m, "t14:20\n")
+	if got := m.selected().Timestamp.Hour(); got != 14 {
+		t.Errorf("t 14:20 selected hour %d, want 14", got)
+	}
+	if len(*loads) != 0 {
+		t.Error("a time inside the window should not fetch")
+	}
+
+	m = browserKeys(m, "t2024-06-10 06:00\n")
+	if len(*loads) != 1 || !m.start.Equal(time.Date(2024, 6, 10, 0, 0, 0, 0, time.Local)) {
+		t.Fatalf("loads = %v, start = %v, want June 10", *loads, m.start)
+	}
+	if o := m.selected(); o.Timestamp.Day() != 10 || o.Timestamp.Hour() != 6 {
+		t.Errorf("selected %v, want June 10 06:00", o.Timestamp)
+	}
+
+	m = browserKeys(m, "tlater\n")
+	if m.err == nil || !strings.Contains(m.View(), `"later"`) {
+		t.Errorf("bad time: err = %v", m.err)
+	}
+}
+
+func TestHistoryBrowserStepsDays(t *testing.T) {
+	m, loads := newTestBrowser(t, nil)
+	m = browserKeys(m, "][[")
+	want := []time.Time{
+		time.Date(2024, 6, 16, 0, 0, 0, 0, time.Local),
+		time.Date(2024, 6, 15, 0, 0, 0, 0, time.Local),
+		time.Date(2024, 6, 14, 0, 0, 0, 0, time.Local),
+	}
+	if len(*loads) != len(want) {
+		t.Fatalf("loads = %v, want %v", *loads, want)
+	}
+	for i := range want {
+		if !(*loads)[i].Equal(want[i]) {
+			t.Errorf("load %d = %v, want %v", i, (*loads)[i], want[i])
+		}
+	}
+	if !m.end.Equal(want[1]) {
+		t.Errorf("window ends %v, want %v", m.end, want[1])
+	}
+}
+
+func TestHistoryBrowserCopiesRow(t *testing.T) {
+	var clip strings.Builder
+	m, _ := newTestBrowser(t, &clip)
+	m = browserKeys(m, "↓↓↓y")
+	want := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(`{"wind_gust":3}`)) + "\x07"
+	if clip.String() != want {
+		t.Errorf("clipboard = %q, want %q", clip.String(), want)
+	}
+	if m.status == "" {
+		t.Error("no status after copying")
+	}
+
+	m.opts.Clipboard = nil
+	m = browserKeys(m, "y")
+	if m.err == nil || m.status != "" {
+		t.Errorf("status = %q, err = %v; want copying reported as unavailable", m.status, m.err)
+	}
+}
+
+func TestHistoryBrowserChart(t *testing.T) {
+	m, _ := newTestBrowser(t, nil)
+	m = browserKeys(m, "lc")
+	view := m.View()
+	if !strings.Contains(view, "Gust") || !strings.Contains(view, "▁") || !strings.Contains(view, "█") {
+		t.Errorf("chart missing:\n%s", view)
+	}
+	if !strings.Contains(view, "max 23.0") {
+		t.Errorf("chart summary missing:\n%s", view)
+	}
+}
+
+func TestHistoryBrowserFitsWidth(t *testing.T) {
+	m, _ := newTestBrowser(t, nil)
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 30, Height: 20})
+	m = next.(HistoryBrowser)
+	if len(m.dropped) != 1 || m.dropped[0] != "gust" {
+		t.Errorf("dropped = %v, want [gust]", m.dropped)
+	}
+	for _, line := range strings.Split(m.table.View(), "\n") {
+		if w := len([]rune(line)); w > 30 {
+			t.Errorf("line is %d wide: %q", w, line)
+		}
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	line, mark := sparkline([]float64{0, 1, 2, 3, 4, 5, 6, 7}, 8, 5)
+	if line != "▁▂▃▄▅▆▇█" || mark != 5 {
+		t.Errorf("sparkline = %q, %d", line, mark)
+	}
+	// Values sharing a cell are averaged.
+	line, mark = sparkline([]float64{0, 0, 7, 7}, 2, 3)
+	if line != "▁█" || mark != 1 {
+		t.Errorf("sparkline = %q, %d", line, mark)
+	}
+	if line, _ := sparkline([]float64{2, 2}, 10, 0); line != "▁▁" {
+		t.Errorf("flat sparkline = %q", line)
+	}
+}
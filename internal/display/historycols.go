@@ -0,0 +1,403 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
+	"github.com/chadmayfield/tempest-cli/internal/pressure"
+	"github.com/chadmayfield/tempest-cli/internal/units"
+	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HistoryColumn is a field of the history table, CSV and JSON output.
+type HistoryColumn struct {
+	// Key is the name used with --columns and --sort.
+	Key string
+	// Fields are the JSON and CSV fields the column selects.
+	Fields []string
+	// Width is the column's minimum table width.
+	Width int
+	// Priority decides which columns are hidden first when the table does
+	// not fit the terminal: the lowest goes first.
+	Priority int
+
+	title    func(p pressure.Reducer) string // message key
+	aliases  []string
+	quantity quantity
+	value    func(o *tempest.Observation) float64 // metric
+	cell     func(o *tempest.Observation, c historyCellContext) string
+}
+
+// quantity says how a column's value converts to the chosen units.
+type quantity int
+
+const (
+	qPlain quantity = iota
+	qTemperature
+	qSpeed
+	qPressure
+	qPrecip
+	qDistance
+)
+
+// Value returns the column's value for o in the units of u, with pressure
+// reduced by p. The time column's value is Unix nanoseconds.
+func (c HistoryColumn) Value(o *tempest.Observation, u units.Set, p pressure.Reducer) float64 {
+	v := c.value(o)
+	switch c.quantity {
+	case qTemperature:
+		return u.Temp(v)
+	case qSpeed:
+		return u.Speed(v)
+	case qPressure:
+		return u.Press(p.Reduce(v, o.AirTemperature))
+	case qPrecip:
+		return u.Precip(v)
+	case qDistance:
+		return u.Dist(v)
+	}
+	return v
+}
+
+// Title returns the column's heading in l, naming p's pressure reference.
+func (c HistoryColumn) Title(l *i18n.Locale, p pressure.Reducer) string {
+	return l.T(c.title(p))
+}
+
+// Unit returns the symbol of the unit Value converts to, or "" if the
+// column is not converted.
+func (c HistoryColumn) Unit(u units.Set) string {
+	switch c.quantity {
+	case qTemperature:
+		return units.Symbol(u.Temperature)
+	case qSpeed:
+		return units.Symbol(u.Wind)
+	case qPressure:
+		return units.Symbol(u.Pressure)
+	case qPrecip:
+		return units.Symbol(u.Precipitation)
+	case qDistance:
+		return units.Symbol(u.Distance)
+	}
+	return ""
+}
+
+type historyCellContext struct {
+	theme *Theme
+	u     units.Set
+	p     pressure.Reducer
+}
+
+func title(key string) func(pressure.Reducer) string {
+	return func(pressure.Reducer) string { return key }
+}
+
+// historyColumns lists every history column in their --columns help order.
+var historyColumns = []HistoryColumn{
+	{
+		Key: "time", Fields: []string{"timestamp"}, Width: 16, Priority: 100,
+		title: title("column_time"), aliases: []string{"date"},
+		value: func(o *tempest.Observation) float64 { return float64(o.Timestamp.UnixNano()) },
+		cell:  func(o *tempest.Observation, c historyCellContext) string { return c.theme.Locale.DateTime(o.Timestamp) },
+	},
+	{
+		Key: "temp", Fields: []string{"temperature"}, Width: 10, Priority: 90, quantity: qTemperature,
+		title: title("column_temp"),
+		value: func(o *tempest.Observation) float64 { return o.AirTemperature },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return FormatTemp(o.AirTemperature, c.u, c.theme.Locale)
+		},
+	},
+	{
+		Key: "feels", Fields: []string{"feels_like"}, Width: 12, Priority: 40, quantity: qTemperature,
+		title: title("column_feels_like"),
+		value: func(o *tempest.Observation) float64 { return o.FeelsLike },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return FormatTemp(o.FeelsLike, c.u, c.theme.Locale)
+		},
+	},
+	{
+		Key: "dew", Fields: []string{"dew_point"}, Width: 10, Priority: 30, quantity: qTemperature,
+		title: title("column_dew_point"),
+		value: func(o *tempest.Observation) float64 { return o.DewPoint },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return FormatTemp(o.DewPoint, c.u, c.theme.Locale)
+		},
+	},
+	{
+		Key: "wetbulb", Fields: []string{"wet_bulb"}, Width: 10, Priority: 10, quantity: qTemperature,
+		title: title("column_wet_bulb"),
+		value: func(o *tempest.Observation) float64 { return o.WetBulb },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return FormatTemp(o.WetBulb, c.u, c.theme.Locale)
+		},
+	},
+	{
+		Key: "hum", Fields: []string{"humidity"}, Width: 7, Priority: 60,
+		title: title("column_humidity"),
+		value: func(o *tempest.Observation) float64 { return o.RelativeHumidity },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return fmt.Sprintf("%.0f%%", o.RelativeHumidity)
+		},
+	},
+	{
+		Key: "wind", Fields: []string{"wind_speed"}, Width: 14, Priority: 80, quantity: qSpeed,
+		title: title("column_wind"),
+		value: func(o *tempest.Observation) float64 { return o.WindAvg },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			l := c.theme.Locale
+			return FormatWind(o.WindAvg, c.u, l) + " " + l.CompassPoint(o.WindDirection)
+		},
+	},
+	{
+		Key: "gust", Fields: []string{"wind_gust"}, Width: 10, Priority: 50, quantity: qSpeed,
+		title: title("column_gust"),
+		value: func(o *tempest.Observation) float64 { return o.WindGust },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return FormatWind(o.WindGust, c.u, c.theme.Locale)
+		},
+	},
+	{
+		Key: "lull", Fields: []string{"wind_lull"}, Width: 10, Priority: 15, quantity: qSpeed,
+		title: title("column_lull"),
+		value: func(o *tempest.Observation) float64 { return o.WindLull },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return FormatWind(o.WindLull, c.u, c.theme.Locale)
+		},
+	},
+	{
+		Key: "dir", Fields: []string{"wind_direction", "wind_direction_cardinal"}, Width: 9, Priority: 45,
+		title: title("column_direction"), aliases: []string{"direction"},
+		value: func(o *tempest.Observation) float64 { return o.WindDirection },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return fmt.Sprintf("%.0f° %s", o.WindDirection, c.theme.Locale.CompassPoint(o.WindDirection))
+		},
+	},
+	{
+		Key: "wind_interval", Fields: []string{"wind_sample_interval"}, Width: 8, Priority: 5,
+		title: title("column_wind_interval"),
+		value: func(o *tempest.Observation) float64 { return float64(o.WindSampleInterval) },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return fmt.Sprintf("%ds", o.WindSampleInterval)
+		},
+	},
+	{
+		Key: "pressure", Fields: []string{"pressure"}, Width: 12, Priority: 70, quantity: qPressure,
+		title: func(p pressure.Reducer) string { return pressureTitle(p.Reference) },
+		value: func(o *tempest.Observation) float64 { return o.StationPressure },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return FormatPressure(c.p.Reduce(o.StationPressure, o.AirTemperature), c.u, c.theme.Locale)
+		},
+	},
+	{
+		Key: "rain", Fields: []string{"rain"}, Width: 9, Priority: 65, quantity: qPrecip,
+		title: title("column_rain"),
+		value: func(o *tempest.Observation) float64 { return o.RainAccumulation },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return FormatPrecip(o.RainAccumulation, c.u, c.theme.Locale)
+		},
+	},
+	{
+		Key: "precip_type", Fields: []string{"precipitation_type"}, Width: 10, Priority: 25,
+		title: title("column_precip_type"),
+		value: func(o *tempest.Observation) float64 { return float64(o.PrecipitationType) },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return c.theme.Locale.T("precip_" + PrecipitationTypeName(o.PrecipitationType))
+		},
+	},
+	{
+		Key: "uv", Fields: []string{"uv_index"}, Width: 5, Priority: 55,
+		title: title("column_uv"),
+		value: func(o *tempest.Observation) float64 { return o.UVIndex },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return c.theme.Locale.Number(o.UVIndex, 1)
+		},
+	},
+	{
+		Key: "solar", Fields: []string{"solar_radiation"}, Width: 10, Priority: 35,
+		title: title("column_solar"),
+		value: func(o *tempest.Observation) float64 { return o.SolarRadiation },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return fmt.Sprintf("%.0f W/m²", o.SolarRadiation)
+		},
+	},
+	{
+		Key: "lux", Fields: []string{"illuminance"}, Width: 10, Priority: 20,
+		title: title("column_illuminance"),
+		value: func(o *tempest.Observation) float64 { return o.Illuminance },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return fmt.Sprintf("%.0f lx", o.Illuminance)
+		},
+	},
+	{
+		Key: "lightning", Fields: []string{"lightning_count"}, Width: 10, Priority: 45,
+		title: title("column_lightning"),
+		value: func(o *tempest.Observation) float64 { return float64(o.LightningCount) },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return fmt.Sprintf("%d", o.LightningCount)
+		},
+	},
+	{
+		Key: "lightning_dist", Fields: []string{"lightning_distance"}, Width: 10, Priority: 20, quantity: qDistance,
+		title: title("column_lightning_distance"),
+		value: func(o *tempest.Observation) float64 { return o.LightningAvgDist },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			if o.LightningCount == 0 {
+				return "-"
+			}
+			return FormatDistance(o.LightningAvgDist, c.u, c.theme.Locale)
+		},
+	},
+	{
+		Key: "battery", Fields: []string{"battery"}, Width: 8, Priority: 30,
+		title: title("column_battery"),
+		value: func(o *tempest.Observation) float64 { return o.Battery },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return c.theme.Locale.Number(o.Battery, 2) + "V"
+		},
+	},
+	{
+		Key: "interval", Fields: []string{"report_interval"}, Width: 8, Priority: 5,
+		title: title("column_report_interval"),
+		value: func(o *tempest.Observation) float64 { return float64(o.ReportInterval) },
+		cell: func(o *tempest.Observation, c historyCellContext) string {
+			return fmt.Sprintf("%dm", o.ReportInterval)
+		},
+	},
+}
+
+// DefaultHistoryColumns are shown when no columns are selected.
+var DefaultHistoryColumns = []string{"time", "temp", "feels", "hum", "wind", "pressure", "rain", "uv"}
+
+// HistoryColumnKeys returns the key of every history column, for help text.
+func HistoryColumnKeys() []string {
+	keys := make([]string, len(historyColumns))
+	for i, c := range historyColumns {
+		keys[i] = c.Key
+	}
+	return keys
+}
+
+// LookupHistoryColumn finds a column by key, by one of its JSON field names
+// or by an alias.
+func LookupHistoryColumn(name string) (HistoryColumn, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, c := range historyColumns {
+		if c.Key == name {
+			return c, nil
+		}
+		for _, a := range append(c.Fields, c.aliases...) {
+			if a == name {
+				return c, nil
+			}
+		}
+	}
+	return HistoryColumn{}, fmt.Errorf("unknown column %q; use one of %s", name, strings.Join(HistoryColumnKeys(), ", "))
+}
+
+// ParseHistoryColumns resolves column names in order, dropping duplicates.
+// The time column is always included, first unless listed elsewhere; no
+// names means DefaultHistoryColumns.
+func ParseHistoryColumns(names []string) ([]HistoryColumn, error) {
+	if len(names) == 0 {
+		names = DefaultHistoryColumns
+	}
+	var cols []HistoryColumn
+	seen := map[string]bool{}
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		c, err := LookupHistoryColumn(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[c.Key] {
+			seen[c.Key] = true
+			cols = append(cols, c)
+		}
+	}
+	if !seen["time"] {
+		timeCol, _ := LookupHistoryColumn("time")
+		cols = append([]HistoryColumn{timeCol}, cols...)
+	}
+	return cols, nil
+}
+
+// ParseHistorySort parses a sort spec: a column name, optionally followed by
+// ":asc" or ":desc".
+func ParseHistorySort(spec string) (col HistoryColumn, desc bool, err error) {
+	name, order, _ := strings.Cut(spec, ":")
+	switch strings.ToLower(strings.TrimSpace(order)) {
+	case "", "asc":
+	case "desc":
+		desc = true
+	default:
+		return HistoryColumn{}, false, fmt.Errorf("sort order must be asc or desc, got %q", order)
+	}
+	col, err = LookupHistoryColumn(name)
+	return col, desc, err
+}
+
+// SortHistory sorts observations by col, keeping the time order of equal
+// values. Pressure sorts by its value reduced by p, as the column shows it.
+func SortHistory(obs []tempest.Observation, col HistoryColumn, desc bool, p pressure.Reducer) {
+	sort.SliceStable(obs, func(i, j int) bool {
+		a, b := col.Value(&obs[i], units.MetricSet, p), col.Value(&obs[j], units.MetricSet, p)
+		if desc {
+			return a > b
+		}
+		return a < b
+	})
+}
+
+// PrecipitationTypeName names tempest's precipitation type codes.
+func PrecipitationTypeName(code int) string {
+	switch code {
+	case 0:
+		return "none"
+	case 1:
+		return "rain"
+	case 2:
+		return "hail"
+	case 3:
+		return "rain_hail"
+	}
+	return "unknown"
+}
+
+// fitHistoryColumns drops the lowest-priority columns, later ones first on a
+// tie, until the table fits in termWidth; pad is the width each column adds
+// around its content. It returns the columns kept, in their original order,
+// and the keys of those dropped.
+func fitHistoryColumns(cols []HistoryColumn, widths []int, pad, termWidth int) (kept []int, dropped []string) {
+	kept = make([]int, len(cols))
+	total := 0
+	for i := range cols {
+		kept[i] = i
+		total += widths[i] + pad
+	}
+	for termWidth > 0 && total > termWidth && len(kept) > 1 {
+		drop := 0
+		for k, i := range kept {
+			if cols[i].Priority <= cols[kept[drop]].Priority {
+				drop = k
+			}
+		}
+		i := kept[drop]
+		total -= widths[i] + pad
+		dropped = append(dropped, cols[i].Key)
+		kept = append(kept[:drop], kept[drop+1:]...)
+	}
+	return kept, dropped
+}
+
+// historyColumnWidth is a column's width: its minimum, widened to fit its
+// localized title.
+func historyColumnWidth(c HistoryColumn, theme *Theme, p pressure.Reducer) (string, int) {
+	t := c.Title(theme.Locale, p)
+	return t, max(c.Width, lipgloss.Width(t)+1)
+}
@@ -0,0 +1,30 @@
+package display
+
+import (
+	"time"
+)
+
+// RenderSource renders a footer naming where the data came from and when it
+// was fetched. Stale data is highlighted so that fallback results are obvious.
+func RenderSource(theme *Theme, source string, fetchedAt time.Time, stale bool) string {
+	line, warn := SourceFooter(theme, source, fetchedAt, stale)
+	if warn {
+		return theme.Warning.Render(line)
+	}
+	return theme.Muted.Render(line)
+}
+
+// SourceFooter returns RenderSource's footer unstyled, and whether it warns
+// of stale data.
+func SourceFooter(theme *Theme, source string, fetchedAt time.Time, stale bool) (string, bool) {
+	l := theme.Locale
+	line := l.T("source_footer", source, timeAgo(fetchedAt, l))
+	if !stale {
+		return line, false
+	}
+	warn := "⚠ " + l.T("stale")
+	if theme.NoEmoji {
+		warn = "[" + l.T("stale") + "]"
+	}
+	return line + " · " + warn + " " + l.T("live_sources_unavailable"), true
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -18,23 +19,35 @@ type StationRow struct {
 	IsDefault    bool
 	Online       bool
 	LastObserved time.Time
+	// Outdated is set when the station reports, but its latest observation
+	// is older than the stale threshold.
+	Outdated bool
+	// Reason explains why a station is offline, e.g. "auth failed" or "timeout".
+	Reason string
+	// Source names where the status came from; Stale marks cached fallback data.
+	Source    string
+	FetchedAt time.Time
+	Stale     bool
 }
 
 // RenderStations renders a table of stations using bubbles/table.
 func RenderStations(theme *Theme, rows []StationRow) string {
 	var b strings.Builder
+	l := theme.Locale
 
-	b.WriteString(theme.Title.Render("Stations"))
+	b.WriteString(theme.Title.Render(l.T("stations")))
 	b.WriteString("\n\n")
 
 	columns := []table.Column{
 		{Title: "", Width: 1},
-		{Title: "Name", Width: 12},
-		{Title: "Station", Width: 20},
-		{Title: "SID", Width: 8},
-		{Title: "DID", Width: 8},
-		{Title: "Status", Width: 8},
-		{Title: "Last Seen", Width: 14},
+		{Title: l.T("column_name"), Width: 12},
+		{Title: l.T("column_station"), Width: 20},
+		{Title: l.T("column_sid"), Width: 8},
+		{Title: l.T("column_did"), Width: 8},
+		{Title: l.T("column_status"), Width: 12},
+		{Title: l.T("column_last_seen"), Width: 14},
+		{Title: l.T("column_source"), Width: 13},
+		{Title: l.T("column_reason"), Width: 22},
 	}
 
 	var tableRows []table.Row
@@ -44,15 +57,12 @@ func RenderStations(theme *Theme, rows []StationRow) string {
 			def = "*"
 		}
 
-		status := "Online"
+		status := l.T("online")
 		if !r.Online {
-			status = "Offline"
+			status = l.T("offline")
 		}
 
-		lastSeen := "never"
-		if !r.LastObserved.IsZero() {
-			lastSeen = timeAgo(r.LastObserved) + " ago"
-		}
+		lastSeen := agoOr(r.LastObserved, l.T("never"), l)
 
 		runes := []rune(r.StationName)
 		name := r.StationName
@@ -63,7 +73,7 @@ func RenderStations(theme *Theme, rows []StationRow) string {
 		tableRows = append(tableRows, table.Row{
 			def, r.ConfigName, name,
 			fmt.Sprintf("%d", r.StationID), fmt.Sprintf("%d", r.DeviceID),
-			status, lastSeen,
+			status, lastSeen, sourceLabel(r.Source, r.Stale, l), r.Reason,
 		})
 	}
 
@@ -73,6 +83,51 @@ func RenderStations(theme *Theme, rows []StationRow) string {
 		table.WithHeight(len(tableRows)+1),
 	)
 
+	t.SetStyles(stationTableStyles(theme))
+
+	b.WriteString(t.View())
+
+	return b.String()
+}
+
+// StationsDocument returns the stations as a table for markdown and HTML
+// output.
+func StationsDocument(theme *Theme, rows []StationRow) Document {
+	l := theme.Locale
+	sec := Section{
+		Columns: []DocColumn{
+			{Title: l.T("column_default"), Align: AlignCenter},
+			{Title: l.T("column_name")},
+			{Title: l.T("column_station")},
+			{Title: l.T("column_sid"), Align: AlignRight},
+			{Title: l.T("column_did"), Align: AlignRight},
+			{Title: l.T("column_status")},
+			{Title: l.T("column_last_seen")},
+			{Title: l.T("column_source")},
+			{Title: l.T("column_reason")},
+		},
+	}
+	for _, r := range rows {
+		def := ""
+		if r.IsDefault {
+			def = "✓"
+		}
+		status := Cell{Text: l.T("online"), Color: theme.spec.Palette["success"].ptr()}
+		if !r.Online {
+			status = Cell{Text: l.T("offline"), Color: theme.spec.Palette["error"].ptr()}
+		}
+		sec.Rows = append(sec.Rows, []Cell{
+			{Text: def}, {Text: r.ConfigName}, {Text: r.StationName},
+			{Text: fmt.Sprintf("%d", r.StationID)}, {Text: fmt.Sprintf("%d", r.DeviceID)},
+			status, {Text: agoOr(r.LastObserved, l.T("never"), l)},
+			{Text: sourceLabel(r.Source, r.Stale, l)}, {Text: r.Reason},
+		})
+	}
+	return Document{Title: l.T("stations"), Sections: []Section{sec}}
+}
+
+// stationTableStyles returns the table styles shared by the station views.
+func stationTableStyles(theme *Theme) table.Styles {
 	s := table.DefaultStyles()
 	if theme.NoColor {
 		s.Header = lipgloss.NewStyle().Bold(true)
@@ -81,15 +136,19 @@ func RenderStations(theme *Theme, rows []StationRow) string {
 	} else {
 		s.Header = lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.AdaptiveColor{Light: "#666666", Dark: "#999999"}).
+			Foreground(theme.Label.GetForeground()).
 			BorderBottom(true).
 			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.AdaptiveColor{Light: "#cccccc", Dark: "#444444"})
+			BorderForeground(theme.Border.GetBorderTopForeground())
 		s.Selected = lipgloss.NewStyle()
 	}
-	t.SetStyles(s)
-
-	b.WriteString(t.View())
+	return s
+}
 
-	return b.String()
+// sourceLabel names a row's data source, marking stale cached data.
+func sourceLabel(source string, stale bool, l *i18n.Locale) string {
+	if stale {
+		return l.T("stale_suffix", source)
+	}
+	return source
 }
@@ -49,6 +49,23 @@ func TestRenderStations(t *testing.T) {
 	}
 }
 
+func TestRenderStationsReason(t *testing.T) {
+	theme := NewTheme(true)
+
+	rows := []StationRow{
+		{ConfigName: "cabin", StationName: "Cabin", StationID: 1, Reason: "auth failed"},
+	}
+
+	output := RenderStations(theme, rows)
+
+	if !strings.Contains(output, "Reason") {
+		t.Error("missing Reason column")
+	}
+	if !strings.Contains(output, "auth failed") {
+		t.Error("missing reason text")
+	}
+}
+
 func TestRenderStationsSingle(t *testing.T) {
 	theme := NewTheme(true)
 
@@ -83,3 +100,67 @@ func TestRenderStationsJSON(t *testing.T) {
 		t.Error("expected rows")
 	}
 }
+
+func TestPowerMode(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want int
+	}{
+		{0, -1},
+		{2.6, 0},
+		{2.455, 0},
+		{2.43, 1},
+		{2.39, 2},
+		{2.2, 3},
+	}
+	for _, tt := range tests {
+		if got := PowerMode(tt.v); got != tt.want {
+			t.Errorf("PowerMode(%.3f) = %d, want %d", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestRenderStationHealth(t *testing.T) {
+	theme := NewTheme(true)
+
+	rows := []StationHealth{
+		{
+			StationRow: StationRow{ConfigName: "home", Online: true, LastObserved: time.Now().Add(-time.Minute)},
+			StaleAfter: 30 * time.Minute,
+			Battery:    2.6,
+			SensorRSSI: -17,
+			HubRSSI:    -87,
+		},
+		{
+			StationRow: StationRow{ConfigName: "cabin"},
+			StaleAfter: 6 * time.Hour,
+			Battery:    2.05,
+			Problems:   []string{"battery low (2.05V)"},
+		},
+	}
+
+	output := RenderStationHealth(theme, rows)
+
+	for _, want := range []string{"Station Health", "home", "cabin", "Degraded", "2.60V Good", "Signal", "-17/-87 dBm", "6h", "battery low", "1 of 2 stations degraded"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("missing %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderSource(t *testing.T) {
+	theme := NewTheme(true, WithNoEmoji(true))
+
+	fresh := RenderSource(theme, "tempestd", time.Now().Add(-2*time.Minute), false)
+	if !strings.Contains(fresh, "Source: tempestd") || !strings.Contains(fresh, "2m ago") {
+		t.Errorf("fresh footer = %q", fresh)
+	}
+	if strings.Contains(fresh, "STALE") {
+		t.Errorf("fresh footer marked stale: %q", fresh)
+	}
+
+	stale := RenderSource(theme, "cache", time.Now().Add(-3*time.Hour), true)
+	if !strings.Contains(stale, "[STALE]") {
+		t.Errorf("stale footer = %q, want [STALE] marker", stale)
+	}
+}
@@ -4,7 +4,8 @@ import (
 	"fmt"
 	"math"
 
-	tempest "github.com/chadmayfield/tempest-go"
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
+	"github.com/chadmayfield/tempest-cli/internal/units"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -22,6 +23,10 @@ type Theme struct {
 	Warning  lipgloss.Style
 	NoColor  bool
 	NoEmoji  bool
+	// Locale supplies the labels and number, date and time formats.
+	Locale *i18n.Locale
+
+	spec *ThemeSpec
 }
 
 // ThemeOption configures optional theme settings.
@@ -34,12 +39,34 @@ func WithNoEmoji(noEmoji bool) ThemeOption {
 	}
 }
 
+// WithLocale renders labels, numbers, dates and times for a locale instead
+// of in English.
+func WithLocale(l *i18n.Locale) ThemeOption {
+	return func(t *Theme) {
+		t.Locale = l
+	}
+}
+
+// WithSpec uses a theme file's palette, border and thresholds instead of the
+// default theme's.
+func WithSpec(spec *ThemeSpec) ThemeOption {
+	return func(t *Theme) {
+		t.spec = spec
+	}
+}
+
 // NewTheme creates a theme appropriate for the terminal.
 func NewTheme(noColor bool, opts ...ThemeOption) *Theme {
 	t := &Theme{}
 	for _, opt := range opts {
 		opt(t)
 	}
+	if t.spec == nil {
+		t.spec = defaultSpec()
+	}
+	if t.Locale == nil {
+		t.Locale = i18n.English
+	}
 
 	if noColor {
 		t.Title = lipgloss.NewStyle().Bold(true)
@@ -56,202 +83,134 @@ func NewTheme(noColor bool, opts ...ThemeOption) *Theme {
 		return t
 	}
 
-	t2 := &Theme{
-		Title: lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.AdaptiveColor{Light: "#1a1a2e", Dark: "#e0e0ff"}),
-		Subtitle: lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#555555", Dark: "#aaaaaa"}),
-		Label: lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#666666", Dark: "#999999"}),
-		Value: lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#1a1a2e", Dark: "#ffffff"}),
-		Muted: lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#999999", Dark: "#666666"}),
-		Border: lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.AdaptiveColor{Light: "#cccccc", Dark: "#444444"}).
-			Padding(1, 2),
-		Card: lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.AdaptiveColor{Light: "#dddddd", Dark: "#333333"}).
-			Padding(0, 1),
-		Error: lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#cc0000", Dark: "#ff4444"}),
-		Success: lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#00aa00", Dark: "#44ff44"}),
-		Warning: lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#cc8800", Dark: "#ffaa00"}),
-		NoColor: false,
-		NoEmoji: t.NoEmoji,
+	fg := func(key string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(t.spec.Palette[key].adaptive())
+	}
+	border := borders[t.spec.Border]
+	t.Title = fg("title").Bold(true)
+	t.Subtitle = fg("subtitle")
+	t.Label = fg("label")
+	t.Value = fg("value")
+	t.Muted = fg("muted")
+	t.Border = lipgloss.NewStyle().
+		Border(border).
+		BorderForeground(t.spec.Palette["border"].adaptive()).
+		Padding(1, 2)
+	t.Card = lipgloss.NewStyle().
+		Border(border).
+		BorderForeground(t.spec.Palette["card"].adaptive()).
+		Padding(0, 1)
+	t.Error = fg("error")
+	t.Success = fg("success")
+	t.Warning = fg("warning")
+	return t
+}
+
+// Name returns the name of the theme's spec.
+func (t *Theme) Name() string {
+	return t.spec.Name
+}
+
+// thresholdColor styles formatted with the color of the first band of metric
+// that contains v.
+func (t *Theme) thresholdColor(metric string, v float64, formatted string) string {
+	if c := t.bandColor(metric, v); c != nil && !t.NoColor {
+		return lipgloss.NewStyle().Foreground(c.adaptive()).Render(formatted)
 	}
-	return t2
+	return formatted
+}
+
+// bandColor returns the color of the first band of metric that contains v,
+// or nil if there is none.
+func (t *Theme) bandColor(metric string, v float64) *Color {
+	for _, b := range t.spec.Thresholds[metric] {
+		if b.contains(v) {
+			return &b.Color
+		}
+	}
+	return nil
 }
 
-// TempColor returns a styled string for a temperature value.
-// Thresholds: blue (<32F/0C), white (cool), yellow (warm), red (>90F/32C).
+// TempColor returns a styled string for a temperature in °C.
 func (t *Theme) TempColor(tempC float64, formatted string) string {
-	if t.NoColor {
-		return formatted
-	}
-	var color lipgloss.AdaptiveColor
-	switch {
-	case tempC <= 0:
-		color = lipgloss.AdaptiveColor{Light: "#0066cc", Dark: "#66aaff"}
-	case tempC <= 15:
-		color = lipgloss.AdaptiveColor{Light: "#333333", Dark: "#dddddd"}
-	case tempC <= 32:
-		color = lipgloss.AdaptiveColor{Light: "#cc8800", Dark: "#ffcc00"}
-	default:
-		color = lipgloss.AdaptiveColor{Light: "#cc0000", Dark: "#ff4444"}
-	}
-	return lipgloss.NewStyle().Foreground(color).Render(formatted)
+	return t.thresholdColor("temperature", tempC, formatted)
 }
 
 // UVColor returns a styled string for a UV index value.
 func (t *Theme) UVColor(uv float64, formatted string) string {
-	if t.NoColor {
-		return formatted
-	}
-	var color lipgloss.AdaptiveColor
-	switch {
-	case uv <= 2:
-		color = lipgloss.AdaptiveColor{Light: "#00aa00", Dark: "#44ff44"}
-	case uv <= 5:
-		color = lipgloss.AdaptiveColor{Light: "#cc8800", Dark: "#ffcc00"}
-	case uv <= 7:
-		color = lipgloss.AdaptiveColor{Light: "#cc4400", Dark: "#ff8800"}
-	case uv <= 10:
-		color = lipgloss.AdaptiveColor{Light: "#cc0000", Dark: "#ff4444"}
-	default:
-		color = lipgloss.AdaptiveColor{Light: "#8800cc", Dark: "#cc66ff"}
-	}
-	return lipgloss.NewStyle().Foreground(color).Render(formatted)
+	return t.thresholdColor("uv", uv, formatted)
 }
 
 // BatteryColor returns a styled string for a battery voltage.
 func (t *Theme) BatteryColor(volts float64, formatted string) string {
-	if t.NoColor {
-		return formatted
-	}
-	var color lipgloss.AdaptiveColor
-	switch {
-	case volts >= 2.4:
-		color = lipgloss.AdaptiveColor{Light: "#00aa00", Dark: "#44ff44"}
-	case volts >= 2.1:
-		color = lipgloss.AdaptiveColor{Light: "#cc8800", Dark: "#ffcc00"}
-	default:
-		color = lipgloss.AdaptiveColor{Light: "#cc0000", Dark: "#ff4444"}
-	}
-	return lipgloss.NewStyle().Foreground(color).Render(formatted)
+	return t.thresholdColor("battery", volts, formatted)
 }
 
 // HumidityColor returns a styled string for a humidity percentage.
 func (t *Theme) HumidityColor(hum float64, formatted string) string {
-	if t.NoColor {
-		return formatted
-	}
-	var color lipgloss.AdaptiveColor
-	switch {
-	case hum < 30:
-		color = lipgloss.AdaptiveColor{Light: "#cc8800", Dark: "#ffcc00"} // dry
-	case hum <= 60:
-		color = lipgloss.AdaptiveColor{Light: "#00aa00", Dark: "#44ff44"} // comfortable
-	default:
-		color = lipgloss.AdaptiveColor{Light: "#0066cc", Dark: "#66aaff"} // humid
-	}
-	return lipgloss.NewStyle().Foreground(color).Render(formatted)
+	return t.thresholdColor("humidity", hum, formatted)
 }
 
 // WindColor returns a styled string for a wind speed in m/s.
 func (t *Theme) WindColor(mps float64, formatted string) string {
-	if t.NoColor {
-		return formatted
-	}
-	var color lipgloss.AdaptiveColor
-	switch {
-	case mps < 5:
-		color = lipgloss.AdaptiveColor{Light: "#00aa00", Dark: "#44ff44"} // calm
-	case mps < 10:
-		color = lipgloss.AdaptiveColor{Light: "#cc8800", Dark: "#ffcc00"} // moderate
-	default:
-		color = lipgloss.AdaptiveColor{Light: "#cc0000", Dark: "#ff4444"} // strong
-	}
-	return lipgloss.NewStyle().Foreground(color).Render(formatted)
+	return t.thresholdColor("wind", mps, formatted)
 }
 
 // PressureColor returns a styled string for a pressure value in hPa.
 func (t *Theme) PressureColor(hpa float64, formatted string) string {
-	if t.NoColor {
-		return formatted
-	}
-	var color lipgloss.AdaptiveColor
-	switch {
-	case hpa < 1000:
-		color = lipgloss.AdaptiveColor{Light: "#cc0000", Dark: "#ff4444"} // low
-	case hpa <= 1020:
-		color = lipgloss.AdaptiveColor{Light: "#00aa00", Dark: "#44ff44"} // normal
-	default:
-		color = lipgloss.AdaptiveColor{Light: "#0066cc", Dark: "#66aaff"} // high
-	}
-	return lipgloss.NewStyle().Foreground(color).Render(formatted)
+	return t.thresholdColor("pressure", hpa, formatted)
 }
 
-// RainColor returns a styled string for precipitation.
+// RainColor returns a styled string for precipitation in mm.
 func (t *Theme) RainColor(mm float64, formatted string) string {
-	if t.NoColor {
-		return formatted
-	}
-	if mm == 0 {
-		return t.Muted.Render(formatted)
-	}
-	var color lipgloss.AdaptiveColor
-	switch {
-	case mm < 5:
-		color = lipgloss.AdaptiveColor{Light: "#0066cc", Dark: "#66aaff"} // light
-	default:
-		color = lipgloss.AdaptiveColor{Light: "#0000cc", Dark: "#4444ff"} // heavy
-	}
-	return lipgloss.NewStyle().Foreground(color).Render(formatted)
+	return t.thresholdColor("rain", mm, formatted)
 }
 
 // LightningColor returns a styled string for lightning count.
 func (t *Theme) LightningColor(count int, formatted string) string {
-	if t.NoColor {
-		return formatted
-	}
-	if count == 0 {
-		return t.Muted.Render(formatted)
-	}
-	color := lipgloss.AdaptiveColor{Light: "#cc8800", Dark: "#ffcc00"}
-	return lipgloss.NewStyle().Foreground(color).Render(formatted)
+	return t.thresholdColor("lightning", float64(count), formatted)
 }
 
-// UVLabel returns a human-readable UV severity label.
+// UVLabel returns a human-readable UV severity label in English.
 func UVLabel(uv float64) string {
+	return i18n.English.T(uvLevel(uv))
+}
+
+// uvLevel returns the message key of a UV index's severity.
+func uvLevel(uv float64) string {
 	switch {
 	case uv <= 2:
-		return "Low"
+		return "uv_low"
 	case uv <= 5:
-		return "Moderate"
+		return "uv_moderate"
 	case uv <= 7:
-		return "High"
+		return "uv_high"
 	case uv <= 10:
-		return "Very High"
+		return "uv_very_high"
 	default:
-		return "Extreme"
+		return "uv_extreme"
 	}
 }
 
-// BatteryLabel returns a human-readable battery status.
+// BatteryLabel returns a human-readable battery status in English.
 func BatteryLabel(volts float64) string {
+	return i18n.English.T(batteryLevel(volts))
+}
+
+// BatteryLow reports whether a battery voltage is below the fair range.
+func BatteryLow(volts float64) bool {
+	return batteryLevel(volts) == "battery_low"
+}
+
+// batteryLevel returns the message key of a battery voltage's status.
+func batteryLevel(volts float64) string {
 	switch {
 	case volts >= 2.4:
-		return "Good"
+		return "battery_good"
 	case volts >= 2.1:
-		return "Fair"
+		return "battery_fair"
 	default:
-		return "Low"
+		return "battery_low"
 	}
 }
 
@@ -280,24 +239,24 @@ func ConditionIcon(icon string) string {
 }
 
 // ConditionLabel returns a text-only label for --no-emoji mode.
-func ConditionLabel(icon string) string {
+func ConditionLabel(icon string, l *i18n.Locale) string {
 	labels := map[string]string{
-		"clear-day":            "[clear]",
-		"clear-night":          "[clear]",
-		"cloudy":               "[cloudy]",
-		"foggy":                "[fog]",
-		"partly-cloudy-day":    "[partly cloudy]",
-		"partly-cloudy-night":  "[partly cloudy]",
-		"possibly-rainy-day":   "[chance rain]",
-		"possibly-rainy-night": "[chance rain]",
-		"rainy":                "[rain]",
-		"sleet":                "[sleet]",
-		"snow":                 "[snow]",
-		"thunderstorm":         "[storm]",
-		"windy":                "[windy]",
-	}
-	if s, ok := labels[icon]; ok {
-		return s
+		"clear-day":            "condition_clear",
+		"clear-night":          "condition_clear",
+		"cloudy":               "condition_cloudy",
+		"foggy":                "condition_fog",
+		"partly-cloudy-day":    "condition_partly_cloudy",
+		"partly-cloudy-night":  "condition_partly_cloudy",
+		"possibly-rainy-day":   "condition_chance_rain",
+		"possibly-rainy-night": "condition_chance_rain",
+		"rainy":                "condition_rain",
+		"sleet":                "condition_sleet",
+		"snow":                 "condition_snow",
+		"thunderstorm":         "condition_storm",
+		"windy":                "condition_windy",
+	}
+	if key, ok := labels[icon]; ok {
+		return "[" + l.T(key) + "]"
 	}
 	return "[--]"
 }
@@ -310,42 +269,35 @@ func WindArrow(degrees float64) string {
 	return arrows[idx]
 }
 
-// FormatTemp formats a temperature with units.
-func FormatTemp(tempC float64, imperial bool) string {
-	if imperial {
-		return fmt.Sprintf("%.1f°F", tempest.CelsiusToFahrenheit(tempC))
-	}
-	return fmt.Sprintf("%.1f°C", tempC)
+// FormatTemp formats a temperature given in °C in u's temperature unit,
+// with l's decimal separator.
+func FormatTemp(tempC float64, u units.Set, l *i18n.Locale) string {
+	return formatValue(u.Temp(tempC), u.Temperature, "", l)
 }
 
-// FormatWind formats a wind speed with units.
-func FormatWind(mps float64, imperial bool) string {
-	if imperial {
-		return fmt.Sprintf("%.1f mph", tempest.MpsToMph(mps))
+// FormatWind formats a wind speed given in m/s in u's wind unit.
+func FormatWind(mps float64, u units.Set, l *i18n.Locale) string {
+	if u.Wind == units.Beaufort {
+		return fmt.Sprintf("%d Bft", units.BeaufortForce(mps))
 	}
-	return fmt.Sprintf("%.1f m/s", mps)
+	return formatValue(u.Speed(mps), u.Wind, " ", l)
 }
 
-// FormatPressure formats a pressure with units.
-func FormatPressure(hpa float64, imperial bool) string {
-	if imperial {
-		return fmt.Sprintf("%.2f inHg", tempest.HpaToInhg(hpa))
-	}
-	return fmt.Sprintf("%.1f hPa", hpa)
+// FormatPressure formats a pressure given in hPa in u's pressure unit.
+func FormatPressure(hpa float64, u units.Set, l *i18n.Locale) string {
+	return formatValue(u.Press(hpa), u.Pressure, " ", l)
 }
 
-// FormatPrecip formats a precipitation amount with units.
-func FormatPrecip(mm float64, imperial bool) string {
-	if imperial {
-		return fmt.Sprintf("%.2f in", tempest.MmToInches(mm))
-	}
-	return fmt.Sprintf("%.1f mm", mm)
+// FormatPrecip formats a precipitation amount given in mm in u's unit.
+func FormatPrecip(mm float64, u units.Set, l *i18n.Locale) string {
+	return formatValue(u.Precip(mm), u.Precipitation, " ", l)
 }
 
-// FormatDistance formats a distance with units.
-func FormatDistance(km float64, imperial bool) string {
-	if imperial {
-		return fmt.Sprintf("%.1f mi", tempest.KmToMiles(km))
-	}
-	return fmt.Sprintf("%.1f km", km)
+// FormatDistance formats a distance given in km in u's distance unit.
+func FormatDistance(km float64, u units.Set, l *i18n.Locale) string {
+	return formatValue(u.Dist(km), u.Distance, " ", l)
+}
+
+func formatValue(v float64, unit, sep string, l *i18n.Locale) string {
+	return l.Number(v, units.Decimals(unit)) + sep + units.Symbol(unit)
 }
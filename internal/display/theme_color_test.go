@@ -1,6 +1,11 @@
 package display
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
+	"github.com/chadmayfield/tempest-cli/internal/units"
+)
 
 func TestWithNoEmoji(t *testing.T) {
 	theme := NewTheme(true, WithNoEmoji(true))
@@ -43,20 +48,20 @@ func TestConditionLabel(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.icon, func(t *testing.T) {
-			got := ConditionLabel(tt.icon)
+			got := ConditionLabel(tt.icon, i18n.English)
 			if got != tt.want {
-				t.Errorf("ConditionLabel(%q) = %q, want %q", tt.icon, got, tt.want)
+				t.Errorf("ConditionLabel(%q, i18n.English) = %q, want %q", tt.icon, got, tt.want)
 			}
 		})
 	}
 }
 
 func TestFormatDistance(t *testing.T) {
-	got := FormatDistance(10.0, false)
+	got := FormatDistance(10.0, units.MetricSet, i18n.English)
 	if got != "10.0 km" {
 		t.Errorf("FormatDistance(10, metric) = %q, want %q", got, "10.0 km")
 	}
-	got = FormatDistance(10.0, true)
+	got = FormatDistance(10.0, units.ImperialSet, i18n.English)
 	if got != "6.2 mi" {
 		t.Errorf("FormatDistance(10, imperial) = %q, want %q", got, "6.2 mi")
 	}
@@ -3,6 +3,9 @@ package display
 import (
 	"strings"
 	"testing"
+
+	"github.com/chadmayfield/tempest-cli/internal/i18n"
+	"github.com/chadmayfield/tempest-cli/internal/units"
 )
 
 func TestNewTheme(t *testing.T) {
@@ -64,6 +67,9 @@ func TestBatteryLabel(t *testing.T) {
 		if got != tt.want {
 			t.Errorf("BatteryLabel(%.1f) = %q, want %q", tt.v, got, tt.want)
 		}
+		if low := BatteryLow(tt.v); low != (tt.want == "Low") {
+			t.Errorf("BatteryLow(%.1f) = %v", tt.v, low)
+		}
 	}
 }
 
@@ -98,44 +104,64 @@ func TestWindArrow(t *testing.T) {
 }
 
 func TestFormatTemp(t *testing.T) {
-	got := FormatTemp(0, false)
+	got := FormatTemp(0, units.MetricSet, i18n.English)
 	if got != "0.0°C" {
 		t.Errorf("FormatTemp(0, metric) = %q", got)
 	}
-	got = FormatTemp(0, true)
+	got = FormatTemp(0, units.ImperialSet, i18n.English)
 	if got != "32.0°F" {
 		t.Errorf("FormatTemp(0, imperial) = %q", got)
 	}
 }
 
 func TestFormatWind(t *testing.T) {
-	got := FormatWind(10.0, false)
+	got := FormatWind(10.0, units.MetricSet, i18n.English)
 	if got != "10.0 m/s" {
 		t.Errorf("FormatWind(10, metric) = %q", got)
 	}
-	got = FormatWind(10.0, true)
+	got = FormatWind(10.0, units.ImperialSet, i18n.English)
 	if !strings.Contains(got, "mph") {
 		t.Errorf("FormatWind(10, imperial) = %q, want mph", got)
 	}
 }
 
+func TestFormatMixedUnits(t *testing.T) {
+	u := units.MetricSet.With(units.Set{Wind: units.Knots, Pressure: units.Millibar})
+	tests := []struct {
+		got, want string
+	}{
+		{FormatTemp(20, u, i18n.English), "20.0°C"},
+		{FormatWind(10, u, i18n.English), "19.4 kn"},
+		{FormatWind(10, units.Set{Wind: units.Beaufort}, i18n.English), "5 Bft"},
+		{FormatWind(10, units.Set{Wind: units.KilometersPerHour}, i18n.English), "36.0 km/h"},
+		{FormatPressure(1013.25, u, i18n.English), "1013.2 mb"},
+		{FormatPressure(1013.25, units.Set{Pressure: units.Kilopascal}, i18n.English), "101.33 kPa"},
+		{FormatPressure(1013.25, units.Set{Pressure: units.MillimetersOfMercury}, i18n.English), "760.0 mmHg"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
 func TestFormatPressure(t *testing.T) {
-	got := FormatPressure(1013.25, false)
+	got := FormatPressure(1013.25, units.MetricSet, i18n.English)
 	if !strings.Contains(got, "hPa") {
 		t.Errorf("FormatPressure metric = %q", got)
 	}
-	got = FormatPressure(1013.25, true)
+	got = FormatPressure(1013.25, units.ImperialSet, i18n.English)
 	if !strings.Contains(got, "inHg") {
 		t.Errorf("FormatPressure imperial = %q", got)
 	}
 }
 
 func TestFormatPrecip(t *testing.T) {
-	got := FormatPrecip(25.4, false)
+	got := FormatPrecip(25.4, units.MetricSet, i18n.English)
 	if !strings.Contains(got, "mm") {
 		t.Errorf("FormatPrecip metric = %q", got)
 	}
-	got = FormatPrecip(25.4, true)
+	got = FormatPrecip(25.4, units.ImperialSet, i18n.English)
 	if !strings.Contains(got, "in") {
 		t.Errorf("FormatPrecip imperial = %q", got)
 	}
@@ -0,0 +1,377 @@
+package display
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed themes/*.yaml
+var presetFS embed.FS
+
+// DefaultThemeName is the preset used when no theme is configured.
+const DefaultThemeName = "default"
+
+// Palette keys a theme file may set; each maps to the Theme style of the same
+// name.
+var paletteKeys = []string{
+	"title", "subtitle", "label", "value", "muted",
+	"border", "card", "error", "success", "warning",
+}
+
+// Threshold metrics a theme file may set, with the unit their bounds are in.
+// Bounds are always metric, whatever units values are displayed in.
+var thresholdMetrics = map[string]string{
+	"temperature": "°C",
+	"uv":          "index",
+	"humidity":    "%",
+	"wind":        "m/s",
+	"pressure":    "hPa",
+	"rain":        "mm",
+	"lightning":   "strikes",
+	"battery":     "V",
+}
+
+// borders are the border styles a theme file may name.
+var borders = map[string]lipgloss.Border{
+	"normal":  lipgloss.NormalBorder(),
+	"rounded": lipgloss.RoundedBorder(),
+	"thick":   lipgloss.ThickBorder(),
+	"double":  lipgloss.DoubleBorder(),
+	"ascii":   lipgloss.ASCIIBorder(),
+	"hidden":  lipgloss.HiddenBorder(),
+}
+
+// Color is a theme color: "#rrggbb", "#rgb" or an ANSI color number, with
+// separate values for light and dark terminal backgrounds. In YAML it is
+// either a single value used for both or a {light, dark} mapping.
+type Color struct {
+	Light string
+	Dark  string
+}
+
+// UnmarshalYAML accepts a scalar or a {light, dark} mapping.
+func (c *Color) UnmarshalYAML(n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		c.Light, c.Dark = n.Value, n.Value
+		return nil
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			if val.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: color %s must be a single value", val.Line, key.Value)
+			}
+			switch key.Value {
+			case "light":
+				c.Light = val.Value
+			case "dark":
+				c.Dark = val.Value
+			default:
+				return fmt.Errorf("line %d: unknown color key %q; use light and dark", key.Line, key.Value)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("line %d: a color must be a value or a {light, dark} mapping", n.Line)
+}
+
+func (c Color) adaptive() lipgloss.AdaptiveColor {
+	return lipgloss.AdaptiveColor{Light: c.Light, Dark: c.Dark}
+}
+
+func (c Color) ptr() *Color {
+	return &c
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func validColor(s string) error {
+	if s == "" {
+		return errors.New("missing color")
+	}
+	if hexColor.MatchString(s) {
+		return nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		return nil
+	}
+	return fmt.Errorf("invalid color %q; use #rrggbb, #rgb or an ANSI color number 0-255", s)
+}
+
+func (c Color) validate() error {
+	if err := validColor(c.Light); err != nil {
+		return fmt.Errorf("light: %w", err)
+	}
+	if err := validColor(c.Dark); err != nil {
+		return fmt.Errorf("dark: %w", err)
+	}
+	return nil
+}
+
+// Band is one threshold band. It matches values up to and including Max, or
+// strictly below Below; the last band of a metric sets neither and matches
+// every larger value.
+type Band struct {
+	Max   *float64 `yaml:"max,omitempty"`
+	Below *float64 `yaml:"below,omitempty"`
+	Color Color    `yaml:"color"`
+}
+
+func (b Band) contains(v float64) bool {
+	switch {
+	case b.Max != nil:
+		return v <= *b.Max
+	case b.Below != nil:
+		return v < *b.Below
+	}
+	return true
+}
+
+// ThemeSpec is a parsed theme file.
+type ThemeSpec struct {
+	Name string `yaml:"name"`
+	// Extends names the preset this theme starts from; the default preset if
+	// empty. Palette keys override the preset's one by one, while a metric's
+	// threshold list replaces the preset's list for that metric.
+	Extends    string            `yaml:"extends,omitempty"`
+	Border     string            `yaml:"border,omitempty"`
+	Palette    map[string]Color  `yaml:"palette,omitempty"`
+	Thresholds map[string][]Band `yaml:"thresholds,omitempty"`
+}
+
+// ThemeNames returns the built-in preset names, sorted.
+func ThemeNames() []string {
+	entries, _ := fs.ReadDir(presetFS, "themes")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// preset parses a built-in theme; ok is false if there is none by that name.
+func preset(name string) (spec *ThemeSpec, ok bool, err error) {
+	data, err := presetFS.ReadFile("themes/" + name + ".yaml")
+	if err != nil {
+		return nil, false, nil
+	}
+	spec, err = ParseTheme(data, name)
+	return spec, true, err
+}
+
+// defaultSpec returns the default preset, which always parses.
+func defaultSpec() *ThemeSpec {
+	spec, _, err := preset(DefaultThemeName)
+	if err != nil {
+		panic("default theme: " + err.Error())
+	}
+	return spec
+}
+
+// isThemePath reports whether name refers to a file rather than a theme name.
+func isThemePath(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return strings.ContainsAny(name, `/\`) || ext == ".yaml" || ext == ".yml"
+}
+
+// LoadTheme resolves name to a theme. It may be a path to a theme file, a
+// built-in preset, or the name of a file in themeDir (e.g. "desert" for
+// themeDir/desert.yaml). Empty means the default preset.
+func LoadTheme(name, themeDir string) (*ThemeSpec, error) {
+	if name == "" {
+		return defaultSpec(), nil
+	}
+	if isThemePath(name) {
+		return loadThemeFile(name)
+	}
+	if spec, ok, err := preset(name); ok {
+		return spec, err
+	}
+	if themeDir != "" {
+		path := filepath.Join(themeDir, name+".yaml")
+		if _, err := os.Stat(path); err == nil {
+			return loadThemeFile(path)
+		}
+	}
+	return nil, fmt.Errorf("unknown theme %q; built-in themes are %s, or give the path to a theme file",
+		name, strings.Join(ThemeNames(), ", "))
+}
+
+func loadThemeFile(path string) (*ThemeSpec, error) {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading theme: %w", err)
+	}
+	return ParseTheme(data, path)
+}
+
+// ParseTheme parses and validates a theme file, merging it onto the preset it
+// extends. source names the theme in errors.
+func ParseTheme(data []byte, source string) (*ThemeSpec, error) {
+	var spec ThemeSpec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("theme %s: %w", source, err)
+	}
+	if spec.Name == "" {
+		spec.Name = strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	}
+
+	// The default preset is the root every other theme extends.
+	if source != DefaultThemeName || spec.Extends != "" {
+		base, err := baseTheme(spec.Extends)
+		if err != nil {
+			return nil, fmt.Errorf("theme %s: %w", source, err)
+		}
+		spec = base.merge(spec)
+	}
+	if err := spec.validate(); err != nil {
+		return nil, fmt.Errorf("theme %s: %w", source, err)
+	}
+	return &spec, nil
+}
+
+func baseTheme(name string) (*ThemeSpec, error) {
+	if name == "" {
+		return defaultSpec(), nil
+	}
+	spec, ok, err := preset(name)
+	if !ok {
+		return nil, fmt.Errorf("extends unknown theme %q; use one of %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	return spec, err
+}
+
+// merge returns s with o's settings applied on top.
+func (s *ThemeSpec) merge(o ThemeSpec) ThemeSpec {
+	out := ThemeSpec{
+		Name:       o.Name,
+		Extends:    o.Extends,
+		Border:     s.Border,
+		Palette:    make(map[string]Color, len(s.Palette)),
+		Thresholds: make(map[string][]Band, len(s.Thresholds)),
+	}
+	if o.Border != "" {
+		out.Border = o.Border
+	}
+	for k, v := range s.Palette {
+		out.Palette[k] = v
+	}
+	for k, v := range o.Palette {
+		out.Palette[k] = v
+	}
+	for k, v := range s.Thresholds {
+		out.Thresholds[k] = v
+	}
+	for k, v := range o.Thresholds {
+		out.Thresholds[k] = v
+	}
+	return out
+}
+
+// validate checks a merged spec, naming the first problem found.
+func (s *ThemeSpec) validate() error {
+	if _, ok := borders[s.Border]; !ok {
+		return fmt.Errorf("unknown border %q; use one of %s", s.Border, strings.Join(sortedKeys(borders), ", "))
+	}
+	known := make(map[string]bool, len(paletteKeys))
+	for _, k := range paletteKeys {
+		known[k] = true
+	}
+	for _, k := range sortedKeys(s.Palette) {
+		if !known[k] {
+			return fmt.Errorf("unknown palette key %q; use one of %s", k, strings.Join(paletteKeys, ", "))
+		}
+		if err := s.Palette[k].validate(); err != nil {
+			return fmt.Errorf("palette.%s: %w", k, err)
+		}
+	}
+	for _, k := range paletteKeys {
+		if _, ok := s.Palette[k]; !ok {
+			return fmt.Errorf("palette.%s is not set", k)
+		}
+	}
+	for _, metric := range sortedKeys(s.Thresholds) {
+		if _, ok := thresholdMetrics[metric]; !ok {
+			return fmt.Errorf("unknown threshold metric %q; use one of %s", metric, strings.Join(sortedKeys(thresholdMetrics), ", "))
+		}
+		if err := validateBands(s.Thresholds[metric]); err != nil {
+			return fmt.Errorf("thresholds.%s (%s): %w", metric, thresholdMetrics[metric], err)
+		}
+	}
+	for metric := range thresholdMetrics {
+		if _, ok := s.Thresholds[metric]; !ok {
+			return fmt.Errorf("thresholds.%s is not set", metric)
+		}
+	}
+	return nil
+}
+
+func validateBands(bands []Band) error {
+	if len(bands) == 0 {
+		return errors.New("needs at least one band")
+	}
+	var prev *Band
+	for i, b := range bands {
+		if err := b.Color.validate(); err != nil {
+			return fmt.Errorf("band %d color: %w", i+1, err)
+		}
+		last := i == len(bands)-1
+		switch {
+		case b.Max != nil && b.Below != nil:
+			return fmt.Errorf("band %d sets both max and below; use one", i+1)
+		case last && (b.Max != nil || b.Below != nil):
+			return fmt.Errorf("the last band must not set max or below; it matches every larger value")
+		case !last && b.Max == nil && b.Below == nil:
+			return fmt.Errorf("band %d needs max or below; only the last band may omit both", i+1)
+		}
+		if last {
+			break
+		}
+		if prev != nil {
+			p, v := prev.bound(), b.bound()
+			// "below: x" followed by "max: x" leaves x for the second band.
+			if v < p || (v == p && !(prev.Below != nil && b.Max != nil)) {
+				return fmt.Errorf("band %d bound %g must be greater than band %d bound %g", i+1, v, i, p)
+			}
+		}
+		prev = &bands[i]
+	}
+	return nil
+}
+
+func (b Band) bound() float64 {
+	if b.Max != nil {
+		return *b.Max
+	}
+	return *b.Below
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
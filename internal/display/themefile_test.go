@@ -0,0 +1,147 @@
+package display
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPresetsParse(t *testing.T) {
+	names := ThemeNames()
+	if len(names) < 3 {
+		t.Fatalf("ThemeNames() = %v, want at least default, high-contrast and colorblind", names)
+	}
+	for _, name := range names {
+		spec, err := LoadTheme(name, "")
+		if err != nil {
+			t.Errorf("LoadTheme(%q): %v", name, err)
+			continue
+		}
+		if spec.Name != name {
+			t.Errorf("preset %q is named %q", name, spec.Name)
+		}
+	}
+}
+
+func TestParseThemeMerge(t *testing.T) {
+	spec, err := ParseTheme([]byte(`
+name: phoenix
+border: double
+palette:
+  title: "#ff0000"
+thresholds:
+  temperature:
+    - {max: 25, color: "33"}
+    - {max: 40, color: {light: "#c80", dark: "#fc0"}}
+    - {color: "#ff0000"}
+`), "phoenix.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Border != "double" || spec.Palette["title"].Dark != "#ff0000" {
+		t.Errorf("overrides not applied: %+v", spec)
+	}
+	if spec.Palette["label"] != defaultSpec().Palette["label"] {
+		t.Error("unset palette keys should come from the default theme")
+	}
+	if len(spec.Thresholds["temperature"]) != 3 || len(spec.Thresholds["uv"]) != 5 {
+		t.Errorf("thresholds not merged: %v", spec.Thresholds)
+	}
+
+	theme := NewTheme(false, WithSpec(spec))
+	if theme.Name() != "phoenix" {
+		t.Errorf("Name() = %q", theme.Name())
+	}
+}
+
+func TestBandMatching(t *testing.T) {
+	theme := NewTheme(false)
+	colorOf := func(metric string, v float64) Color {
+		for _, b := range theme.spec.Thresholds[metric] {
+			if b.contains(v) {
+				return b.Color
+			}
+		}
+		return Color{}
+	}
+	// The default bands reproduce the original hardcoded thresholds.
+	tests := []struct {
+		metric string
+		a, b   float64
+		same   bool
+	}{
+		{"temperature", 0, 0.1, false},
+		{"temperature", 15, 15.1, false},
+		{"humidity", 29.9, 30, false},
+		{"humidity", 30, 60, true},
+		{"wind", 4.9, 5, false},
+		{"battery", 2.4, 2.5, true},
+		{"battery", 2.39, 2.4, false},
+		{"rain", 0, 0.1, false},
+	}
+	for _, tt := range tests {
+		if got := colorOf(tt.metric, tt.a) == colorOf(tt.metric, tt.b); got != tt.same {
+			t.Errorf("%s %v and %v same color = %v, want %v", tt.metric, tt.a, tt.b, got, tt.same)
+		}
+	}
+	if got := NewTheme(true).TempColor(40, "40"); got != "40" {
+		t.Errorf("no-color TempColor = %q", got)
+	}
+}
+
+func TestParseThemeErrors(t *testing.T) {
+	tests := []struct {
+		name, yaml, want string
+	}{
+		{"unknown key", "colours: {}", "field colours not found"},
+		{"palette key", "palette: {titel: '#fff'}", `unknown palette key "titel"`},
+		{"bad color", "palette: {title: red}", `palette.title: light: invalid color "red"`},
+		{"ansi range", "palette: {title: '300'}", "invalid color"},
+		{"color key", "palette: {title: {dark: '#fff', dim: '#000'}}", `unknown color key "dim"`},
+		{"metric", "thresholds: {temp: [{color: '#fff'}]}", `unknown threshold metric "temp"`},
+		{"empty", "thresholds: {uv: []}", "thresholds.uv (index): needs at least one band"},
+		{"both bounds", "thresholds: {uv: [{max: 1, below: 2, color: '#fff'}, {color: '#000'}]}", "sets both max and below"},
+		{"last bounded", "thresholds: {uv: [{max: 1, color: '#fff'}]}", "the last band must not set max or below"},
+		{"missing bound", "thresholds: {uv: [{color: '#fff'}, {color: '#000'}]}", "band 1 needs max or below"},
+		{"decreasing", "thresholds: {wind: [{below: 10, color: '#fff'}, {below: 5, color: '#fff'}, {color: '#000'}]}", "band 2 bound 5 must be greater than band 1 bound 10"},
+		{"border", "border: wavy", `unknown border "wavy"`},
+		{"extends", "extends: solarized", `extends unknown theme "solarized"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTheme([]byte(tt.yaml), "mine.yaml")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), "theme mine.yaml: ") {
+				t.Errorf("error %q should name the theme", err)
+			}
+		})
+	}
+
+	// "below: x" then "max: x" is allowed: x falls in the second band.
+	if _, err := ParseTheme([]byte("thresholds: {uv: [{below: 3, color: '#fff'}, {max: 3, color: '#000'}, {color: '#000'}]}"), "ok.yaml"); err != nil {
+		t.Errorf("below then max at the same bound: %v", err)
+	}
+}
+
+func TestLoadThemeFromDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "desert.yaml"), []byte("extends: colorblind\nborder: ascii\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := LoadTheme("desert", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Name != "desert" || spec.Border != "ascii" || spec.Palette["success"].Dark != "#0072b2" {
+		t.Errorf("desert = %+v", spec)
+	}
+	if _, err := LoadTheme(filepath.Join(dir, "desert.yaml"), ""); err != nil {
+		t.Errorf("loading by path: %v", err)
+	}
+	if _, err := LoadTheme("oasis", dir); err == nil || !strings.Contains(err.Error(), "built-in themes are colorblind, default, high-contrast") {
+		t.Errorf("unknown theme error = %v", err)
+	}
+}
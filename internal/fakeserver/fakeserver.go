@@ -0,0 +1,522 @@
+// Package fakeserver serves deterministic synthetic weather on the
+// WeatherFlow REST endpoints and tempestd API endpoints that tempest-cli
+// uses, so the CLI can be run and tested end to end without network access
+// or real tokens.
+//
+// The same scenario and clock always produce the same data: readings are
+// computed from the station, the minute and the scenario, not drawn from a
+// random source.
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tempest "github.com/chadmayfield/tempest-go"
+)
+
+// CloudPath is the path the WeatherFlow REST API is served under. Point the
+// CLI's cloud_url at the server's URL followed by CloudPath.
+const CloudPath = "/swd/rest"
+
+// Version is what the fake tempestd reports from /api/v1/health.
+const Version = "fake"
+
+// Options configures a fake server.
+type Options struct {
+	// Scenario selects the weather; empty means Normal.
+	Scenario Scenario
+	// Now returns the current time; nil means time.Now. Data up to now is
+	// served as history and nothing after it.
+	Now func() time.Time
+	// Token is the only REST API token accepted, and the bearer token
+	// required by the tempestd endpoints. Empty accepts any REST API token
+	// and no tempestd authentication.
+	Token string
+	// Faults are applied, in order, before a request is served.
+	Faults []Fault
+}
+
+// Fault makes matching requests fail.
+type Fault struct {
+	// Status is the HTTP status to return, e.g. 429.
+	Status int
+	// Timeout makes the request hang until the client gives up.
+	Timeout bool
+	// Count is how many matching requests fail; 0 means all of them.
+	Count int
+	// Path limits the fault to requests whose path contains it.
+	Path string
+}
+
+// ParseFault parses a fault from STATUS or "timeout", optionally followed by
+// xCOUNT and @PATH, e.g. "429", "timeout@/api/v1" or "503x2@/stations/1002".
+func ParseFault(s string) (Fault, error) {
+	var f Fault
+	spec := s
+	if i := strings.Index(spec, "@"); i >= 0 {
+		spec, f.Path = spec[:i], spec[i+1:]
+		if f.Path == "" {
+			return Fault{}, fmt.Errorf("invalid fault %q: empty path after @", s)
+		}
+	}
+	if i := strings.LastIndex(spec, "x"); i >= 0 {
+		n, err := strconv.Atoi(spec[i+1:])
+		if err != nil || n < 1 {
+			return Fault{}, fmt.Errorf("invalid fault %q: count must be a positive number", s)
+		}
+		spec, f.Count = spec[:i], n
+	}
+	if strings.EqualFold(spec, "timeout") {
+		f.Timeout = true
+		return f, nil
+	}
+	status, err := strconv.Atoi(spec)
+	if err != nil || status < 400 || status > 599 {
+		return Fault{}, fmt.Errorf("invalid fault %q: use an HTTP error status (400-599) or \"timeout\"", s)
+	}
+	f.Status = status
+	return f, nil
+}
+
+// String formats f the way ParseFault reads it.
+func (f Fault) String() string {
+	s := strconv.Itoa(f.Status)
+	if f.Timeout {
+		s = "timeout"
+	}
+	if f.Count > 0 {
+		s += "x" + strconv.Itoa(f.Count)
+	}
+	if f.Path != "" {
+		s += "@" + f.Path
+	}
+	return s
+}
+
+type server struct {
+	scenario Scenario
+	now      func() time.Time
+	token    string
+	mux      *http.ServeMux
+
+	mu     sync.Mutex
+	faults []Fault
+	// remaining counts down the failures left for faults with a Count.
+	remaining []int
+}
+
+// New returns a handler serving the fake REST API under CloudPath and the
+// fake tempestd API under /api/v1.
+func New(opts Options) http.Handler {
+	s := &server{scenario: opts.Scenario, now: opts.Now, token: opts.Token, faults: opts.Faults}
+	if s.scenario == "" {
+		s.scenario = Normal
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+	s.remaining = make([]int, len(s.faults))
+	for i, f := range s.faults {
+		s.remaining[i] = f.Count
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/health", s.tempestd(s.health))
+	mux.HandleFunc("GET /api/v1/stations", s.tempestd(s.stations))
+	mux.HandleFunc("GET /api/v1/stations/{id}", s.tempestd(s.station))
+	mux.HandleFunc("GET /api/v1/stations/{id}/current", s.tempestd(s.current))
+	mux.HandleFunc("GET /api/v1/stations/{id}/forecast", s.tempestd(s.forecast))
+	mux.HandleFunc("GET /api/v1/stations/{id}/observations", s.tempestd(s.observations))
+
+	mux.HandleFunc("GET "+CloudPath+"/stations", s.cloud(s.cloudStations))
+	mux.HandleFunc("GET "+CloudPath+"/stations/{id}", s.cloud(s.cloudStation))
+	mux.HandleFunc("GET "+CloudPath+"/observations/station/{id}", s.cloud(s.cloudCurrent))
+	mux.HandleFunc("GET "+CloudPath+"/observations/device/{id}", s.cloud(s.cloudDevice))
+	mux.HandleFunc("GET "+CloudPath+"/better_forecast", s.cloud(s.cloudForecast))
+	// The doctor command reads the clock from the Date header of any response.
+	mux.HandleFunc("HEAD "+CloudPath, func(w http.ResponseWriter, r *http.Request) {})
+	s.mux = mux
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f, ok := s.fault(r.URL.Path); ok {
+		if f.Timeout {
+			<-r.Context().Done()
+			return
+		}
+		if f.Status == http.StatusTooManyRequests || f.Status == http.StatusServiceUnavailable {
+			w.Header().Set("Retry-After", "1")
+		}
+		writeError(w, f.Status, http.StatusText(f.Status))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// fault returns the first fault that applies to a request for path, and
+// counts it against the fault's Count.
+func (s *server) fault(path string) (Fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, f := range s.faults {
+		if !strings.Contains(path, f.Path) {
+			continue
+		}
+		if f.Count > 0 {
+			if s.remaining[i] == 0 {
+				continue
+			}
+			s.remaining[i]--
+		}
+		return f, true
+	}
+	return Fault{}, false
+}
+
+// handler serves one route once the request is authorized. It returns the
+// response body, or an error status and message.
+type handler func(r *http.Request) (any, int, string)
+
+func (s *server) tempestd(h handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" && r.Header.Get("Authorization") != "Bearer "+s.token {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		serve(w, r, h)
+	}
+}
+
+func (s *server) cloud(h handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" || (s.token != "" && token != s.token) {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED")
+			return
+		}
+		serve(w, r, h)
+	}
+}
+
+func serve(w http.ResponseWriter, r *http.Request, h handler) {
+	body, status, msg := h(r)
+	if status != 0 {
+		writeError(w, status, msg)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+// lookup returns the station named by the {id} path value.
+func lookup(r *http.Request) (station, int, string) {
+	return stationByID(r.PathValue("id"))
+}
+
+func stationByID(s string) (station, int, string) {
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return station{}, http.StatusBadRequest, "invalid station id"
+	}
+	for _, st := range stations {
+		if st.ID == id {
+			return st, 0, ""
+		}
+	}
+	return station{}, http.StatusNotFound, "station not found"
+}
+
+// tempestd API
+
+func (s *server) health(*http.Request) (any, int, string) {
+	return map[string]string{"status": "ok", "version": Version, "api_version": "v1"}, 0, ""
+}
+
+func (st station) tempest() tempest.Station {
+	return tempest.Station{
+		StationID: st.ID,
+		Name:      st.Name,
+		Latitude:  st.Latitude,
+		Longitude: st.Longitude,
+		Elevation: st.Elevation,
+		CreatedAt: createdAt,
+		Devices: []tempest.Device{
+			{DeviceID: st.HubID, SerialNum: st.hubSerial(), DeviceType: "HB"},
+			{DeviceID: st.DeviceID, SerialNum: st.serial(), DeviceType: "ST"},
+		},
+	}
+}
+
+// createdAt is when the fake stations were set up.
+var createdAt = time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+
+func (s *server) stations(*http.Request) (any, int, string) {
+	return Stations(), 0, ""
+}
+
+func (s *server) station(r *http.Request) (any, int, string) {
+	st, status, msg := lookup(r)
+	if status != 0 {
+		return nil, status, msg
+	}
+	return st.tempest(), 0, ""
+}
+
+func (s *server) current(r *http.Request) (any, int, string) {
+	st, status, msg := lookup(r)
+	if status != 0 {
+		return nil, status, msg
+	}
+	return s.scenario.current(st, s.now()), 0, ""
+}
+
+func (s *server) forecast(r *http.Request) (any, int, string) {
+	st, status, msg := lookup(r)
+	if status != 0 {
+		return nil, status, msg
+	}
+	return s.scenario.forecast(st, s.now()), 0, ""
+}
+
+// resolutions are the intervals tempestd downsamples history to.
+var resolutions = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"30m": 30 * time.Minute,
+	"3h":  3 * time.Hour,
+}
+
+func (s *server) observations(r *http.Request) (any, int, string) {
+	st, status, msg := lookup(r)
+	if status != 0 {
+		return nil, status, msg
+	}
+	q := r.URL.Query()
+	start, err := time.Parse(time.RFC3339, q.Get("start"))
+	if err != nil {
+		return nil, http.StatusBadRequest, "invalid start"
+	}
+	end, err := time.Parse(time.RFC3339, q.Get("end"))
+	if err != nil || end.Before(start) {
+		return nil, http.StatusBadRequest, "invalid end"
+	}
+	step := interval(end.Sub(start))
+	if res := q.Get("resolution"); res != "" {
+		var ok bool
+		if step, ok = resolutions[res]; !ok {
+			return nil, http.StatusBadRequest, "invalid resolution"
+		}
+	}
+
+	type observation struct {
+		Timestamp          time.Time `json:"timestamp"`
+		StationID          int       `json:"station_id"`
+		DeviceID           int       `json:"device_id"`
+		WindLull           float64   `json:"wind_lull"`
+		WindAvg            float64   `json:"wind_avg"`
+		WindGust           float64   `json:"wind_gust"`
+		WindDirection      float64   `json:"wind_direction"`
+		WindSampleInterval int       `json:"wind_sample_interval"`
+		StationPressure    float64   `json:"station_pressure"`
+		AirTemperature     float64   `json:"air_temperature"`
+		RelativeHumidity   float64   `json:"relative_humidity"`
+		Illuminance        float64   `json:"illuminance"`
+		UVIndex            float64   `json:"uv_index"`
+		SolarRadiation     float64   `json:"solar_radiation"`
+		RainAccumulation   float64   `json:"rain_accumulation"`
+		PrecipitationType  int       `json:"precipitation_type"`
+		LightningAvgDist   float64   `json:"lightning_avg_distance"`
+		LightningCount     int       `json:"lightning_strike_count"`
+		Battery            float64   `json:"battery"`
+		ReportInterval     int       `json:"report_interval"`
+		FeelsLike          float64   `json:"feels_like"`
+		DewPoint           float64   `json:"dew_point"`
+		WetBulb            float64   `json:"wet_bulb"`
+	}
+	obs := s.scenario.observations(st, start, end, s.now(), step)
+	out := make([]observation, len(obs))
+	for i, o := range obs {
+		out[i] = observation(o)
+	}
+	return map[string]any{"station_id": st.ID, "observations": out}, 0, ""
+}
+
+// interval returns the spacing of device observations the REST API returns
+// for a span of time.
+func interval(span time.Duration) time.Duration {
+	switch {
+	case span <= 24*time.Hour:
+		return time.Minute
+	case span <= 5*24*time.Hour:
+		return 5 * time.Minute
+	case span <= 30*24*time.Hour:
+		return 30 * time.Minute
+	default:
+		return 3 * time.Hour
+	}
+}
+
+// WeatherFlow REST API
+
+func (st station) cloud() map[string]any {
+	return map[string]any{
+		"station_id":    st.ID,
+		"name":          st.Name,
+		"public_name":   st.Name,
+		"latitude":      st.Latitude,
+		"longitude":     st.Longitude,
+		"timezone":      st.Timezone,
+		"created_epoch": createdAt.Unix(),
+		"station_meta":  map[string]any{"elevation": st.Elevation},
+		"devices": []map[string]any{
+			{"device_id": st.HubID, "serial_number": st.hubSerial(), "device_type": "HB"},
+			{"device_id": st.DeviceID, "serial_number": st.serial(), "device_type": "ST"},
+		},
+	}
+}
+
+func (s *server) cloudStations(*http.Request) (any, int, string) {
+	list := make([]map[string]any, len(stations))
+	for i, st := range stations {
+		list[i] = st.cloud()
+	}
+	return map[string]any{"stations": list}, 0, ""
+}
+
+func (s *server) cloudStation(r *http.Request) (any, int, string) {
+	st, status, msg := lookup(r)
+	if status != 0 {
+		return nil, status, msg
+	}
+	return map[string]any{"stations": []map[string]any{st.cloud()}}, 0, ""
+}
+
+func (s *server) cloudCurrent(r *http.Request) (any, int, string) {
+	st, status, msg := lookup(r)
+	if status != 0 {
+		return nil, status, msg
+	}
+	o := s.scenario.current(st, s.now())
+	obs := map[string]any{
+		"timestamp":                       o.Timestamp.Unix(),
+		"air_temperature":                 o.AirTemperature,
+		"relative_humidity":               o.RelativeHumidity,
+		"wind_avg":                        o.WindAvg,
+		"wind_gust":                       o.WindGust,
+		"wind_lull":                       o.WindLull,
+		"wind_direction":                  o.WindDirection,
+		"barometric_pressure":             o.BarometricPressure,
+		"sea_level_pressure":              o.SeaLevelPressure,
+		"solar_radiation":                 o.SolarRadiation,
+		"uv":                              o.UV,
+		"brightness":                      o.Brightness,
+		"feels_like":                      o.FeelsLike,
+		"dew_point":                       o.DewPoint,
+		"wet_bulb_temperature":            o.WetBulbTemperature,
+		"precip_accum_local_day":          o.PrecipAccumDay,
+		"lightning_strike_count_last_3hr": o.LightningCount3hr,
+		"lightning_strike_last_distance":  o.LightningStrikeLastDistance,
+		"pressure_trend":                  o.PressureTrend,
+	}
+	if !o.LightningStrikeLastEpoch.IsZero() {
+		obs["lightning_strike_last_epoch"] = o.LightningStrikeLastEpoch.Unix()
+	}
+	return map[string]any{"station_id": st.ID, "station_name": st.Name, "timezone": st.Timezone, "obs": []any{obs}}, 0, ""
+}
+
+func (s *server) cloudDevice(r *http.Request) (any, int, string) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return nil, http.StatusBadRequest, "invalid device id"
+	}
+	var st station
+	for _, candidate := range stations {
+		if candidate.DeviceID == id {
+			st = candidate
+		}
+	}
+	if st.ID == 0 {
+		return nil, http.StatusNotFound, "device not found"
+	}
+
+	now := s.now()
+	start, end := now.Add(-time.Minute), now
+	q := r.URL.Query()
+	if q.Has("time_start") || q.Has("time_end") {
+		from, err1 := strconv.ParseInt(q.Get("time_start"), 10, 64)
+		to, err2 := strconv.ParseInt(q.Get("time_end"), 10, 64)
+		if err1 != nil || err2 != nil || to < from {
+			return nil, http.StatusBadRequest, "invalid time_start or time_end"
+		}
+		start, end = time.Unix(from, 0), time.Unix(to, 0)
+	}
+	obs := s.scenario.observations(st, start, end, now, interval(end.Sub(start)))
+	rows := make([][]any, len(obs))
+	for i, o := range obs {
+		rows[i] = []any{
+			o.Timestamp.Unix(), o.WindLull, o.WindAvg, o.WindGust, o.WindDirection,
+			o.WindSampleInterval, o.StationPressure, o.AirTemperature, o.RelativeHumidity,
+			o.Illuminance, o.UVIndex, o.SolarRadiation, o.RainAccumulation, o.PrecipitationType,
+			o.LightningAvgDist, o.LightningCount, o.Battery, o.ReportInterval,
+		}
+	}
+	return map[string]any{"device_id": id, "type": "obs_st", "obs": rows}, 0, ""
+}
+
+func (s *server) cloudForecast(r *http.Request) (any, int, string) {
+	st, status, msg := stationByID(r.URL.Query().Get("station_id"))
+	if status != 0 {
+		return nil, status, msg
+	}
+	f := s.scenario.forecast(st, s.now())
+	daily := make([]map[string]any, len(f.Daily))
+	for i, d := range f.Daily {
+		daily[i] = map[string]any{
+			"day_start_local":    d.Date.Unix(),
+			"air_temp_high":      d.HighTemp,
+			"air_temp_low":       d.LowTemp,
+			"conditions":         d.Conditions,
+			"icon":               d.Icon,
+			"precip_probability": d.PrecipChance,
+			"precip_type":        d.PrecipType,
+			"sunrise":            d.Sunrise.Unix(),
+			"sunset":             d.Sunset.Unix(),
+		}
+	}
+	hourly := make([]map[string]any, len(f.Hourly))
+	for i, h := range f.Hourly {
+		hourly[i] = map[string]any{
+			"time":               h.Time.Unix(),
+			"air_temperature":    h.Temperature,
+			"feels_like":         h.FeelsLike,
+			"relative_humidity":  h.Humidity,
+			"wind_avg":           h.WindAvg,
+			"wind_direction":     h.WindDirection,
+			"wind_gust":          h.WindGust,
+			"conditions":         h.Conditions,
+			"icon":               h.Icon,
+			"precip_probability": h.PrecipChance,
+			"precip_type":        h.PrecipType,
+			"uv":                 h.UV,
+		}
+	}
+	return map[string]any{
+		"latitude":  f.Latitude,
+		"longitude": f.Longitude,
+		"timezone":  st.Timezone,
+		"forecast":  map[string]any{"daily": daily, "hourly": hourly},
+	}, 0, ""
+}
@@ -0,0 +1,264 @@
+package fakeserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tempest "github.com/chadmayfield/tempest-go"
+)
+
+// now is 15:00 in Denver, the hottest part of the day, and the peak of a
+// storm.
+var now = time.Date(2024, 7, 15, 21, 0, 0, 0, time.UTC)
+
+func newServer(t *testing.T, opts Options) *httptest.Server {
+	t.Helper()
+	if opts.Now == nil {
+		opts.Now = func() time.Time { return now }
+	}
+	srv := httptest.NewServer(New(opts))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, url string) (int, []byte) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, body
+}
+
+func getJSON[T any](t *testing.T, url string) T {
+	t.Helper()
+	status, body := get(t, url)
+	if status != http.StatusOK {
+		t.Fatalf("GET %s = %d: %s", url, status, body)
+	}
+	var v T
+	if err := json.Unmarshal(body, &v); err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	return v
+}
+
+func TestDeterministic(t *testing.T) {
+	a, b := newServer(t, Options{}), newServer(t, Options{})
+	for _, path := range []string{"/api/v1/stations/1001/current", "/api/v1/stations/1002/forecast", CloudPath + "/observations/station/1001?token=x"} {
+		_, first := get(t, a.URL+path)
+		_, second := get(t, b.URL+path)
+		if string(first) != string(second) {
+			t.Errorf("%s differs between servers:\n%s\n%s", path, first, second)
+		}
+	}
+}
+
+func TestCloudWithClient(t *testing.T) {
+	srv := newServer(t, Options{Token: "secret"})
+	client, err := tempest.NewClient("secret", tempest.WithBaseURL(srv.URL+CloudPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	st, err := client.GetStation(ctx, 1001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Name != "Fake Home" || len(st.Devices) != 2 || st.Devices[1].DeviceType != "ST" {
+		t.Errorf("station = %+v", st)
+	}
+
+	obs, err := client.GetStationObservation(ctx, 1001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.Timestamp.Equal(now) || obs.AirTemperature < 15 || obs.SeaLevelPressure < 1000 {
+		t.Errorf("current = %+v", obs)
+	}
+
+	// A day is served a minute apart; nothing after now is served.
+	history, err := client.GetDeviceObservations(ctx, 2001, now.Add(-24*time.Hour), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 24*60+1 || !history[len(history)-1].Timestamp.Equal(now) {
+		t.Errorf("got %d observations ending %v", len(history), history[len(history)-1].Timestamp)
+	}
+	if o := history[0]; o.StationPressure < 800 || o.StationPressure > 850 || o.Battery < 2.5 {
+		t.Errorf("observation = %+v", o)
+	}
+
+	f, err := client.GetForecast(ctx, 1002)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Daily) != 10 || len(f.Hourly) != 48 || f.Daily[0].HighTemp <= f.Daily[0].LowTemp {
+		t.Errorf("forecast = %d days, %d hours, %+v", len(f.Daily), len(f.Hourly), f.Daily[0])
+	}
+}
+
+func TestTempestdObservations(t *testing.T) {
+	srv := newServer(t, Options{})
+	url := srv.URL + "/api/v1/stations/1001/observations?start=2024-07-15T20:00:00Z&end=2024-07-15T22:00:00Z&resolution=5m"
+	body := getJSON[struct {
+		Observations []struct {
+			Timestamp      time.Time `json:"timestamp"`
+			AirTemperature float64   `json:"air_temperature"`
+			ReportInterval int       `json:"report_interval"`
+		} `json:"observations"`
+	}](t, url)
+	obs := body.Observations
+	if len(obs) != 13 || !obs[12].Timestamp.Equal(now) || obs[0].ReportInterval != 5 || obs[0].AirTemperature == 0 {
+		t.Errorf("got %d observations: %+v", len(obs), obs)
+	}
+
+	if status, _ := get(t, srv.URL+"/api/v1/stations/1001/observations?start=yesterday"); status != http.StatusBadRequest {
+		t.Errorf("bad start = %d, want 400", status)
+	}
+	if status, _ := get(t, srv.URL+"/api/v1/stations/9/current"); status != http.StatusNotFound {
+		t.Errorf("unknown station = %d, want 404", status)
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	current := func(sc Scenario) tempest.StationObservation {
+		srv := newServer(t, Options{Scenario: sc})
+		return getJSON[tempest.StationObservation](t, srv.URL+"/api/v1/stations/1001/current")
+	}
+
+	normal := current(Normal)
+	if normal.LightningCount3hr != 0 || normal.PrecipAccumDay != 0 {
+		t.Errorf("normal = %+v", normal)
+	}
+	if heat := current(HeatWave); heat.AirTemperature < 38 || heat.RelativeHumidity > 15 || heat.UV < 6 {
+		t.Errorf("heat wave = %+v", heat)
+	}
+	storm := current(Storm)
+	if storm.LightningCount3hr == 0 || storm.LightningStrikeLastEpoch.IsZero() || storm.PrecipAccumDay == 0 ||
+		storm.WindGust < 20 || storm.SeaLevelPressure > normal.SeaLevelPressure-10 || storm.PressureTrend != "falling" {
+		t.Errorf("storm = %+v", storm)
+	}
+	if dead := current(DeadBattery); !dead.Timestamp.Equal(now.Add(-2 * time.Hour)) {
+		t.Errorf("dead battery reported at %v, want two hours ago", dead.Timestamp)
+	}
+
+	srv := newServer(t, Options{Scenario: DeadBattery})
+	body := getJSON[struct {
+		Observations []struct {
+			Battery float64 `json:"battery"`
+		} `json:"observations"`
+	}](t, srv.URL+"/api/v1/stations/1001/observations?start=2024-07-15T18:00:00Z&end=2024-07-15T21:00:00Z&resolution=30m")
+	if len(body.Observations) != 3 || body.Observations[0].Battery > 2.35 {
+		t.Errorf("dead battery history = %+v", body.Observations)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	srv := newServer(t, Options{Token: "secret"})
+	tests := []struct {
+		name, path string
+		bearer     string
+		want       int
+	}{
+		{"no token", CloudPath + "/stations", "", http.StatusUnauthorized},
+		{"wrong token", CloudPath + "/stations?token=nope", "", http.StatusUnauthorized},
+		{"token", CloudPath + "/stations?token=secret", "", http.StatusOK},
+		{"tempestd without bearer", "/api/v1/stations", "", http.StatusUnauthorized},
+		{"tempestd with bearer", "/api/v1/stations", "secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+tt.path, nil)
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestFaults(t *testing.T) {
+	srv := newServer(t, Options{Faults: []Fault{
+		{Status: http.StatusTooManyRequests, Count: 1, Path: "/stations/1001"},
+		{Timeout: true, Path: "/forecast"},
+	}})
+
+	resp, err := http.Get(srv.URL + "/api/v1/stations/1001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("first request = %d, Retry-After %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	if status, _ := get(t, srv.URL+"/api/v1/stations/1001"); status != http.StatusOK {
+		t.Errorf("second request = %d, want 200 once the fault is used up", status)
+	}
+
+	client := &http.Client{Timeout: 100 * time.Millisecond}
+	if _, err := client.Get(srv.URL + "/api/v1/stations/1001/forecast"); err == nil {
+		t.Error("forecast should time out")
+	}
+}
+
+func TestParseFault(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Fault
+		wantErr bool
+	}{
+		{in: "429", want: Fault{Status: 429}},
+		{in: "timeout", want: Fault{Timeout: true}},
+		{in: "503x2@/stations/1002", want: Fault{Status: 503, Count: 2, Path: "/stations/1002"}},
+		{in: "401@/swd/rest", want: Fault{Status: 401, Path: "/swd/rest"}},
+		{in: "200", wantErr: true},
+		{in: "teapot", wantErr: true},
+		{in: "429x0", wantErr: true},
+		{in: "404@", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseFault(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFault(%q) error = %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFault(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if !tt.wantErr && got.String() != tt.in {
+				t.Errorf("String() = %q, want %q", got.String(), tt.in)
+			}
+		})
+	}
+}
+
+func TestParseScenario(t *testing.T) {
+	if sc, err := ParseScenario("Heat-Wave"); err != nil || sc != HeatWave {
+		t.Errorf("ParseScenario(Heat-Wave) = %q, %v", sc, err)
+	}
+	if sc, err := ParseScenario(""); err != nil || sc != Normal {
+		t.Errorf("ParseScenario(\"\") = %q, %v", sc, err)
+	}
+	if _, err := ParseScenario("blizzard"); err == nil {
+		t.Error("ParseScenario(blizzard) should fail")
+	}
+}
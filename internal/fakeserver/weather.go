@@ -0,0 +1,360 @@
+package fakeserver
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/chadmayfield/tempest-cli/internal/pressure"
+	tempest "github.com/chadmayfield/tempest-go"
+)
+
+// Scenario selects the weather the fake stations report.
+type Scenario string
+
+const (
+	// Normal is mild weather with a daily temperature cycle.
+	Normal Scenario = "normal"
+	// HeatWave is hot, dry and sunny.
+	HeatWave Scenario = "heat-wave"
+	// Storm brings a thunderstorm every twelve hours, with wind, rain,
+	// lightning and a pressure drop.
+	Storm Scenario = "storm"
+	// DeadBattery is normal weather from sensors whose batteries are nearly
+	// flat and that stopped reporting two hours ago.
+	DeadBattery Scenario = "dead-battery"
+)
+
+// Scenarios lists the scenarios in the order they are documented.
+var Scenarios = []Scenario{Normal, HeatWave, Storm, DeadBattery}
+
+// ParseScenario parses a scenario name; empty means Normal.
+func ParseScenario(s string) (Scenario, error) {
+	if s == "" {
+		return Normal, nil
+	}
+	for _, sc := range Scenarios {
+		if strings.EqualFold(s, string(sc)) {
+			return sc, nil
+		}
+	}
+	names := make([]string, len(Scenarios))
+	for i, sc := range Scenarios {
+		names[i] = string(sc)
+	}
+	return "", fmt.Errorf("unknown scenario %q; use %s", s, strings.Join(names, ", "))
+}
+
+// deadBatteryGap is how long before now sensors stop reporting in the
+// DeadBattery scenario.
+const deadBatteryGap = 2 * time.Hour
+
+// station is a fake station with one hub and one Tempest sensor.
+type station struct {
+	ID        int
+	Name      string
+	HubID     int
+	DeviceID  int
+	Timezone  string
+	Latitude  float64
+	Longitude float64
+	Elevation float64 // meters
+}
+
+// stations are the fake stations.
+var stations = []station{
+	{ID: 1001, Name: "Fake Home", HubID: 2000, DeviceID: 2001, Timezone: "America/Denver", Latitude: 39.74, Longitude: -104.99, Elevation: 1609},
+	{ID: 1002, Name: "Fake Coast", HubID: 2010, DeviceID: 2011, Timezone: "America/Los_Angeles", Latitude: 37.81, Longitude: -122.47, Elevation: 12},
+}
+
+// Stations returns the fake stations.
+func Stations() []tempest.Station {
+	list := make([]tempest.Station, len(stations))
+	for i, st := range stations {
+		list[i] = st.tempest()
+	}
+	return list
+}
+
+func (s station) serial() string    { return fmt.Sprintf("ST-%08d", s.DeviceID) }
+func (s station) hubSerial() string { return fmt.Sprintf("HB-%08d", s.HubID) }
+
+func (s station) location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// noise returns a value in [-1, 1] that depends only on its arguments, so
+// the same station and minute always get the same weather.
+func noise(stationID int, t time.Time, salt uint64) float64 {
+	x := uint64(stationID)<<32 ^ uint64(t.Unix()/60) ^ salt*0x9e3779b97f4a7c15
+	// splitmix64
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x>>11)/float64(1<<52) - 1
+}
+
+// weather is the state of the weather at a station at a moment, in metric
+// units. Rain and lightning are rates per minute.
+type weather struct {
+	temp, humidity        float64
+	wind, gust, lull, dir float64
+	seaLevel              float64
+	solar, uv, lux        float64
+	rainPerMin            float64
+	precipType            int
+	strikesPerMin         float64
+	strikeDistance        float64
+	battery               float64
+}
+
+// stormIntensity is 0 between storms and rises to 1 at a storm's peak, at
+// 09:00 and 21:00 UTC. Each storm lasts six hours.
+func stormIntensity(t time.Time) float64 {
+	phase := float64((t.Unix()+6*3600)%(12*3600)) / (12 * 3600)
+	return math.Max(0, math.Sin(2*math.Pi*phase))
+}
+
+func (sc Scenario) weather(st station, t time.Time) weather {
+	local := t.In(st.location())
+	hour := float64(local.Hour()) + float64(local.Minute())/60
+	// day peaks at 15:00 and bottoms out at 03:00.
+	day := math.Sin(2 * math.Pi * (hour - 9) / 24)
+	sun := math.Max(0, math.Sin(math.Pi*(hour-6)/12))
+	n := func(salt uint64) float64 { return noise(st.ID, t, salt) }
+	offset := float64(st.ID%5) - 2
+
+	w := weather{
+		temp:     18 + offset + 7*day + 0.3*n(1),
+		humidity: 55 - 20*day + 2*n(2),
+		wind:     2.5 + 1.5*sun + 0.6*math.Abs(n(3)),
+		dir:      math.Mod(220+40*math.Sin(float64(t.Unix())/7200)+10*n(4)+360, 360),
+		seaLevel: 1015 + 2*math.Sin(2*math.Pi*float64(t.Unix())/(86400*3)),
+		solar:    800 * sun,
+		battery:  2.62,
+	}
+	switch sc {
+	case HeatWave:
+		w.temp = 36 + offset + 6*day + 0.3*n(1)
+		w.humidity = 18 - 8*day + n(2)
+		w.wind = 1.5 + sun + 0.4*math.Abs(n(3))
+		w.seaLevel = 1011
+		w.solar = 1000 * sun
+		w.battery = 2.68
+	case Storm:
+		s := stormIntensity(t)
+		w.temp -= 6 * s
+		w.humidity = math.Min(100, w.humidity+40*s)
+		w.wind += 14 * s
+		w.seaLevel -= 16 * s
+		w.solar *= 1 - 0.9*s
+		w.rainPerMin = 0.25 * s * s
+		if w.rainPerMin > 0.01 {
+			w.precipType = 1
+			if s > 0.95 {
+				w.precipType = 3
+			}
+		}
+		if s > 0.5 {
+			w.strikesPerMin = 4 * (s - 0.5)
+			w.strikeDistance = math.Round(30 - 25*s)
+		}
+	case DeadBattery:
+		w.battery = 2.31 + 0.01*n(5)
+	}
+	w.gust = w.wind*1.6 + 0.5 + 0.5*math.Abs(n(6))
+	w.lull = w.wind * 0.4
+	w.uv = math.Round(w.solar/90*10) / 10
+	w.lux = w.solar * 120
+	return w
+}
+
+// lastReport returns the time of a station's latest observation at now.
+func (sc Scenario) lastReport(now time.Time) time.Time {
+	if sc == DeadBattery {
+		now = now.Add(-deadBatteryGap)
+	}
+	return now.Truncate(time.Minute)
+}
+
+// observation returns a device observation at t covering the interval
+// before it, as tempestd and the REST API report them.
+func (sc Scenario) observation(st station, t time.Time, interval time.Duration) tempest.Observation {
+	w := sc.weather(st, t)
+	minutes := interval.Minutes()
+	stationHPa := w.seaLevel / pressure.ToSeaLevel(1, w.temp, st.Elevation)
+	return tempest.Observation{
+		Timestamp:          t,
+		StationID:          st.ID,
+		DeviceID:           st.DeviceID,
+		WindLull:           round(w.lull, 2),
+		WindAvg:            round(w.wind, 2),
+		WindGust:           round(w.gust, 2),
+		WindDirection:      math.Round(w.dir),
+		WindSampleInterval: 3,
+		StationPressure:    round(stationHPa, 1),
+		AirTemperature:     round(w.temp, 1),
+		RelativeHumidity:   math.Round(w.humidity),
+		Illuminance:        math.Round(w.lux),
+		UVIndex:            w.uv,
+		SolarRadiation:     math.Round(w.solar),
+		RainAccumulation:   round(w.rainPerMin*minutes, 2),
+		PrecipitationType:  w.precipType,
+		LightningAvgDist:   w.strikeDistance,
+		LightningCount:     int(math.Round(w.strikesPerMin * minutes)),
+		Battery:            round(w.battery, 2),
+		ReportInterval:     int(minutes),
+		FeelsLike:          round(tempest.FeelsLike(w.temp, w.humidity, w.wind), 1),
+		DewPoint:           round(tempest.DewPoint(w.temp, w.humidity), 1),
+		WetBulb:            round(tempest.WetBulb(w.temp, w.humidity), 1),
+	}
+}
+
+// observations returns observations every step from start to end, stopping
+// at the station's last report.
+func (sc Scenario) observations(st station, start, end, now time.Time, step time.Duration) []tempest.Observation {
+	last := sc.lastReport(now)
+	if end.After(last) {
+		end = last
+	}
+	var obs []tempest.Observation
+	for t := start.Truncate(step); !t.After(end); t = t.Add(step) {
+		if !t.Before(start) {
+			obs = append(obs, sc.observation(st, t, step))
+		}
+	}
+	return obs
+}
+
+// current returns the station's latest observation with the daily and
+// three-hour totals the REST API adds to it.
+func (sc Scenario) current(st station, now time.Time) tempest.StationObservation {
+	at := sc.lastReport(now)
+	o := sc.observation(st, at, time.Minute)
+	w := sc.weather(st, at)
+
+	local := at.In(st.location())
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	rain := 0.0
+	for t := midnight; !t.After(at); t = t.Add(time.Minute) {
+		rain += sc.weather(st, t).rainPerMin
+	}
+	strikes, lastStrike := 0.0, time.Time{}
+	for t := at.Add(-3 * time.Hour); !t.After(at); t = t.Add(time.Minute) {
+		if s := sc.weather(st, t).strikesPerMin; s > 0 {
+			strikes += s
+			lastStrike = t
+		}
+	}
+
+	trend := "steady"
+	switch change := w.seaLevel - sc.weather(st, at.Add(-3*time.Hour)).seaLevel; {
+	case change > 1:
+		trend = "rising"
+	case change < -1:
+		trend = "falling"
+	}
+	obs := tempest.StationObservation{
+		StationID:          st.ID,
+		Timestamp:          at,
+		AirTemperature:     o.AirTemperature,
+		RelativeHumidity:   o.RelativeHumidity,
+		WindAvg:            o.WindAvg,
+		WindGust:           o.WindGust,
+		WindLull:           o.WindLull,
+		WindDirection:      o.WindDirection,
+		BarometricPressure: o.StationPressure,
+		SeaLevelPressure:   round(w.seaLevel, 1),
+		SolarRadiation:     o.SolarRadiation,
+		UV:                 o.UVIndex,
+		Brightness:         o.Illuminance,
+		FeelsLike:          o.FeelsLike,
+		DewPoint:           o.DewPoint,
+		WetBulbTemperature: o.WetBulb,
+		PrecipAccumDay:     round(rain, 2),
+		LightningCount3hr:  int(math.Round(strikes)),
+		PressureTrend:      trend,
+	}
+	if !lastStrike.IsZero() {
+		obs.LightningStrikeLastEpoch = lastStrike
+		obs.LightningStrikeLastDistance = sc.weather(st, lastStrike).strikeDistance
+	}
+	return obs
+}
+
+// forecast returns ten days and 48 hours of forecast from now.
+func (sc Scenario) forecast(st station, now time.Time) tempest.Forecast {
+	loc := st.location()
+	local := now.In(loc)
+	f := tempest.Forecast{StationID: st.ID, Latitude: st.Latitude, Longitude: st.Longitude}
+	for d := 0; d < 10; d++ {
+		date := time.Date(local.Year(), local.Month(), local.Day()+d, 0, 0, 0, 0, loc)
+		high := sc.weather(st, date.Add(15*time.Hour))
+		low := sc.weather(st, date.Add(3*time.Hour))
+		conditions, icon, chance := sc.conditions(st, date.Add(12*time.Hour), 12*time.Hour)
+		f.Daily = append(f.Daily, tempest.DailyForecast{
+			Date:         date,
+			HighTemp:     math.Round(high.temp),
+			LowTemp:      math.Round(low.temp),
+			Conditions:   conditions,
+			Icon:         icon,
+			PrecipChance: chance,
+			PrecipType:   "rain",
+			Sunrise:      date.Add(6*time.Hour + time.Duration(d)*time.Minute),
+			Sunset:       date.Add(20*time.Hour - time.Duration(d)*time.Minute),
+		})
+	}
+	hour := local.Truncate(time.Hour).Add(time.Hour)
+	for h := 0; h < 48; h++ {
+		t := hour.Add(time.Duration(h) * time.Hour)
+		w := sc.weather(st, t)
+		conditions, icon, chance := sc.conditions(st, t, time.Hour)
+		f.Hourly = append(f.Hourly, tempest.HourlyForecast{
+			Time:          t,
+			Temperature:   math.Round(w.temp),
+			FeelsLike:     math.Round(tempest.FeelsLike(w.temp, w.humidity, w.wind)),
+			Humidity:      math.Round(w.humidity),
+			WindAvg:       round(w.wind, 1),
+			WindDirection: math.Round(w.dir),
+			WindGust:      round(w.gust, 1),
+			Conditions:    conditions,
+			Icon:          icon,
+			PrecipChance:  chance,
+			PrecipType:    "rain",
+			UV:            math.Round(w.uv),
+		})
+	}
+	return f
+}
+
+// conditions describes the weather over the window centered on t.
+func (sc Scenario) conditions(st station, t time.Time, window time.Duration) (text, icon string, precipChance int) {
+	storm := 0.0
+	if sc == Storm {
+		for d := -window / 2; d <= window/2; d += 30 * time.Minute {
+			storm = math.Max(storm, stormIntensity(t.Add(d)))
+		}
+	}
+	switch {
+	case storm > 0.5:
+		return "Thunderstorms Likely", "thunderstorm", 80
+	case storm > 0:
+		return "Rain Possible", "possibly-rainy-day", 40
+	case sc == HeatWave:
+		return "Clear", "clear-day", 0
+	case noise(st.ID, t.Truncate(24*time.Hour), 7) > 0.3:
+		return "Partly Cloudy", "partly-cloudy-day", 10
+	}
+	return "Clear", "clear-day", 0
+}
+
+func round(v float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(v*p) / p
+}
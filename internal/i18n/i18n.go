@@ -0,0 +1,281 @@
+// Package i18n holds the message catalogs and number, date and time formats
+// for each supported locale.
+package i18n
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed locales/*.yaml
+var catalogFS embed.FS
+
+// Date orders.
+const (
+	MonthDayYear = "mdy"
+	DayMonthYear = "dmy"
+)
+
+// Locale is a message catalog with the number, date and time conventions
+// that go with it.
+type Locale struct {
+	// Tag is the locale's BCP 47 tag, e.g. "en" or "en-GB".
+	Tag string `yaml:"tag"`
+	// Name is the locale's name in its own language.
+	Name string `yaml:"name"`
+	// Parent is the locale whose messages and formats fill in anything this
+	// one leaves out; every locale but English falls back to English.
+	Parent string `yaml:"parent"`
+	// Decimal is the decimal separator.
+	Decimal string `yaml:"decimal"`
+	// Hour24 selects 24-hour time instead of 12-hour time with am/pm.
+	Hour24 *bool `yaml:"hour24"`
+	// DateOrder is MonthDayYear or DayMonthYear.
+	DateOrder string `yaml:"date_order"`
+	// DateSep separates the parts of a numeric date.
+	DateSep  string   `yaml:"date_sep"`
+	Weekdays []string `yaml:"weekdays"` // abbreviated, Sunday first
+	Months   []string `yaml:"months"`   // abbreviated, January first
+	// Compass is the 16 points of the compass, clockwise from north.
+	Compass  []string          `yaml:"compass"`
+	Messages map[string]string `yaml:"messages"`
+}
+
+// catalogs holds every built-in locale by tag. They are all loaded up front
+// so that lookups need no locking.
+var catalogs = mustLoadAll()
+
+// English is the built-in en locale, used when no other is selected.
+var English = catalogs["en"]
+
+// aliases maps regional tags to the locale with their conventions.
+var aliases = map[string]string{
+	"en-au": "en-GB", "en-ie": "en-GB", "en-nz": "en-GB", "en-za": "en-GB",
+}
+
+func mustLoadAll() map[string]*Locale {
+	loaded := map[string]*Locale{}
+	for _, tag := range Tags() {
+		if _, err := load(tag, loaded); err != nil {
+			panic(err)
+		}
+	}
+	return loaded
+}
+
+// load parses a catalog into loaded, filling it in from its parent.
+func load(tag string, loaded map[string]*Locale) (*Locale, error) {
+	if l, ok := loaded[tag]; ok {
+		return l, nil
+	}
+	data, err := catalogFS.ReadFile("locales/" + tag + ".yaml")
+	if err != nil {
+		return nil, err
+	}
+	var l Locale
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&l); err != nil {
+		return nil, fmt.Errorf("locale %s: %w", tag, err)
+	}
+	if l.Tag != tag {
+		return nil, fmt.Errorf("locale %s: file declares tag %q", tag, l.Tag)
+	}
+	parent := l.Parent
+	if parent == "" && tag != "en" {
+		parent = "en"
+	}
+	if parent != "" {
+		p, err := load(parent, loaded)
+		if err != nil {
+			return nil, fmt.Errorf("locale %s: parent: %w", tag, err)
+		}
+		l.inherit(p)
+	}
+	if err := l.validate(); err != nil {
+		return nil, fmt.Errorf("locale %s: %w", tag, err)
+	}
+	loaded[tag] = &l
+	return &l, nil
+}
+
+func (l *Locale) validate() error {
+	switch {
+	case len(l.Weekdays) != 7:
+		return fmt.Errorf("weekdays has %d names, want 7", len(l.Weekdays))
+	case len(l.Months) != 12:
+		return fmt.Errorf("months has %d names, want 12", len(l.Months))
+	case len(l.Compass) != 16:
+		return fmt.Errorf("compass has %d points, want 16", len(l.Compass))
+	case l.DateOrder != MonthDayYear && l.DateOrder != DayMonthYear:
+		return fmt.Errorf("date_order must be %s or %s, got %q", MonthDayYear, DayMonthYear, l.DateOrder)
+	case l.Decimal == "" || l.DateSep == "" || l.Hour24 == nil:
+		return fmt.Errorf("decimal, date_sep and hour24 must be set")
+	}
+	return nil
+}
+
+func (l *Locale) inherit(p *Locale) {
+	if l.Decimal == "" {
+		l.Decimal = p.Decimal
+	}
+	if l.Hour24 == nil {
+		l.Hour24 = p.Hour24
+	}
+	if l.DateOrder == "" {
+		l.DateOrder = p.DateOrder
+	}
+	if l.DateSep == "" {
+		l.DateSep = p.DateSep
+	}
+	if len(l.Weekdays) == 0 {
+		l.Weekdays = p.Weekdays
+	}
+	if len(l.Months) == 0 {
+		l.Months = p.Months
+	}
+	if len(l.Compass) == 0 {
+		l.Compass = p.Compass
+	}
+	if l.Messages == nil {
+		l.Messages = map[string]string{}
+	}
+	for k, v := range p.Messages {
+		if _, ok := l.Messages[k]; !ok {
+			l.Messages[k] = v
+		}
+	}
+}
+
+// Tags returns the tags of every built-in locale, sorted.
+func Tags() []string {
+	entries, _ := fs.ReadDir(catalogFS, "locales")
+	tags := make([]string, 0, len(entries))
+	for _, e := range entries {
+		tags = append(tags, strings.TrimSuffix(e.Name(), ".yaml"))
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// normalize turns a POSIX locale such as "de_DE.UTF-8@euro" into a
+// lower-case tag such as "de-de".
+func normalize(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, ".@"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.ToLower(strings.ReplaceAll(s, "_", "-"))
+}
+
+// Lookup returns the locale for a tag such as "de", "en-GB" or "fr_FR.UTF-8".
+// A tag with an unknown region falls back to its language.
+func Lookup(tag string) (*Locale, error) {
+	norm := normalize(tag)
+	switch norm {
+	case "", "c", "posix":
+		return English, nil
+	}
+	if a, ok := aliases[norm]; ok {
+		norm = strings.ToLower(a)
+	}
+	for t, l := range catalogs {
+		if strings.ToLower(t) == norm {
+			return l, nil
+		}
+	}
+	lang, _, _ := strings.Cut(norm, "-")
+	if l, ok := catalogs[lang]; ok {
+		return l, nil
+	}
+	return nil, fmt.Errorf("unsupported language %q; use one of %s", tag, strings.Join(Tags(), ", "))
+}
+
+// FromEnv returns the locale named by LC_ALL, LC_MESSAGES or LANG, in that
+// order, or English if none is set to a supported locale.
+func FromEnv() *Locale {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			if l, err := Lookup(v); err == nil {
+				return l
+			}
+			return English
+		}
+	}
+	return English
+}
+
+// T returns the message for key, formatted with args if any. An unknown key
+// is returned as is so that a missing translation is visible but harmless.
+func (l *Locale) T(key string, args ...any) string {
+	msg, ok := l.Messages[key]
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Number formats v with the given number of decimals and l's separator.
+func (l *Locale) Number(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	if s == "-"+strconv.FormatFloat(0, 'f', decimals, 64) {
+		s = s[1:]
+	}
+	if l.Decimal != "." {
+		s = strings.Replace(s, ".", l.Decimal, 1)
+	}
+	return s
+}
+
+// CompassPoint returns the 16-point compass direction for degrees.
+func (l *Locale) CompassPoint(degrees float64) string {
+	degrees = math.Mod(degrees, 360)
+	if degrees < 0 {
+		degrees += 360
+	}
+	return l.Compass[int(math.Round(degrees/22.5))%16]
+}
+
+// Time formats the time of day, e.g. "3:04pm" or "15:04".
+func (l *Locale) Time(t time.Time) string {
+	if *l.Hour24 {
+		return t.Format("15:04")
+	}
+	return t.Format("3:04pm")
+}
+
+// DayLabel formats a short day heading, e.g. "Mon Jan 2" or "Mo 2 Jan".
+func (l *Locale) DayLabel(t time.Time) string {
+	wd, mon := l.Weekdays[t.Weekday()], l.Months[t.Month()-1]
+	if l.DateOrder == MonthDayYear {
+		return fmt.Sprintf("%s %s %d", wd, mon, t.Day())
+	}
+	return fmt.Sprintf("%s %d %s", wd, t.Day(), mon)
+}
+
+// ShortDate formats the month and day numerically, e.g. "01/02" or "02.01".
+func (l *Locale) ShortDate(t time.Time) string {
+	m, d := fmt.Sprintf("%02d", int(t.Month())), fmt.Sprintf("%02d", t.Day())
+	if l.DateOrder == DayMonthYear {
+		return d + l.DateSep + m
+	}
+	return m + l.DateSep + d
+}
+
+// DateTime formats a short date and time of day, e.g. "01/02 3:04pm".
+func (l *Locale) DateTime(t time.Time) string {
+	return l.ShortDate(t) + " " + l.Time(t)
+}
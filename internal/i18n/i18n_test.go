@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLookup(t *testing.T) {
+	tests := map[string]string{
+		"":            "en",
+		"C":           "en",
+		"en_US.UTF-8": "en",
+		"en-GB":       "en-GB",
+		"en_gb":       "en-GB",
+		"en_AU.UTF-8": "en-GB",
+		"de_AT.UTF-8": "de",
+		"fr_CA":       "fr",
+		"es":          "es",
+	}
+	for in, want := range tests {
+		l, err := Lookup(in)
+		if err != nil || l.Tag != want {
+			t.Errorf("Lookup(%q) = %v, %v; want %s", in, l, err, want)
+		}
+	}
+	if _, err := Lookup("tlh"); err == nil {
+		t.Error("expected an error for an unsupported language")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	if got := FromEnv().Tag; got != "de" {
+		t.Errorf("LANG=de_DE: got %s", got)
+	}
+	t.Setenv("LC_ALL", "fr_FR.UTF-8")
+	if got := FromEnv().Tag; got != "fr" {
+		t.Errorf("LC_ALL wins over LANG: got %s", got)
+	}
+	t.Setenv("LC_ALL", "ja_JP.UTF-8")
+	if got := FromEnv().Tag; got != "en" {
+		t.Errorf("unsupported locale: got %s, want en", got)
+	}
+}
+
+// TestCatalogsMatchEnglish checks every catalog against en: no unknown keys,
+// and the same format verbs in the same order.
+func TestCatalogsMatchEnglish(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	raw := func(tag string) map[string]string {
+		data, err := catalogFS.ReadFile("locales/" + tag + ".yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var l Locale
+		if err := yaml.Unmarshal(data, &l); err != nil {
+			t.Fatal(err)
+		}
+		return l.Messages
+	}
+	en := raw("en")
+	for _, tag := range Tags() {
+		for key, msg := range raw(tag) {
+			want, ok := en[key]
+			if !ok {
+				t.Errorf("%s: unknown message %q", tag, key)
+				continue
+			}
+			if got, want := verbs.FindAllString(msg, -1), verbs.FindAllString(want, -1); !slices.Equal(got, want) {
+				t.Errorf("%s: %s has verbs %v, want %v", tag, key, got, want)
+			}
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	de, _ := Lookup("de")
+	gb, _ := Lookup("en-GB")
+	ts := time.Date(2024, time.March, 5, 15, 4, 0, 0, time.UTC) // a Tuesday
+
+	tests := []struct {
+		name, got, want string
+	}{
+		{"en number", English.Number(21.55, 1), "21.6"},
+		{"de number", de.Number(21.55, 1), "21,6"},
+		{"negative zero", English.Number(-0.01, 1), "0.0"},
+		{"en time", English.Time(ts), "3:04pm"},
+		{"gb time", gb.Time(ts), "15:04"},
+		{"en day", English.DayLabel(ts), "Tue Mar 5"},
+		{"gb day", gb.DayLabel(ts), "Tue 5 Mar"},
+		{"de day", de.DayLabel(ts), "Di 5 Mär"},
+		{"en datetime", English.DateTime(ts), "03/05 3:04pm"},
+		{"de datetime", de.DateTime(ts), "05.03 15:04"},
+		{"en compass", English.CompassPoint(67.5), "ENE"},
+		{"de compass", de.CompassPoint(90), "O"},
+		{"wraps", English.CompassPoint(-10), "N"},
+		{"message", de.T("updated_ago", "5 min"), "Aktualisiert vor 5 min"},
+		{"inherited", gb.T("feels_like"), "Feels like"},
+		{"missing", English.T("no_such_key"), "no_such_key"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
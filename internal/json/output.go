@@ -15,3 +15,12 @@ func Write(w io.Writer, v any) error {
 	}
 	return nil
 }
+
+// WriteCompact encodes v as single-line JSON to w, for consumers such as
+// status bars that read one object per line.
+func WriteCompact(w io.Writer, v any) error {
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+	return nil
+}
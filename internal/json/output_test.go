@@ -58,3 +58,17 @@ func TestWrite(t *testing.T) {
 		t.Errorf("output not indented: %s", out)
 	}
 }
+
+func TestWriteCompact(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCompact(&buf, map[string]any{"text": "22.5°C", "class": []string{"normal"}}); err != nil {
+		t.Fatalf("WriteCompact() error: %v", err)
+	}
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 || !strings.HasSuffix(out, "\n") {
+		t.Errorf("output should be a single line: %q", out)
+	}
+	if !strings.Contains(out, `"text":"22.5°C"`) {
+		t.Errorf("output missing text field: %s", out)
+	}
+}
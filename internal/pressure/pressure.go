@@ -0,0 +1,87 @@
+// Package pressure reduces station pressure, measured at the sensor's
+// elevation, to sea-level pressure and the altimeter setting.
+package pressure
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Reference is what a pressure value is relative to.
+type Reference string
+
+const (
+	// Station is the pressure measured at the station's elevation.
+	Station Reference = "station"
+	// SeaLevel is station pressure reduced to sea level using the air
+	// temperature, as weather maps show it.
+	SeaLevel Reference = "sea_level"
+	// Altimeter is station pressure reduced through the standard
+	// atmosphere, as aviation reports show it.
+	Altimeter Reference = "altimeter"
+)
+
+// ParseReference parses a reference name; "sea-level" and "slp" are
+// accepted for SeaLevel.
+func ParseReference(s string) (Reference, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "station", "stn":
+		return Station, nil
+	case "sea_level", "sea-level", "slp", "":
+		return SeaLevel, nil
+	case "altimeter", "alt":
+		return Altimeter, nil
+	}
+	return "", fmt.Errorf("pressure must be station, sea-level or altimeter, got %q", s)
+}
+
+// Standard atmosphere constants.
+const (
+	lapseRate     = 0.0065 // K/m
+	standardTempK = 288.15
+	standardHPa   = 1013.25
+	// exponent is g·M/(R·L) for dry air.
+	exponent = 5.25588
+)
+
+// ToSeaLevel reduces stationHPa at elevationM to sea level, using tempC as
+// the air temperature at the station.
+func ToSeaLevel(stationHPa, tempC, elevationM float64) float64 {
+	if stationHPa <= 0 {
+		return stationHPa
+	}
+	tK := tempC + 273.15
+	return stationHPa * math.Pow(1-lapseRate*elevationM/(tK+lapseRate*elevationM), -exponent)
+}
+
+// ToAltimeter returns the altimeter setting for stationHPa at elevationM,
+// following the NWS formula. It ignores the actual temperature.
+func ToAltimeter(stationHPa, elevationM float64) float64 {
+	if stationHPa <= 0 {
+		return stationHPa
+	}
+	const n = 1 / exponent
+	p := stationHPa - 0.3
+	k := math.Pow(standardHPa, n) * lapseRate / standardTempK
+	return p * math.Pow(1+k*elevationM/math.Pow(p, n), 1/n)
+}
+
+// Reducer converts station pressure to a Reference at a known elevation.
+type Reducer struct {
+	Reference Reference
+	// ElevationM is the station's elevation in meters.
+	ElevationM float64
+}
+
+// Reduce returns stationHPa relative to r's Reference; tempC is the air
+// temperature at the same time.
+func (r Reducer) Reduce(stationHPa, tempC float64) float64 {
+	switch r.Reference {
+	case SeaLevel:
+		return ToSeaLevel(stationHPa, tempC, r.ElevationM)
+	case Altimeter:
+		return ToAltimeter(stationHPa, r.ElevationM)
+	}
+	return stationHPa
+}
@@ -0,0 +1,62 @@
+package pressure
+
+import (
+	"math"
+	"testing"
+)
+
+func TestToSeaLevel(t *testing.T) {
+	tests := []struct {
+		name                string
+		station, temp, elev float64
+		want                float64
+	}{
+		{"sea level", 1013.25, 15, 0, 1013.25},
+		{"1500 m", 845.6, 15, 1500, 1007.0},
+		{"500 m cold", 950, -10, 500, 1013.3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToSeaLevel(tt.station, tt.temp, tt.elev); math.Abs(got-tt.want) > 1 {
+				t.Errorf("ToSeaLevel(%v, %v, %v) = %.1f, want %.1f", tt.station, tt.temp, tt.elev, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToAltimeter(t *testing.T) {
+	// The standard atmosphere at 1500 m is 845.6 hPa, so the altimeter
+	// setting is standard pressure.
+	if got := ToAltimeter(845.6, 1500); math.Abs(got-1013.25) > 0.5 {
+		t.Errorf("ToAltimeter = %.2f, want about 1013.25", got)
+	}
+	if got := ToAltimeter(0, 1500); got != 0 {
+		t.Errorf("ToAltimeter of a missing reading = %v, want 0", got)
+	}
+}
+
+func TestReducer(t *testing.T) {
+	station := Reducer{Reference: Station, ElevationM: 1500}
+	if got := station.Reduce(845, 20); got != 845 {
+		t.Errorf("station Reduce = %v, want unchanged", got)
+	}
+	slp := Reducer{Reference: SeaLevel, ElevationM: 1500}
+	if got := slp.Reduce(845, 20); got < 1000 || got > 1010 {
+		t.Errorf("sea-level Reduce = %.1f, want about 1004", got)
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	tests := map[string]Reference{
+		"station": Station, "sea-level": SeaLevel, "SLP": SeaLevel, "": SeaLevel, "altimeter": Altimeter,
+	}
+	for in, want := range tests {
+		got, err := ParseReference(in)
+		if err != nil || got != want {
+			t.Errorf("ParseReference(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseReference("qnh"); err == nil {
+		t.Error("expected an error for an unknown reference")
+	}
+}
@@ -0,0 +1,266 @@
+// Package units converts observations, which are always metric, to the
+// units a user prefers for each kind of quantity.
+package units
+
+import (
+	"fmt"
+	"strings"
+
+	tempest "github.com/chadmayfield/tempest-go"
+)
+
+// Temperature units.
+const (
+	Celsius    = "c"
+	Fahrenheit = "f"
+)
+
+// Wind speed units.
+const (
+	MetersPerSecond   = "m/s"
+	KilometersPerHour = "km/h"
+	MilesPerHour      = "mph"
+	Knots             = "kn"
+	Beaufort          = "bft"
+)
+
+// Pressure units.
+const (
+	Hectopascal          = "hpa"
+	Millibar             = "mb"
+	Kilopascal           = "kpa"
+	InchesOfMercury      = "inhg"
+	MillimetersOfMercury = "mmhg"
+)
+
+// Precipitation and distance units.
+const (
+	Millimeters = "mm"
+	Inches      = "in"
+	Kilometers  = "km"
+	Miles       = "mi"
+)
+
+// Unit systems.
+const (
+	Metric   = "metric"
+	Imperial = "imperial"
+	// Custom labels a Set that is neither Metric nor Imperial.
+	Custom = "custom"
+)
+
+// Set is the unit for each kind of quantity. In config an empty field means
+// "not overridden"; a resolved Set has every field set.
+type Set struct {
+	Temperature   string `mapstructure:"temperature" yaml:"temperature,omitempty" json:"temperature"`
+	Wind          string `mapstructure:"wind" yaml:"wind,omitempty" json:"wind"`
+	Pressure      string `mapstructure:"pressure" yaml:"pressure,omitempty" json:"pressure"`
+	Precipitation string `mapstructure:"precipitation" yaml:"precipitation,omitempty" json:"precipitation"`
+	Distance      string `mapstructure:"distance" yaml:"distance,omitempty" json:"distance"`
+}
+
+// MetricSet is °C, m/s, hPa, mm and km.
+var MetricSet = Set{Celsius, MetersPerSecond, Hectopascal, Millimeters, Kilometers}
+
+// ImperialSet is °F, mph, inHg, in and mi.
+var ImperialSet = Set{Fahrenheit, MilesPerHour, InchesOfMercury, Inches, Miles}
+
+// aliases maps accepted spellings to canonical units, per quantity.
+var aliases = map[string]map[string]string{
+	"temperature": {
+		"c": Celsius, "°c": Celsius, "celsius": Celsius,
+		"f": Fahrenheit, "°f": Fahrenheit, "fahrenheit": Fahrenheit,
+	},
+	"wind": {
+		"m/s": MetersPerSecond, "mps": MetersPerSecond,
+		"km/h": KilometersPerHour, "kmh": KilometersPerHour, "kph": KilometersPerHour,
+		"mph": MilesPerHour,
+		"kn":  Knots, "kt": Knots, "kts": Knots, "knots": Knots,
+		"bft": Beaufort, "beaufort": Beaufort,
+	},
+	"pressure": {
+		"hpa": Hectopascal, "mb": Millibar, "mbar": Millibar, "kpa": Kilopascal,
+		"inhg": InchesOfMercury, "mmhg": MillimetersOfMercury,
+	},
+	"precipitation": {
+		"mm": Millimeters, "in": Inches, "inch": Inches, "inches": Inches,
+	},
+	"distance": {
+		"km": Kilometers, "mi": Miles, "mile": Miles, "miles": Miles,
+	},
+}
+
+// Choices returns the canonical units accepted for quantity, for help text.
+func Choices(quantity string) []string {
+	switch quantity {
+	case "temperature":
+		return []string{Celsius, Fahrenheit}
+	case "wind":
+		return []string{MetersPerSecond, KilometersPerHour, MilesPerHour, Knots, Beaufort}
+	case "pressure":
+		return []string{Hectopascal, Millibar, Kilopascal, InchesOfMercury, MillimetersOfMercury}
+	case "precipitation":
+		return []string{Millimeters, Inches}
+	case "distance":
+		return []string{Kilometers, Miles}
+	}
+	return nil
+}
+
+// System returns the Set for a unit system name; empty means metric.
+func System(name string) (Set, error) {
+	switch strings.ToLower(name) {
+	case "", Metric:
+		return MetricSet, nil
+	case Imperial:
+		return ImperialSet, nil
+	}
+	return Set{}, fmt.Errorf("units must be 'metric' or 'imperial', got %q", name)
+}
+
+// fields returns pointers to s's fields keyed by quantity name.
+func (s *Set) fields() []struct {
+	name string
+	p    *string
+} {
+	return []struct {
+		name string
+		p    *string
+	}{
+		{"temperature", &s.Temperature},
+		{"wind", &s.Wind},
+		{"pressure", &s.Pressure},
+		{"precipitation", &s.Precipitation},
+		{"distance", &s.Distance},
+	}
+}
+
+// Normalize returns s with every set field in canonical form, or an error
+// naming the first unknown unit.
+func (s Set) Normalize() (Set, error) {
+	for _, f := range s.fields() {
+		if *f.p == "" {
+			continue
+		}
+		canon, ok := aliases[f.name][strings.ToLower(strings.TrimSpace(*f.p))]
+		if !ok {
+			return Set{}, fmt.Errorf("unknown %s unit %q; use one of %s", f.name, *f.p, strings.Join(Choices(f.name), ", "))
+		}
+		*f.p = canon
+	}
+	return s, nil
+}
+
+// With returns s with the fields set in o replacing its own.
+func (s Set) With(o Set) Set {
+	of := o.fields()
+	for i, f := range s.fields() {
+		if v := *of[i].p; v != "" {
+			*f.p = v
+		}
+	}
+	return s
+}
+
+// System names the system s matches: Metric, Imperial or Custom.
+func (s Set) System() string {
+	switch s {
+	case MetricSet:
+		return Metric
+	case ImperialSet:
+		return Imperial
+	}
+	return Custom
+}
+
+// Temp converts a temperature from °C.
+func (s Set) Temp(c float64) float64 {
+	if s.Temperature == Fahrenheit {
+		return tempest.CelsiusToFahrenheit(c)
+	}
+	return c
+}
+
+// Speed converts a wind speed from m/s. Beaufort returns the force number.
+func (s Set) Speed(mps float64) float64 {
+	switch s.Wind {
+	case KilometersPerHour:
+		return mps * 3.6
+	case MilesPerHour:
+		return tempest.MpsToMph(mps)
+	case Knots:
+		return mps * 3600 / 1852
+	case Beaufort:
+		return float64(BeaufortForce(mps))
+	}
+	return mps
+}
+
+// Press converts a pressure from hPa.
+func (s Set) Press(hpa float64) float64 {
+	switch s.Pressure {
+	case Kilopascal:
+		return hpa / 10
+	case InchesOfMercury:
+		return tempest.HpaToInhg(hpa)
+	case MillimetersOfMercury:
+		return hpa * 0.750061683
+	}
+	return hpa
+}
+
+// Precip converts a precipitation amount from mm.
+func (s Set) Precip(mm float64) float64 {
+	if s.Precipitation == Inches {
+		return tempest.MmToInches(mm)
+	}
+	return mm
+}
+
+// Dist converts a distance from km.
+func (s Set) Dist(km float64) float64 {
+	if s.Distance == Miles {
+		return tempest.KmToMiles(km)
+	}
+	return km
+}
+
+// beaufortLimits are the upper bounds in m/s of forces 0 to 11.
+var beaufortLimits = []float64{0.5, 1.6, 3.4, 5.5, 8.0, 10.8, 13.9, 17.2, 20.8, 24.5, 28.5, 32.7}
+
+// BeaufortForce returns the Beaufort force (0-12) for a wind speed in m/s.
+func BeaufortForce(mps float64) int {
+	for force, limit := range beaufortLimits {
+		if mps < limit {
+			return force
+		}
+	}
+	return len(beaufortLimits)
+}
+
+// symbols are the display symbols of the canonical units.
+var symbols = map[string]string{
+	Celsius: "°C", Fahrenheit: "°F",
+	MetersPerSecond: "m/s", KilometersPerHour: "km/h", MilesPerHour: "mph", Knots: "kn", Beaufort: "Bft",
+	Hectopascal: "hPa", Millibar: "mb", Kilopascal: "kPa", InchesOfMercury: "inHg", MillimetersOfMercury: "mmHg",
+	Millimeters: "mm", Inches: "in", Kilometers: "km", Miles: "mi",
+}
+
+// Symbol returns the display symbol for a canonical unit, e.g. "inHg".
+func Symbol(unit string) string {
+	if s, ok := symbols[unit]; ok {
+		return s
+	}
+	return unit
+}
+
+// Decimals returns how many decimal places values in unit are shown with.
+func Decimals(unit string) int {
+	switch unit {
+	case Beaufort:
+		return 0
+	case Kilopascal, InchesOfMercury, Inches:
+		return 2
+	}
+	return 1
+}
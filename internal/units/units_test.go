@@ -0,0 +1,77 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	got, err := Set{Temperature: "°F", Wind: "Knots", Pressure: "mbar", Precipitation: "", Distance: "Miles"}.Normalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Set{Temperature: Fahrenheit, Wind: Knots, Pressure: Millibar, Distance: Miles}
+	if got != want {
+		t.Errorf("Normalize() = %+v, want %+v", got, want)
+	}
+
+	if _, err := (Set{Wind: "furlongs/fortnight"}).Normalize(); err == nil {
+		t.Error("expected an error for an unknown wind unit")
+	}
+	if _, err := (Set{Temperature: "mph"}).Normalize(); err == nil {
+		t.Error("expected an error for a wind unit used as a temperature unit")
+	}
+}
+
+func TestWithAndSystem(t *testing.T) {
+	if MetricSet.System() != Metric || ImperialSet.System() != Imperial {
+		t.Error("the preset sets should name their systems")
+	}
+	mixed := MetricSet.With(Set{Wind: KilometersPerHour, Pressure: Millibar})
+	if mixed.Temperature != Celsius || mixed.Wind != KilometersPerHour || mixed.Pressure != Millibar {
+		t.Errorf("With() = %+v", mixed)
+	}
+	if mixed.System() != Custom {
+		t.Errorf("System() = %q, want custom", mixed.System())
+	}
+	if _, err := System("kelvin"); err == nil {
+		t.Error("expected an error for an unknown system")
+	}
+}
+
+func TestConversions(t *testing.T) {
+	near := func(got, want float64) bool { return math.Abs(got-want) < 0.01 }
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"f", Set{Temperature: Fahrenheit}.Temp(100), 212},
+		{"km/h", Set{Wind: KilometersPerHour}.Speed(10), 36},
+		{"kn", Set{Wind: Knots}.Speed(10), 19.44},
+		{"bft", Set{Wind: Beaufort}.Speed(10), 5},
+		{"kpa", Set{Pressure: Kilopascal}.Press(1013.25), 101.325},
+		{"mmhg", Set{Pressure: MillimetersOfMercury}.Press(1013.25), 760},
+		{"mb", Set{Pressure: Millibar}.Press(1013.25), 1013.25},
+		{"in", Set{Precipitation: Inches}.Precip(25.4), 1},
+		{"mi", Set{Distance: Miles}.Dist(1.609344), 1},
+	}
+	for _, tt := range tests {
+		if !near(tt.got, tt.want) {
+			t.Errorf("%s: got %.3f, want %.3f", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestBeaufortForce(t *testing.T) {
+	tests := []struct {
+		mps  float64
+		want int
+	}{
+		{0, 0}, {0.5, 1}, {3.3, 2}, {10.8, 6}, {17.1, 7}, {32.6, 11}, {40, 12},
+	}
+	for _, tt := range tests {
+		if got := BeaufortForce(tt.mps); got != tt.want {
+			t.Errorf("BeaufortForce(%.1f) = %d, want %d", tt.mps, got, tt.want)
+		}
+	}
+}
@@ -25,9 +25,9 @@ func main() {
 		// Check if the error was caused by context cancellation (SIGINT)
 		if ctx.Err() != nil {
 			fmt.Fprintln(os.Stderr, "Interrupted")
-			os.Exit(130)
+			os.Exit(cmd.ExitInterrupted)
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		cmd.PrintError(err)
+		os.Exit(cmd.ExitCode(err))
 	}
 }